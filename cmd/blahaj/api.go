@@ -1,33 +1,49 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
 	"github.com/cyclimse/fediverse-blahaj/internal/api/controller"
 	api "github.com/cyclimse/fediverse-blahaj/internal/api/v1"
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
 	"github.com/cyclimse/fediverse-blahaj/internal/business"
 	"github.com/cyclimse/fediverse-blahaj/internal/config"
+	"github.com/cyclimse/fediverse-blahaj/internal/metrics"
 )
 
 type APICmd struct {
 	Port int `help:"Port to listen on." default:"8080" env:"PORT"`
+	// the metrics reveal the internals of the API, eg: the database pool, they are not served with it
+	MetricsPort int `help:"Port to serve the Prometheus metrics on, disabled if zero." default:"9090" env:"METRICS_PORT"`
 }
 
 func (cmd *APICmd) Run(cmdContext *Context) error {
 	cfg := cmdContext.Config
 	cfg.SetDevelopmentDefaults()
 
-	dbpool, err := pgxpool.New(cmdContext.Ctx, cfg.PgConn)
+	dbpool, err := newPool(cmdContext.Ctx, cfg.PgConn)
 	if err != nil {
 		return err
 	}
 	defer dbpool.Close()
 
-	b := business.New(dbpool)
+	bl := blocklist.New()
+	b := business.New(dbpool, bl)
+
+	prometheus.MustRegister(metrics.NewPoolCollector(dbpool))
+
+	if err := b.LoadBlocklist(cmdContext.Ctx); err != nil {
+		return err
+	}
+	go b.WatchBlocklist(cmdContext.Ctx, cfg.BlocklistRefreshInterval)
 
 	e := echo.New()
 
@@ -49,6 +65,7 @@ func (cmd *APICmd) Run(cmdContext *Context) error {
 		e.HideBanner = true
 	}
 
+	e.Use(otelecho.Middleware("blahaj-api"))
 	e.Use(middleware.Timeout())
 	e.Use(middleware.Logger())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
@@ -57,7 +74,28 @@ func (cmd *APICmd) Run(cmdContext *Context) error {
 		AllowOrigins: []string{cfg.FrontendURL},
 	}))
 
+	metricsMiddleware, err := controller.MetricsMiddleware("/api/v1")
+	if err != nil {
+		return err
+	}
+	e.Use(metricsMiddleware)
+
 	api.RegisterHandlersWithBaseURL(e, controller.NewAPIController(b), "/api/v1")
 
+	if cmd.MetricsPort != 0 {
+		go cmd.serveMetrics()
+	}
+
 	return e.Start(fmt.Sprintf(":%d", cmd.Port))
 }
+
+// serveMetrics serves the Prometheus metrics on their own port, to keep them private.
+func (cmd *APICmd) serveMetrics() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	err := http.ListenAndServe(fmt.Sprintf(":%d", cmd.MetricsPort), mux)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("failed to serve the metrics", "port", cmd.MetricsPort, "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"log/slog"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
+	"github.com/cyclimse/fediverse-blahaj/internal/business"
+)
+
+type BlocklistCmd struct {
+	Import BlocklistImportCmd `cmd:"" help:"Import a blocklist into the database."`
+}
+
+type BlocklistImportCmd struct {
+	Path      string        `arg:"" help:"Path to the list to import." type:"existingfile"`
+	Format    string        `help:"Format of the list. Guessed from the file extension if not set." enum:",mastodon,text" default:""`
+	Source    string        `help:"Source of the entries. Defaults to the file name."`
+	ExpiresIn time.Duration `help:"Expire the entries after this duration. Never expire if not set."`
+}
+
+func (cmd *BlocklistImportCmd) Run(cmdContext *Context) error {
+	cfg := cmdContext.Config
+	cfg.SetDevelopmentDefaults()
+
+	format := blocklist.Format(cmd.Format)
+	if format == "" {
+		// Mastodon exports its domain blocks as CSV
+		format = blocklist.FormatText
+		if strings.EqualFold(filepath.Ext(cmd.Path), ".csv") {
+			format = blocklist.FormatMastodon
+		}
+	}
+
+	source := cmd.Source
+	if source == "" {
+		source = filepath.Base(cmd.Path)
+	}
+
+	var expiresAt *time.Time
+	if cmd.ExpiresIn > 0 {
+		t := time.Now().Add(cmd.ExpiresIn)
+		expiresAt = &t
+	}
+
+	f, err := os.Open(cmd.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := blocklist.Parse(f, format)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", cmd.Path, err)
+	}
+
+	dbpool, err := newPool(cmdContext.Ctx, cfg.PgConn)
+	if err != nil {
+		return err
+	}
+	defer dbpool.Close()
+
+	b := business.New(dbpool, blocklist.New())
+
+	imported, skipped, err := b.ImportBlocklist(cmdContext.Ctx, entries, source, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(cmdContext.Ctx, "imported blocklist", "source", source, "format", format, "imported", imported, "skipped", skipped)
+
+	return nil
+}
@@ -2,32 +2,55 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"sync/atomic"
 	"time"
 
 	"log/slog"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
 	"github.com/cyclimse/fediverse-blahaj/internal/business"
 	"github.com/cyclimse/fediverse-blahaj/internal/config"
+	"github.com/cyclimse/fediverse-blahaj/internal/metrics"
 	"github.com/cyclimse/fediverse-blahaj/internal/models"
 	"github.com/cyclimse/fediverse-blahaj/internal/orchestrator"
+	"github.com/cyclimse/fediverse-blahaj/internal/sink"
+	"github.com/cyclimse/fediverse-blahaj/internal/spool"
 )
 
 const (
 	MaximumConcurrentCrawls = 100
+	SeedCount               = 50
+
+	// finishRunTimeout bounds the time spent recording the end of a crawl run
+	finishRunTimeout = 10 * time.Second
+	// rollupTimeout bounds the time spent computing the daily aggregates and the uptime at the end of a crawl run
+	rollupTimeout = 5 * time.Minute
 )
 
 type CrawlCmd struct {
 	// To be set to the Container Timeout in production
-	Duration     time.Duration `help:"Duration of the crawl." default:"5m" env:"CRAWL_DURATION"`
-	CrawlerCount int           `help:"Number of crawlers." default:"2" env:"CRAWLER_COUNT"`
+	Duration      time.Duration `help:"Duration of the crawl." default:"5m" env:"CRAWL_DURATION"`
+	CrawlerCount  int           `help:"Number of crawlers." default:"2" env:"CRAWLER_COUNT"`
+	QueueCapacity int           `help:"Number of domains waiting to be crawled above which discovered domains are left for a later run." default:"10000" env:"CRAWL_QUEUE_CAPACITY"`
+	MaxPeers      int           `help:"Maximum number of peers kept per crawl, 0 for unlimited." default:"20000" env:"CRAWL_MAX_PEERS"`
+	// The container timeout must leave room for the grace period
+	DrainTimeout time.Duration `help:"Grace period given to the crawls in progress once the duration is reached." default:"30s" env:"CRAWL_DRAIN_TIMEOUT"`
+
+	Sinks []string `help:"Destinations of the crawls: postgres, stdout or ndjson:<path>. Only postgres requires a database." name:"sink" default:"postgres" env:"CRAWL_SINKS"`
+	Seeds []string `help:"Domains to start the crawl from. Taken from the database, or a built-in list without it, if not set." name:"seed" env:"CRAWL_SEEDS"`
+
+	SpoolPath string `help:"Path to the spool receiving the crawls that cannot be written to the database. Disabled if empty." default:"blahaj-spool.ndjson" env:"CRAWL_SPOOL_PATH"`
 
 	EntryPointServerPort int `help:"Port to listen on for the entry point server." default:"8081" env:"PORT"`
+
+	Schedule  ScheduleFlags `embed:"" prefix:"schedule-"`
+	Incidents IncidentFlags `embed:"" prefix:"incident-"`
 }
 
 func (cmd *CrawlCmd) Run(cmdContext *Context) error {
@@ -45,31 +68,154 @@ func (cmd *CrawlCmd) RunCrawl(cmdContext *Context) error {
 	cfg := cmdContext.Config
 	cfg.SetDevelopmentDefaults()
 
-	dbpool, err := pgxpool.New(cmdContext.Ctx, cfg.PgConn)
+	specs, err := cmd.parseSinks()
 	if err != nil {
 		return err
 	}
-	defer dbpool.Close()
 
-	b := business.New(dbpool)
+	// without the postgres sink, the crawl runs without a database:
+	// the default blocklist is used and the run is not recorded
+	var b *business.Business
+	bl := blocklist.Default()
+	seeds := cmd.Seeds
+	run := models.CrawlRun{}
+
+	if slices.ContainsFunc(specs, func(s sink.Spec) bool { return s.Kind == sink.KindPostgres }) {
+		dbpool, err := newPool(cmdContext.Ctx, cfg.PgConn)
+		if err != nil {
+			return err
+		}
+		defer dbpool.Close()
+
+		bl = blocklist.New()
+		b = business.New(dbpool, bl)
+		b.SetSchedulePolicy(cmd.Schedule.Policy())
+		b.SetIncidentPolicy(cmd.Incidents.Policy())
+
+		if cmd.SpoolPath != "" {
+			s, err := spool.Open(cmd.SpoolPath)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+			b.SetSpool(s)
+		}
 
-	seeds, err := b.GetCrawlerSeedDomains(cmdContext.Ctx, 50)
+		if err := b.LoadBlocklist(cmdContext.Ctx); err != nil {
+			return err
+		}
+		watchCtx, stopWatching := context.WithCancel(cmdContext.Ctx)
+		defer stopWatching()
+		// reload the blocklist while crawling
+		go b.WatchBlocklist(watchCtx, cfg.BlocklistRefreshInterval)
+
+		if len(seeds) == 0 {
+			seeds, err = b.GetCrawlerSeedDomains(cmdContext.Ctx, SeedCount)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(seeds) == 0 {
+		seeds = business.InitialSeedDomains()
+	}
+
+	resultSink, err := cmd.openSinks(specs, b)
 	if err != nil {
 		return err
 	}
 
+	if b != nil {
+		runConfig, err := json.Marshal(map[string]any{
+			"duration":       cmd.Duration.String(),
+			"crawler_count":  cmd.CrawlerCount,
+			"drain_timeout":  cmd.DrainTimeout.String(),
+			"queue_capacity": cmd.QueueCapacity,
+			"max_peers":      cmd.MaxPeers,
+			"seed_count":     SeedCount,
+			"schedule":       cmd.Schedule.Policy(),
+			"incidents":      cmd.Incidents.Policy(),
+			"sinks":          cmd.Sinks,
+		})
+		if err != nil {
+			return err
+		}
+
+		run, err = b.StartCrawlRun(cmdContext.Ctx, models.CrawlRun{
+			StartedAt:      time.Now(),
+			CrawlerVersion: cmdContext.Version,
+			Seeds:          seeds,
+			Config:         runConfig,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// the peers are crawled according to their schedule, like the seeds
+	var skipped []string
+	if b != nil {
+		skipped, err = b.GetNotDueDomains(cmdContext.Ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	o := orchestrator.New(orchestrator.OrchestratorConfig{
 		NumCrawlers:      cmd.CrawlerCount,
-		BlockedDomains:   business.BlockedDomains,
+		Blocklist:        bl,
 		SeedDomains:      seeds,
 		CrawlTimeout:     cmd.Duration,
 		CrawlerUserAgent: fmt.Sprintf("blahaj/%s", cmdContext.Version),
+		MaxPeers:         cmd.MaxPeers,
+		QueueCapacity:    cmd.QueueCapacity,
+		DrainTimeout:     cmd.DrainTimeout,
+		RunID:            run.ID,
+		SkippedDomains:   skipped,
 	})
 
+	// record the end of the run, even if the crawl was stopped
+	defer func() {
+		if b == nil {
+			// results received but not written because a sink failed
+			if lost := o.Stats().Lost + resultSink.Lost(); lost > 0 {
+				slog.WarnContext(cmdContext.Ctx, "crawls lost", "lost", lost)
+			}
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(cmdContext.Ctx), finishRunTimeout)
+		defer cancel()
+
+		stats := o.Stats()
+		stats.Lost += resultSink.Lost() + b.LostCrawls()
+
+		finished, err := b.FinishCrawlRun(ctx, run.ID, time.Now(), stats)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to record the end of the crawl run", "run_id", run.ID, "err", err)
+			return
+		}
+		slog.InfoContext(ctx, "crawl run finished", "run_id", run.ID, "stats", finished.Stats)
+
+		// roll up the days of the run, so that the time series include its crawls
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(cmdContext.Ctx), rollupTimeout)
+		defer cancel()
+		if err := b.RollupDays(ctx, run.StartedAt, time.Now()); err != nil {
+			// the next run, or the rollup command, will roll them up
+			slog.ErrorContext(ctx, "failed to roll up the days of the crawl run", "run_id", run.ID, "err", err)
+		}
+
+		// the uptime windows end at the last run, they are refreshed by the next one on failure
+		if err := b.RefreshUptime(ctx, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "failed to refresh the uptime of the instances", "run_id", run.ID, "err", err)
+		}
+	}()
+
 	// create a channel to receive the results
 	results := make(chan models.Crawl, MaximumConcurrentCrawls)
 
-	// run the crawler and the business logic in parallel
+	// run the crawler and the sinks in parallel
 	// using errgroup to exit early if one of them fails
 	g, ctx := errgroup.WithContext(cmdContext.Ctx)
 	g.SetLimit(2)
@@ -92,8 +238,9 @@ func (cmd *CrawlCmd) RunCrawl(cmdContext *Context) error {
 		return err
 	})
 	g.Go(func() error {
-		// this will run until the results channel is closed
-		return b.Run(ctx, results)
+		// this will run until the results channel is closed, even if a sink fails,
+		// the pending results are written even if the crawl was stopped
+		return resultSink.Run(context.WithoutCancel(ctx), results)
 	})
 
 	// wait for the goroutines to finish
@@ -111,6 +258,44 @@ func (cmd *CrawlCmd) RunCrawl(cmdContext *Context) error {
 	return nil
 }
 
+func (cmd *CrawlCmd) parseSinks() ([]sink.Spec, error) {
+	specs := make([]sink.Spec, 0, len(cmd.Sinks))
+	for _, s := range cmd.Sinks {
+		spec, err := sink.ParseSpec(s)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%w: at least one sink is required", sink.ErrInvalidSpec)
+	}
+	return specs, nil
+}
+
+// openSinks returns the sink receiving the crawls.
+// b is only used by the postgres sink.
+func (cmd *CrawlCmd) openSinks(specs []sink.Spec, b *business.Business) (*sink.FanOut, error) {
+	sinks := make([]sink.ResultSink, 0, len(specs))
+	for _, spec := range specs {
+		switch spec.Kind {
+		case sink.KindPostgres:
+			sinks = append(sinks, b)
+		case sink.KindStdout:
+			sinks = append(sinks, sink.NewStdout())
+		case sink.KindNDJSON:
+			s, err := sink.CreateNDJSON(spec.Path)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, s)
+		}
+	}
+
+	// even with a single sink, so that the crawls it cannot receive are counted as lost
+	return sink.NewFanOut(sinks...), nil
+}
+
 // This server is used as an entry point to start the crawl.
 // It used in production because of the way Scaleway Serverless Containers work.
 func (cmd *CrawlCmd) RunEntryPointServer(cmdContext *Context) error {
@@ -130,6 +315,8 @@ func (cmd *CrawlCmd) RunEntryPointServer(cmdContext *Context) error {
 		}
 	})
 
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
 	e.POST("/stop", func(c echo.Context) error {
 		// check if we are already crawling
 		if atomic.LoadUint32(&atomicIsAlreadyCrawling) == 0 {
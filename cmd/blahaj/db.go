@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/telemetry"
+)
+
+// newPool connects to the database, tracing every query.
+func newPool(ctx context.Context, connString string) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.Tracer = telemetry.NewQueryTracer()
+
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/alecthomas/kong"
 	"github.com/cyclimse/fediverse-blahaj/internal/config"
+	"github.com/cyclimse/fediverse-blahaj/internal/telemetry"
 )
 
 type Context struct {
@@ -20,8 +21,11 @@ var cli struct {
 	Debug  bool          `help:"Enable debug mode."`
 	Config config.Config `embed:""`
 
-	API   APICmd   `cmd:"" help:"Start the API." default:"1"`
-	Crawl CrawlCmd `cmd:"" help:"Start the crawler."`
+	API       APICmd       `cmd:"" help:"Start the API." default:"1"`
+	Crawl     CrawlCmd     `cmd:"" help:"Start the crawler."`
+	Blocklist BlocklistCmd `cmd:"" help:"Manage the blocklist."`
+	Spool     SpoolCmd     `cmd:"" help:"Manage the crawls that could not be written to the database."`
+	Rollup    RollupCmd    `cmd:"" help:"Compute the daily aggregates of the crawls. Also done at the end of each crawl run."`
 }
 
 func main() {
@@ -40,6 +44,11 @@ func main() {
 		Version: info.Main.Version,
 	}
 
-	err := ctx.Run(cmdContext)
+	shutdownTracing, err := telemetry.Setup(cmdContext.Ctx, cli.Config.Tracing, "blahaj", cmdContext.Version)
+	ctx.FatalIfErrorf(err)
+
+	err = ctx.Run(cmdContext)
+	// flush the pending spans, even if the command failed
+	err = errors.Join(err, shutdownTracing(cmdContext.Ctx))
 	ctx.FatalIfErrorf(err)
 }
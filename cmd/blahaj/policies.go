@@ -0,0 +1,42 @@
+package main
+
+import (
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/incident"
+	"github.com/cyclimse/fediverse-blahaj/internal/schedule"
+)
+
+// ScheduleFlags configure the schedule of the crawls, see schedule.Policy.
+// The defaults match schedule.DefaultPolicy.
+type ScheduleFlags struct {
+	HealthyInterval time.Duration `help:"Interval between two crawls of a healthy instance." default:"6h" env:"SCHEDULE_HEALTHY_INTERVAL"`
+	RetryInterval   time.Duration `help:"Interval before retrying a failing instance." default:"1h" env:"SCHEDULE_RETRY_INTERVAL"`
+	FreshFailures   int           `help:"Number of failures retried at the retry interval before backing off." default:"3" env:"SCHEDULE_FRESH_FAILURES"`
+	MaxBackoff      time.Duration `help:"Maximum interval between two crawls of a failing instance." default:"168h" env:"SCHEDULE_MAX_BACKOFF"`
+	TombstoneAfter  time.Duration `help:"Stop crawling domains that could not be resolved for this long." default:"720h" env:"SCHEDULE_TOMBSTONE_AFTER"`
+}
+
+func (f ScheduleFlags) Policy() schedule.Policy {
+	return schedule.Policy{
+		HealthyInterval: f.HealthyInterval,
+		RetryInterval:   f.RetryInterval,
+		FreshFailures:   f.FreshFailures,
+		MaxBackoff:      f.MaxBackoff,
+		TombstoneAfter:  f.TombstoneAfter,
+	}
+}
+
+// IncidentFlags configure the recording of the outages, see incident.Policy.
+// The defaults match incident.DefaultPolicy.
+type IncidentFlags struct {
+	ConfirmAfter int `help:"Number of consecutive failed crawls before an incident is confirmed." default:"2" env:"INCIDENT_CONFIRM_AFTER"`
+	CloseAfter   int `help:"Number of consecutive completed crawls before an incident is closed." default:"2" env:"INCIDENT_CLOSE_AFTER"`
+}
+
+func (f IncidentFlags) Policy() incident.Policy {
+	return incident.Policy{
+		ConfirmAfter: f.ConfirmAfter,
+		CloseAfter:   f.CloseAfter,
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"log/slog"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
+	"github.com/cyclimse/fediverse-blahaj/internal/business"
+)
+
+type RollupCmd struct {
+	From time.Time `help:"First day to roll up, in UTC. Defaults to the day before the last one." format:"2006-01-02"`
+	To   time.Time `help:"Last day to roll up, in UTC. Defaults to today." format:"2006-01-02"`
+}
+
+func (cmd *RollupCmd) Run(cmdContext *Context) error {
+	cfg := cmdContext.Config
+	cfg.SetDevelopmentDefaults()
+
+	to := cmd.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := cmd.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -1)
+	}
+	if from.After(to) {
+		return errors.New("the first day to roll up is after the last one")
+	}
+
+	dbpool, err := newPool(cmdContext.Ctx, cfg.PgConn)
+	if err != nil {
+		return err
+	}
+	defer dbpool.Close()
+
+	b := business.New(dbpool, blocklist.New())
+	// the global aggregates exclude the blocked domains
+	if err := b.LoadBlocklist(cmdContext.Ctx); err != nil {
+		return err
+	}
+	if err := b.RollupDays(cmdContext.Ctx, from, to); err != nil {
+		return err
+	}
+
+	slog.InfoContext(cmdContext.Ctx, "rolled up", "from", from.Format(time.DateOnly), "to", to.Format(time.DateOnly))
+	return nil
+}
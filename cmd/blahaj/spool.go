@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"log/slog"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
+	"github.com/cyclimse/fediverse-blahaj/internal/business"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/spool"
+)
+
+// replayBatchSize is the number of crawls written per transaction during a replay
+const replayBatchSize = 100
+
+type SpoolCmd struct {
+	Replay SpoolReplayCmd `cmd:"" help:"Write the crawls of a spool to the database. Must not run while a crawl writes to the spool."`
+}
+
+type SpoolReplayCmd struct {
+	Path string `arg:"" help:"Path to the spool." type:"existingfile"`
+	Keep bool   `help:"Keep the spool once replayed."`
+
+	Schedule  ScheduleFlags `embed:"" prefix:"schedule-"`
+	Incidents IncidentFlags `embed:"" prefix:"incident-"`
+}
+
+func (cmd *SpoolReplayCmd) Run(cmdContext *Context) error {
+	cfg := cmdContext.Config
+	cfg.SetDevelopmentDefaults()
+
+	f, err := os.Open(cmd.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dbpool, err := newPool(cmdContext.Ctx, cfg.PgConn)
+	if err != nil {
+		return err
+	}
+	defer dbpool.Close()
+
+	b := business.New(dbpool, blocklist.New())
+	b.SetSchedulePolicy(cmd.Schedule.Policy())
+	b.SetIncidentPolicy(cmd.Incidents.Policy())
+
+	// crawls of domains blocked since then are skipped
+	if err := b.LoadBlocklist(cmdContext.Ctx); err != nil {
+		return err
+	}
+
+	read, written, duplicates := 0, 0, 0
+	batch := make([]models.Crawl, 0, replayBatchSize)
+	write := func() error {
+		w, d, err := b.WriteCrawls(cmdContext.Ctx, batch)
+		written += w
+		duplicates += d
+		batch = batch[:0]
+		return err
+	}
+
+	err = spool.Read(f, func(crawl models.Crawl) error {
+		read++
+		batch = append(batch, crawl)
+		if len(batch) < replayBatchSize {
+			return nil
+		}
+		return write()
+	})
+	if err == nil {
+		err = write()
+	}
+	if err != nil {
+		// replaying is idempotent, the whole spool can be replayed again
+		return fmt.Errorf("failed to replay %s after %d crawls: %w", cmd.Path, written, err)
+	}
+
+	// skipped are the crawls of domains blocked since then, or invalid
+	slog.InfoContext(cmdContext.Ctx, "replayed spool", "path", cmd.Path, "read", read, "written", written, "duplicates", duplicates, "skipped", read-written-duplicates)
+
+	if cmd.Keep {
+		return nil
+	}
+	return os.Remove(cmd.Path)
+}
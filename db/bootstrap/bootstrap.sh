@@ -15,5 +15,6 @@ EOSQL
 for db in fediverse fediversedev; do
 psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$db" <<-EOSQL
         CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+        CREATE EXTENSION IF NOT EXISTS "pg_trgm";
 EOSQL
 done
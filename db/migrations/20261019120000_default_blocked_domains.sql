@@ -0,0 +1,12 @@
+INSERT INTO blocked_domain (pattern, reason, source)
+VALUES ('localhost', 'Not a public instance.', 'default'),
+    (
+        'ngrok.io',
+        'Tunnels to development instances.',
+        'default'
+    ),
+    (
+        'activitypub-troll.cf',
+        'Spam instance flooding the peers lists.',
+        'default'
+    ) ON CONFLICT DO NOTHING;
@@ -0,0 +1,19 @@
+-- Backfill the languages of the crawls from their raw nodeinfo,
+-- see nodeinfoLanguages in internal/crawler.
+UPDATE crawl
+SET languages = ARRAY(
+        SELECT DISTINCT lower(split_part(replace(tag, '_', '-'), '-', 1))
+        FROM jsonb_array_elements_text(
+                CASE
+                    WHEN jsonb_typeof(raw_nodeinfo->'metadata'->'languages') = 'array' THEN raw_nodeinfo->'metadata'->'languages'
+                    ELSE raw_nodeinfo->'metadata'->'langs'
+                END
+            ) AS tag
+        WHERE tag ~ '^[A-Za-z]{2,3}([-_][A-Za-z0-9]+)*$'
+        LIMIT 32
+    )
+WHERE languages IS NULL
+    AND 'array' IN (
+        jsonb_typeof(raw_nodeinfo->'metadata'->'languages'),
+        jsonb_typeof(raw_nodeinfo->'metadata'->'langs')
+    );
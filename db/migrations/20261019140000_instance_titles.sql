@@ -0,0 +1,56 @@
+-- Backfill the titles and descriptions of the crawls from their raw nodeinfo,
+-- see nodeinfoDescription in internal/crawler.
+-- Unlike the crawler, the HTML entities are not decoded.
+UPDATE crawl
+SET title = nullif(
+        left(
+            btrim(
+                regexp_replace(
+                    regexp_replace(raw_nodeinfo->'metadata'->>'nodeName', '<[^>]*>', ' ', 'g'),
+                    '[[:space:][:cntrl:]]+',
+                    ' ',
+                    'g'
+                )
+            ),
+            255
+        ),
+        ''
+    ),
+    description = nullif(
+        left(
+            btrim(
+                regexp_replace(
+                    regexp_replace(raw_nodeinfo->'metadata'->>'nodeDescription', '<[^>]*>', ' ', 'g'),
+                    '[[:space:][:cntrl:]]+',
+                    ' ',
+                    'g'
+                )
+            ),
+            1024
+        ),
+        ''
+    )
+WHERE title IS NULL
+    AND description IS NULL
+    AND 'string' IN (
+        jsonb_typeof(raw_nodeinfo->'metadata'->'nodeName'),
+        jsonb_typeof(raw_nodeinfo->'metadata'->'nodeDescription')
+    );
+
+
+-- The instances keep the title and the description of their last completed crawl.
+UPDATE instance
+SET title = last_completed.title,
+    description = last_completed.description
+FROM (
+        SELECT DISTINCT ON (instance_id) instance_id,
+            title,
+            description
+        FROM crawl
+        WHERE status = 'completed'
+        ORDER BY instance_id,
+            started_at DESC
+    ) AS last_completed
+WHERE instance.id = last_completed.instance_id
+    AND instance.title IS NULL
+    AND instance.description IS NULL;
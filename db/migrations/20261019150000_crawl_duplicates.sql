@@ -0,0 +1,31 @@
+-- Remove the duplicate crawls of an instance started at the same time,
+-- written twice before crawl_instance_id_started_at_idx made writing the crawls idempotent.
+-- The schema cannot be applied while there are duplicates: on a database created before the index,
+-- run this file first, eg: psql -f, then apply the schema and the migrations as usual.
+-- The first crawl of each duplicate set is kept, the instances referencing the others are moved to it.
+UPDATE instance
+SET last_crawl_id = duplicate.kept_id
+FROM (
+        SELECT id,
+            first_value(id) OVER (
+                PARTITION BY instance_id,
+                started_at
+                ORDER BY finished_at,
+                    id
+            ) AS kept_id
+        FROM crawl
+    ) AS duplicate
+WHERE instance.last_crawl_id = duplicate.id
+    AND duplicate.id <> duplicate.kept_id;
+DELETE FROM crawl USING (
+        SELECT id,
+            first_value(id) OVER (
+                PARTITION BY instance_id,
+                started_at
+                ORDER BY finished_at,
+                    id
+            ) AS kept_id
+        FROM crawl
+    ) AS duplicate
+WHERE crawl.id = duplicate.id
+    AND duplicate.id <> duplicate.kept_id;
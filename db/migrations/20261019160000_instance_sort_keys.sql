@@ -0,0 +1,11 @@
+-- Backfill the sort keys of the instances listing from their last crawl,
+-- see UpdateInstanceFromLastCrawl.
+UPDATE instance
+SET last_total_users = crawl.total_users,
+    last_active_month = crawl.active_month,
+    last_local_posts = crawl.local_posts,
+    last_number_of_peers = crawl.number_of_peers,
+    last_crawled_at = crawl.started_at
+FROM crawl
+WHERE crawl.id = instance.last_crawl_id
+    AND instance.last_crawled_at IS NULL;
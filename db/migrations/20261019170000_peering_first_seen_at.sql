@@ -0,0 +1,12 @@
+-- Clear the first_seen_at of the edges recorded before the peering relationships were tracked.
+-- Those were given the time the column was added, which would report them as gained on that day.
+-- The edges seen by a crawl have the start time of that crawl as first_seen_at, the others predate the tracking.
+UPDATE peering_relationship
+SET first_seen_at = NULL
+WHERE first_seen_at IS NOT NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM crawl
+        WHERE crawl.instance_id = peering_relationship.instance_id
+            AND crawl.started_at = peering_relationship.first_seen_at
+    );
@@ -0,0 +1,92 @@
+// Command gen generates the queries listing the instances from instances.sql.tmpl.
+// There is one query per sort key and direction so that each one can use the index of its key,
+// the template keeps their filters in a single place.
+package main
+
+import (
+	_ "embed"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"text/template"
+)
+
+//go:embed instances.sql.tmpl
+var instancesTemplate string
+
+// sortKey is a sort key of the instances listing
+type sortKey struct {
+	// Name is the suffix of the names of the queries
+	Name string
+	// Expr is the sorted expression, the same as in the index of the key
+	Expr string
+	// Cast converts the after_value text parameter to the type of Expr
+	Cast string
+	// Min and Max are below and above every value of Expr,
+	// they start the page when there is no cursor
+	Min, Max string
+}
+
+var sortKeys = []sortKey{
+	{Name: "TotalUsers", Expr: "instance.last_total_users", Cast: "::integer", Min: "-2147483648", Max: "2147483647"},
+	{Name: "ActiveMonth", Expr: "coalesce(instance.last_active_month, -1)", Cast: "::integer", Min: "-2147483648", Max: "2147483647"},
+	{Name: "LocalPosts", Expr: "coalesce(instance.last_local_posts, -1)", Cast: "::integer", Min: "-2147483648", Max: "2147483647"},
+	{Name: "NumberOfPeers", Expr: "coalesce(instance.last_number_of_peers, -1)", Cast: "::integer", Min: "-2147483648", Max: "2147483647"},
+	{Name: "Domain", Expr: "instance.domain", Min: "''", Max: "(SELECT max(domain) FROM instance)"},
+	{Name: "LastCrawledAt", Expr: "instance.last_crawled_at", Cast: "::timestamptz", Min: "'-infinity'", Max: "'infinity'"},
+	{Name: "FirstSeenAt", Expr: "instance.created_at", Cast: "::timestamptz", Min: "'-infinity'", Max: "'infinity'"},
+	{Name: "Uptime24h", Expr: "coalesce(instance.uptime_24h, -1)", Cast: "::float8", Min: "'-Infinity'", Max: "'Infinity'"},
+	{Name: "Uptime7d", Expr: "coalesce(instance.uptime_7d, -1)", Cast: "::float8", Min: "'-Infinity'", Max: "'Infinity'"},
+	{Name: "Uptime30d", Expr: "coalesce(instance.uptime_30d, -1)", Cast: "::float8", Min: "'-Infinity'", Max: "'Infinity'"},
+	{Name: "Uptime90d", Expr: "coalesce(instance.uptime_90d, -1)", Cast: "::float8", Min: "'-Infinity'", Max: "'Infinity'"},
+}
+
+// query is a ListInstancesBy query, for a sort key and a direction
+type query struct {
+	sortKey
+	// Op compares the sort key and id to the cursor
+	Op string
+	// Order is the direction of the ORDER BY clause
+	Order string
+	// After and AfterID start the page when there is no cursor
+	After, AfterID string
+}
+
+func queries() []query {
+	var qs []query
+	for _, key := range sortKeys {
+		asc, desc := key, key
+		asc.Name += "Asc"
+		desc.Name += "Desc"
+		qs = append(qs,
+			query{sortKey: asc, Op: ">", After: key.Min, AfterID: "00000000-0000-0000-0000-000000000000"},
+			query{sortKey: desc, Op: "<", Order: " DESC", After: key.Max, AfterID: "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+		)
+	}
+	return qs
+}
+
+func render(w io.Writer) error {
+	tmpl, err := template.New("instances").Parse(instancesTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, queries())
+}
+
+func main() {
+	out := flag.String("o", "instances.sql", "path of the generated queries")
+	flag.Parse()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := render(f); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesQueriesAreUpToDate(t *testing.T) {
+	want, err := os.ReadFile("../instances.sql")
+	require.NoError(t, err)
+
+	var got bytes.Buffer
+	require.NoError(t, render(&got))
+	assert.Equal(t, string(want), got.String(), "db/sql/instances.sql is out of date, run go generate ./internal/business")
+}
+
+func TestQueries(t *testing.T) {
+	qs := queries()
+	assert.Len(t, qs, 2*len(sortKeys))
+
+	names := make(map[string]bool)
+	for _, q := range qs {
+		assert.False(t, names[q.Name], "duplicate query %s", q.Name)
+		names[q.Name] = true
+	}
+}
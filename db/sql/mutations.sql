@@ -10,8 +10,12 @@ SELECT domain
 FROM unnest(@domains::varchar(255) []) domain ON CONFLICT DO NOTHING;
 
 
--- name: CreateCrawl :one
+-- name: CreateCrawl :batchone
+-- The ID is generated by the caller so that the crawls and the instances
+-- can be written in a single batch.
+-- Crawls already written, eg: replayed from the spool, are ignored: no row is returned.
 INSERT INTO crawl (
+        id,
         instance_id,
         status,
         error_code,
@@ -28,9 +32,16 @@ INSERT INTO crawl (
         local_posts,
         local_comments,
         raw_nodeinfo,
-        addresses
+        addresses,
+        crawl_run_id,
+        max_peers,
+        peers_truncated,
+        languages,
+        title,
+        description
     )
 VALUES (
+        $21,
         $1,
         $2,
         $3,
@@ -47,28 +58,390 @@ VALUES (
         $14,
         $15,
         $16,
-        $17
-    )
-RETURNING *;
+        $17,
+        $18,
+        $19,
+        $20,
+        $22,
+        $23,
+        $24
+    ) ON CONFLICT (instance_id, started_at) DO NOTHING
+RETURNING id;
 
 
 -- name: UpdatePeeringRelationships :exec
-INSERT INTO peering_relationship (instance_id, peer_id)
-SELECT $1,
-    id
+-- The edges are identified by the start time of the crawl that listed them,
+-- an older crawl being replayed does not overwrite what a newer one has seen,
+-- nor restores an edge that a newer crawl has removed.
+INSERT INTO peering_relationship (
+        instance_id,
+        peer_id,
+        first_seen_at,
+        last_seen_at,
+        last_crawl_id
+    )
+SELECT @instance_id,
+    instance.id,
+    @seen_at,
+    @seen_at,
+    (
+        SELECT crawl.id
+        FROM crawl
+        WHERE crawl.instance_id = @instance_id
+            AND crawl.started_at = @seen_at
+    )
 FROM instance
-WHERE domain = ANY(@domains::varchar(255) []) ON CONFLICT DO NOTHING;
+WHERE domain = ANY(@domains::varchar(255) []) ON CONFLICT (instance_id, peer_id) DO
+UPDATE
+SET first_seen_at = CASE
+        WHEN peering_relationship.removed_at IS NULL THEN peering_relationship.first_seen_at
+        ELSE EXCLUDED.first_seen_at
+    END,
+    last_seen_at = EXCLUDED.last_seen_at,
+    last_crawl_id = EXCLUDED.last_crawl_id,
+    removed_at = NULL
+WHERE peering_relationship.last_seen_at <= EXCLUDED.last_seen_at
+    AND (
+        peering_relationship.removed_at IS NULL
+        OR peering_relationship.removed_at <= EXCLUDED.last_seen_at
+    );
+
 
+-- name: RemoveUnseenPeeringRelationships :exec
+-- Marks as removed the edges not listed by a crawl with a complete list of peers.
+UPDATE peering_relationship
+SET removed_at = @seen_at
+WHERE instance_id = @instance_id
+    AND removed_at IS NULL
+    AND last_seen_at < @seen_at;
 
--- name: UpdateInstanceFromLastCrawl :exec
+
+-- name: UpdateInstanceFromLastCrawl :batchexec
+-- Only if the crawl was written and is more recent than the last crawl of the instance,
+-- so that replaying old crawls does not overwrite the state of the instance.
+-- The title and the description are kept from the last completed crawl.
+-- The sort keys of the instances listing are copied from the crawl.
 UPDATE instance
 SET last_crawl_id = $2,
     status = $3,
     software_name = $4,
+    next_crawl_at = $5,
+    consecutive_failures = $6,
+    failing_since = $7,
+    tombstoned_at = $8,
+    not_found_since = $9,
+    title = CASE
+        WHEN new_crawl.status = 'completed' THEN new_crawl.title
+        ELSE instance.title
+    END,
+    description = CASE
+        WHEN new_crawl.status = 'completed' THEN new_crawl.description
+        ELSE instance.description
+    END,
+    last_total_users = new_crawl.total_users,
+    last_active_month = new_crawl.active_month,
+    last_local_posts = new_crawl.local_posts,
+    last_number_of_peers = new_crawl.number_of_peers,
+    last_crawled_at = new_crawl.started_at,
     updated_at = NOW()
-WHERE id = $1;
+FROM crawl AS new_crawl
+WHERE instance.id = $1
+    AND new_crawl.id = $2
+    AND NOT EXISTS (
+        SELECT 1
+        FROM crawl AS last_crawl
+        WHERE last_crawl.id = instance.last_crawl_id
+            AND last_crawl.started_at >= new_crawl.started_at
+    );
 
 
 -- name: DeleteInstanceByID :exec
 DELETE FROM instance
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpsertBlockedDomain :exec
+INSERT INTO blocked_domain (pattern, reason, source, expires_at)
+VALUES ($1, $2, $3, $4) ON CONFLICT (pattern) DO
+UPDATE
+SET reason = EXCLUDED.reason,
+    source = EXCLUDED.source,
+    expires_at = EXCLUDED.expires_at,
+    updated_at = NOW();
+
+
+
+-- name: CreateCrawlRun :one
+INSERT INTO crawl_run (started_at, crawler_version, seeds, config)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+
+-- name: FinishCrawlRun :one
+-- Counts are derived from the crawls of the run, except for the ones
+-- only known by the orchestrator (rejected and blocked peers, lost crawls).
+UPDATE crawl_run
+SET finished_at = @finished_at,
+    rejected = @rejected,
+    blocked = @blocked,
+    blocked_groups = @blocked_groups,
+    lost = @lost,
+    attempted = (
+        SELECT COUNT(*)
+        FROM crawl
+        WHERE crawl.crawl_run_id = @id
+    ),
+    completed = (
+        SELECT COUNT(*)
+        FROM crawl
+        WHERE crawl.crawl_run_id = @id
+            AND crawl.status = 'completed'
+    ),
+    failed = (
+        SELECT COUNT(*)
+        FROM crawl
+        WHERE crawl.crawl_run_id = @id
+            AND crawl.status = 'failed'
+    ),
+    discovered = (
+        SELECT COUNT(*)
+        FROM instance
+        WHERE instance.created_at >= crawl_run.started_at
+            AND instance.created_at <= @finished_at
+    ),
+    error_counts = (
+        SELECT COALESCE(jsonb_object_agg(error_code, n), '{}')
+        FROM (
+                SELECT error_code,
+                    COUNT(*) AS n
+                FROM crawl
+                WHERE crawl.crawl_run_id = @id
+                    AND crawl.error_code IS NOT NULL
+                GROUP BY error_code
+            ) AS errors
+    ),
+    software_counts = (
+        SELECT COALESCE(jsonb_object_agg(software_name, n), '{}')
+        FROM (
+                SELECT software_name,
+                    COUNT(*) AS n
+                FROM crawl
+                WHERE crawl.crawl_run_id = @id
+                    AND crawl.software_name IS NOT NULL
+                    AND crawl.software_name != ''
+                GROUP BY software_name
+            ) AS software
+    )
+WHERE id = @id
+RETURNING *;
+
+-- name: RollupInstanceDays :exec
+-- Aggregates the crawls of each instance per day, from from_day to to_day included.
+-- Rolling up a day again replaces its aggregates, eg: once the day is over.
+INSERT INTO instance_daily_rollup (
+        instance_id,
+        day,
+        crawls,
+        completed_crawls,
+        last_users,
+        min_users,
+        max_users,
+        last_active_month,
+        min_active_month,
+        max_active_month,
+        last_local_posts,
+        min_local_posts,
+        max_local_posts,
+        rolled_up_at
+    )
+SELECT crawl.instance_id,
+    (crawl.started_at AT TIME ZONE 'UTC')::date AS day,
+    COUNT(*),
+    COUNT(*) FILTER (
+        WHERE crawl.status = 'completed'
+    ),
+    (
+        array_agg(
+            crawl.total_users
+            ORDER BY crawl.started_at DESC
+        ) FILTER (
+            WHERE crawl.total_users IS NOT NULL
+        )
+    ) [1],
+    MIN(crawl.total_users),
+    MAX(crawl.total_users),
+    (
+        array_agg(
+            crawl.active_month
+            ORDER BY crawl.started_at DESC
+        ) FILTER (
+            WHERE crawl.active_month IS NOT NULL
+        )
+    ) [1],
+    MIN(crawl.active_month),
+    MAX(crawl.active_month),
+    (
+        array_agg(
+            crawl.local_posts
+            ORDER BY crawl.started_at DESC
+        ) FILTER (
+            WHERE crawl.local_posts IS NOT NULL
+        )
+    ) [1],
+    MIN(crawl.local_posts),
+    MAX(crawl.local_posts),
+    NOW()
+FROM crawl
+WHERE crawl.started_at >= (sqlc.arg('from_day')::date)::timestamp AT TIME ZONE 'UTC'
+    AND crawl.started_at < (sqlc.arg('to_day')::date + 1)::timestamp AT TIME ZONE 'UTC'
+GROUP BY crawl.instance_id,
+    day ON CONFLICT (instance_id, day) DO
+UPDATE
+SET crawls = EXCLUDED.crawls,
+    completed_crawls = EXCLUDED.completed_crawls,
+    last_users = EXCLUDED.last_users,
+    min_users = EXCLUDED.min_users,
+    max_users = EXCLUDED.max_users,
+    last_active_month = EXCLUDED.last_active_month,
+    min_active_month = EXCLUDED.min_active_month,
+    max_active_month = EXCLUDED.max_active_month,
+    last_local_posts = EXCLUDED.last_local_posts,
+    min_local_posts = EXCLUDED.min_local_posts,
+    max_local_posts = EXCLUDED.max_local_posts,
+    rolled_up_at = EXCLUDED.rolled_up_at;
+
+
+-- name: RollupGlobalDays :exec
+-- Sums the daily aggregates of the instances, from from_day to to_day included.
+-- Must run after RollupInstanceDays for the same days.
+-- The instances which were down all day count with their last known values, as in GetGlobalStats,
+-- so that an outage does not show up as a drop of the users.
+-- Small servers and blocked domains are excluded.
+INSERT INTO global_daily_rollup (
+        day,
+        instances,
+        instances_up,
+        crawls,
+        completed_crawls,
+        total_users,
+        active_month,
+        local_posts,
+        rolled_up_at
+    )
+SELECT instance_daily_rollup.day,
+    COUNT(*),
+    COUNT(*) FILTER (
+        WHERE instance_daily_rollup.completed_crawls > 0
+    ),
+    SUM(instance_daily_rollup.crawls),
+    SUM(instance_daily_rollup.completed_crawls),
+    COALESCE(
+        SUM(
+            COALESCE(instance_daily_rollup.last_users, known.last_users)
+        ),
+        0
+    ),
+    COALESCE(
+        SUM(
+            COALESCE(
+                instance_daily_rollup.last_active_month,
+                known.last_active_month
+            )
+        ),
+        0
+    ),
+    COALESCE(
+        SUM(
+            COALESCE(
+                instance_daily_rollup.last_local_posts,
+                known.last_local_posts
+            )
+        ),
+        0
+    ),
+    NOW()
+FROM instance_daily_rollup
+    JOIN instance ON instance.id = instance_daily_rollup.instance_id
+    AND instance.deleted_at IS NULL
+    -- the last values reported before a day without any
+    LEFT JOIN LATERAL (
+        SELECT previous.last_users,
+            previous.last_active_month,
+            previous.last_local_posts
+        FROM instance_daily_rollup AS previous
+        WHERE previous.instance_id = instance_daily_rollup.instance_id
+            AND previous.day < instance_daily_rollup.day
+            AND previous.last_users IS NOT NULL
+        ORDER BY previous.day DESC
+        LIMIT 1
+    ) AS known ON instance_daily_rollup.last_users IS NULL
+WHERE instance_daily_rollup.day BETWEEN sqlc.arg('from_day')::date AND sqlc.arg('to_day')::date
+    AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+    AND COALESCE(instance_daily_rollup.max_users, known.last_users) > sqlc.arg('small_server_threshold')::integer
+GROUP BY instance_daily_rollup.day ON CONFLICT (day) DO
+UPDATE
+SET instances = EXCLUDED.instances,
+    instances_up = EXCLUDED.instances_up,
+    crawls = EXCLUDED.crawls,
+    completed_crawls = EXCLUDED.completed_crawls,
+    total_users = EXCLUDED.total_users,
+    active_month = EXCLUDED.active_month,
+    local_posts = EXCLUDED.local_posts,
+    rolled_up_at = EXCLUDED.rolled_up_at;
+
+
+-- name: UpdateInstanceUptime :batchexec
+UPDATE instance
+SET uptime_24h = sqlc.narg('uptime_24h'),
+    uptime_7d = sqlc.narg('uptime_7d'),
+    uptime_30d = sqlc.narg('uptime_30d'),
+    uptime_90d = sqlc.narg('uptime_90d'),
+    uptime_updated_at = sqlc.arg('updated_at')
+WHERE id = sqlc.arg('id');
+
+
+-- name: CreateIncident :batchexec
+-- Only if the crawl is the last crawl of the instance, so that replaying old crawls does not open incidents.
+INSERT INTO incident (
+        id,
+        instance_id,
+        first_crawl_id,
+        started_at,
+        error_code,
+        failed_crawls,
+        confirmed_at
+    )
+SELECT @id,
+    instance.id,
+    crawl.id,
+    crawl.started_at,
+    crawl.error_code,
+    @failed_crawls,
+    sqlc.narg('confirmed_at')
+FROM instance
+    JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.id = @instance_id
+    AND crawl.id = @crawl_id ON CONFLICT (instance_id)
+WHERE closed_at IS NULL DO NOTHING;
+
+
+-- name: UpdateIncident :batchexec
+-- Only if the crawl is the last crawl of the instance, see CreateIncident.
+-- The confirmation time is kept once set.
+UPDATE incident
+SET failed_crawls = @failed_crawls,
+    confirmed_at = COALESCE(incident.confirmed_at, sqlc.narg('confirmed_at')),
+    recovered_crawls = @recovered_crawls,
+    recovering_since = sqlc.narg('recovering_since'),
+    closed_at = sqlc.narg('closed_at')
+FROM instance
+WHERE incident.id = @id
+    AND instance.id = incident.instance_id
+    AND instance.last_crawl_id = @crawl_id;
+
+
+-- name: DeleteIncident :batchexec
+-- Only if the crawl is the last crawl of the instance, see CreateIncident.
+DELETE FROM incident USING instance
+WHERE incident.id = @id
+    AND instance.id = incident.instance_id
+    AND instance.last_crawl_id = @crawl_id;
@@ -5,8 +5,21 @@ WHERE domain = $1
 LIMIT 1;
 
 
--- name: GetInstanceWithLastCrawlByID :one
+-- name: GetInstancesByDomains :many
 SELECT *
+FROM instance
+WHERE domain = ANY(@domains::varchar(255) []);
+
+
+-- name: ListInstanceDomains :many
+SELECT domain
+FROM instance
+WHERE deleted_at IS NULL;
+
+
+-- name: GetInstanceWithLastCrawlByID :one
+SELECT sqlc.embed(instance),
+  sqlc.embed(crawl)
 FROM instance
   JOIN crawl ON crawl.id = instance.last_crawl_id
 WHERE instance.id = $1
@@ -14,34 +27,178 @@ WHERE instance.id = $1
 LIMIT 1;
 
 
--- name: GetPeersIDsByInstanceID :many
-SELECT peer_id
+-- name: ListOutgoingPeersPaginated :many
+-- The peers listed by an instance, by domain.
+-- ListIncomingPeersPaginated must return the same columns.
+SELECT instance.id,
+  instance.domain,
+  instance.status,
+  instance.software_name,
+  crawl.software_version,
+  crawl.total_users,
+  crawl.started_at AS last_crawled_at,
+  peering_relationship.first_seen_at,
+  peering_relationship.last_seen_at
 FROM peering_relationship
-  JOIN instance ON instance.id = peer_id
+  JOIN instance ON instance.id = peering_relationship.peer_id
   AND instance.deleted_at IS NULL
-WHERE instance_id = $1;
+  LEFT JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE peering_relationship.instance_id = @instance_id
+  AND peering_relationship.removed_at IS NULL
+  AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND (
+    sqlc.narg('status')::instance_status IS NULL
+    OR instance.status = sqlc.narg('status')::instance_status
+  )
+  AND (
+    sqlc.narg('software')::text IS NULL
+    OR instance.software_name = sqlc.narg('software')::text
+  )
+  AND (
+    sqlc.narg('after')::text IS NULL
+    OR instance.domain > sqlc.narg('after')::text
+  )
+ORDER BY instance.domain
+LIMIT sqlc.arg('limit');
 
 
--- TODO: these types of paginated queries are not efficient
---       we should use a cursor instead or a CTE
--- name: ListInstancesPaginated :many
-SELECT *,
+-- name: ListIncomingPeersPaginated :many
+-- The instances listing an instance as peer, by domain.
+SELECT instance.id,
+  instance.domain,
+  instance.status,
+  instance.software_name,
+  crawl.software_version,
+  crawl.total_users,
+  crawl.started_at AS last_crawled_at,
+  peering_relationship.first_seen_at,
+  peering_relationship.last_seen_at
+FROM peering_relationship
+  JOIN instance ON instance.id = peering_relationship.instance_id
+  AND instance.deleted_at IS NULL
+  LEFT JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE peering_relationship.peer_id = @instance_id
+  AND peering_relationship.removed_at IS NULL
+  AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND (
+    sqlc.narg('status')::instance_status IS NULL
+    OR instance.status = sqlc.narg('status')::instance_status
+  )
+  AND (
+    sqlc.narg('software')::text IS NULL
+    OR instance.software_name = sqlc.narg('software')::text
+  )
+  AND (
+    sqlc.narg('after')::text IS NULL
+    OR instance.domain > sqlc.narg('after')::text
+  )
+ORDER BY instance.domain
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListPeeringChangesPaginated :many
+-- The peers gained and lost by an instance since a date, most recent first.
+-- The edges recorded before the peering relationships were tracked have no first_seen_at,
+-- they are not reported as gained.
+WITH changes AS (
+  SELECT peer_id,
+    'gained'::text AS change,
+    first_seen_at AS changed_at
+  FROM peering_relationship
+  WHERE peering_relationship.instance_id = @instance_id
+    AND peering_relationship.first_seen_at IS NOT NULL
+    AND peering_relationship.first_seen_at >= @since
+  UNION ALL
+  SELECT peer_id,
+    'lost'::text AS change,
+    removed_at AS changed_at
+  FROM peering_relationship
+  WHERE peering_relationship.instance_id = @instance_id
+    AND peering_relationship.removed_at >= @since
+)
+SELECT changes.peer_id,
+  instance.domain,
+  changes.change,
+  changes.changed_at::timestamptz AS changed_at,
+  COUNT(*) OVER() AS total_count
+FROM changes
+  JOIN instance ON instance.id = changes.peer_id
+  AND instance.deleted_at IS NULL
+WHERE NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND (
+    sqlc.narg('change')::text IS NULL
+    OR changes.change = sqlc.narg('change')::text
+  )
+ORDER BY changes.changed_at DESC,
+  instance.domain
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+
+-- name: SearchInstances :many
+-- The instances matching the query, by full-text search or with typos in their domain or title.
+-- The expression of the search vector must be the same as in the instance_search_idx index.
+-- Only the small servers are excluded, the filters of the instances listing do not apply.
+-- The blocked domains are excluded before paginating, so that the pages are full and total_count is right.
+WITH search AS (
+  SELECT websearch_to_tsquery('simple', sqlc.arg('query')::text) AS tsquery
+)
+SELECT sqlc.embed(instance),
+  sqlc.embed(crawl),
+  (
+    ts_rank_cd(
+      setweight(to_tsvector('simple', replace(instance.domain, '.', ' ')), 'A') || setweight(to_tsvector('simple', coalesce(instance.title, '')), 'A') || setweight(to_tsvector('simple', coalesce(instance.software_name, '')), 'B') || setweight(to_tsvector('simple', coalesce(instance.description, '')), 'C'),
+      search.tsquery
+    ) + greatest(
+      word_similarity(sqlc.arg('query')::text, instance.domain),
+      word_similarity(sqlc.arg('query')::text, coalesce(instance.title, ''))
+    )
+  )::float8 AS rank,
+  ts_headline(
+    'simple',
+    coalesce(instance.description, ''),
+    search.tsquery,
+    sqlc.arg('headline_options')::text
+  )::text AS snippet,
   COUNT(*) OVER() AS total_count
 FROM instance
   JOIN crawl ON crawl.id = instance.last_crawl_id
-WHERE deleted_at IS NULL
-  AND total_users > $3
-ORDER BY total_users DESC
-LIMIT $1 OFFSET $2;
+  CROSS JOIN search
+WHERE instance.deleted_at IS NULL
+  AND crawl.total_users > sqlc.arg('small_server_threshold')::integer
+  AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND (
+    setweight(to_tsvector('simple', replace(instance.domain, '.', ' ')), 'A') || setweight(to_tsvector('simple', coalesce(instance.title, '')), 'A') || setweight(to_tsvector('simple', coalesce(instance.software_name, '')), 'B') || setweight(to_tsvector('simple', coalesce(instance.description, '')), 'C') @@ search.tsquery
+    OR sqlc.arg('query')::text <% instance.domain
+    OR sqlc.arg('query')::text <% instance.title
+  )
+ORDER BY rank DESC,
+  crawl.total_users DESC,
+  instance.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 
 -- name: ListCrawlsPaginated :many
-SELECT *,
-  COUNT(*) OVER() AS total_count
+-- Most recent first, the page starts after the crawl whose start time and id are after_*, if set.
+-- The page number is deprecated, offset must be zero with a cursor.
+SELECT *
 FROM crawl
-WHERE instance_id = $1
-ORDER BY started_at DESC
-LIMIT $2 OFFSET $3;
+WHERE instance_id = sqlc.arg('instance_id')
+  AND (
+    sqlc.narg('after_started_at')::timestamptz IS NULL
+    OR (started_at, id) < (
+      sqlc.narg('after_started_at')::timestamptz,
+      sqlc.arg('after_id')::uuid
+    )
+  )
+ORDER BY started_at DESC,
+  id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+
+-- name: CountCrawlsForInstance :one
+SELECT COUNT(*)
+FROM crawl
+WHERE instance_id = $1;
 
 
 -- name: ListErrorCodeDescriptions :many
@@ -50,17 +207,222 @@ FROM crawl_errors;
 
 
 -- name: GetCrawlerSeedDomains :many
--- Get all domains that have not been crawled yet. If there are none, return the domains with the oldest crawl.
+-- Get the domains that are due for a crawl: the ones that have never been crawled first,
+-- then by their scheduled crawl time. Tombstoned instances are never crawled again.
 SELECT domain
 FROM instance
-WHERE last_crawl_id IS NULL
-  AND deleted_at IS NULL
-UNION
-(
-  SELECT domain
-  FROM instance
-    JOIN crawl ON crawl.id = instance.last_crawl_id
-  WHERE deleted_at IS NULL
-  ORDER BY started_at ASC
-)
-LIMIT $1 OFFSET $2;
\ No newline at end of file
+WHERE deleted_at IS NULL
+  AND tombstoned_at IS NULL
+  AND (
+    next_crawl_at IS NULL
+    OR next_crawl_at <= NOW()
+  )
+ORDER BY last_crawl_id IS NOT NULL,
+  next_crawl_at ASC NULLS FIRST
+LIMIT $1 OFFSET $2;
+
+
+-- name: ListNotDueDomains :many
+-- Get the domains that are not due for a crawl, the opposite of GetCrawlerSeedDomains.
+-- A crawl does not queue them when they are discovered as peers.
+SELECT domain
+FROM instance
+WHERE deleted_at IS NULL
+  AND (
+    tombstoned_at IS NOT NULL
+    OR next_crawl_at > NOW()
+  );
+
+
+-- name: ListActiveBlockedDomains :many
+SELECT *
+FROM blocked_domain
+WHERE expires_at IS NULL
+  OR expires_at > NOW()
+ORDER BY pattern;
+
+
+
+-- name: GetCrawlRunByID :one
+SELECT *
+FROM crawl_run
+WHERE id = $1
+LIMIT 1;
+
+
+-- name: ListCrawlRunsPaginated :many
+SELECT *,
+  COUNT(*) OVER() AS total_count
+FROM crawl_run
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: GetLastFinishedCrawlRunID :one
+SELECT id
+FROM crawl_run
+WHERE finished_at IS NOT NULL
+ORDER BY started_at DESC
+LIMIT 1;
+
+
+-- name: GetGlobalStats :one
+-- Totals over the last crawl of each instance, small servers and blocked domains excluded.
+-- The size of an instance is the number of users reported by its last crawl that reported it,
+-- so that the instances which are down are excluded if they were small.
+SELECT COUNT(*) AS instances,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'up'
+  ) AS up,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'down'
+  ) AS down,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'unhealthy'
+  ) AS unhealthy,
+  COALESCE(SUM(last_crawl.total_users), 0)::bigint AS total_users,
+  COALESCE(SUM(last_crawl.active_month), 0)::bigint AS active_month,
+  COALESCE(SUM(last_crawl.local_posts), 0)::bigint AS local_posts,
+  COUNT(*) FILTER (
+    WHERE last_crawl.open_registrations
+  ) AS open_registrations,
+  -- number of instances whose last crawl reported if registrations are open
+  COUNT(last_crawl.open_registrations) AS reported_registrations
+FROM instance
+  JOIN crawl AS last_crawl ON last_crawl.id = instance.last_crawl_id
+  CROSS JOIN LATERAL (
+    SELECT sized_crawl.total_users
+    FROM crawl AS sized_crawl
+    WHERE sized_crawl.instance_id = instance.id
+      AND sized_crawl.total_users IS NOT NULL
+    ORDER BY sized_crawl.started_at DESC
+    LIMIT 1
+  ) AS size
+WHERE instance.deleted_at IS NULL
+  AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND size.total_users > sqlc.arg('small_server_threshold')::integer;
+
+
+-- name: ListSoftwareVersionStats :many
+-- Totals per software and version over the last crawl of each instance, small servers and blocked domains excluded.
+-- The software, the version and the size of an instance are from its last crawl that reported its users,
+-- as the crawls of the instances which are down report none.
+SELECT known.software_name::text AS software_name,
+  known.software_version,
+  COUNT(*) AS instances,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'up'
+  ) AS up,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'down'
+  ) AS down,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'unhealthy'
+  ) AS unhealthy,
+  COALESCE(SUM(last_crawl.total_users), 0)::bigint AS total_users,
+  COALESCE(SUM(last_crawl.active_month), 0)::bigint AS active_month
+FROM instance
+  JOIN crawl AS last_crawl ON last_crawl.id = instance.last_crawl_id
+  CROSS JOIN LATERAL (
+    SELECT known_crawl.software_name,
+      known_crawl.software_version,
+      known_crawl.total_users
+    FROM crawl AS known_crawl
+    WHERE known_crawl.instance_id = instance.id
+      AND known_crawl.total_users IS NOT NULL
+    ORDER BY known_crawl.started_at DESC
+    LIMIT 1
+  ) AS known
+WHERE instance.deleted_at IS NULL
+  AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND known.total_users > sqlc.arg('small_server_threshold')::integer
+  AND known.software_name IS NOT NULL
+  AND known.software_name != ''
+GROUP BY known.software_name,
+  known.software_version;
+
+
+
+-- name: ListInstanceDailyRollups :many
+SELECT *
+FROM instance_daily_rollup
+WHERE instance_id = sqlc.arg('instance_id')
+  AND day BETWEEN sqlc.arg('from_day')::date AND sqlc.arg('to_day')::date
+ORDER BY day;
+
+
+-- name: ListGlobalDailyRollups :many
+SELECT *
+FROM global_daily_rollup
+WHERE day BETWEEN sqlc.arg('from_day')::date AND sqlc.arg('to_day')::date
+ORDER BY day;
+
+
+-- name: ListInstanceIDsPaginated :many
+-- The instances are sorted by id, the page starts after after_id.
+SELECT id
+FROM instance
+WHERE deleted_at IS NULL
+  AND id > sqlc.arg('after_id')::uuid
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+
+-- name: ListCrawlStatusesSince :many
+-- The crawls of the instances started since a date, sorted by instance then by start time.
+SELECT instance_id,
+  started_at,
+  status
+FROM crawl
+WHERE instance_id = ANY(sqlc.arg('instance_ids')::uuid [])
+  AND started_at >= sqlc.arg('since')
+ORDER BY instance_id,
+  started_at;
+
+
+-- name: ListOpenIncidentsByInstanceIDs :many
+SELECT *
+FROM incident
+WHERE instance_id = ANY(sqlc.arg('instance_ids')::uuid [])
+  AND closed_at IS NULL;
+
+
+-- name: ListIncidentsPaginated :many
+-- The confirmed incidents, most recent first, small servers excluded.
+-- All of them if active is null, otherwise only the open or the closed ones.
+-- The size of an instance is the number of users reported by its last crawl that reported it, as in GetGlobalStats.
+-- The blocked domains are excluded before paginating, so that the pages are full and total_count is right.
+SELECT incident.*,
+  instance.domain,
+  COUNT(*) OVER() AS total_count
+FROM incident
+  JOIN instance ON instance.id = incident.instance_id
+  CROSS JOIN LATERAL (
+    SELECT sized_crawl.total_users
+    FROM crawl AS sized_crawl
+    WHERE sized_crawl.instance_id = instance.id
+      AND sized_crawl.total_users IS NOT NULL
+    ORDER BY sized_crawl.started_at DESC
+    LIMIT 1
+  ) AS size
+WHERE incident.confirmed_at IS NOT NULL
+  AND instance.deleted_at IS NULL
+  AND NOT instance.domain = ANY(sqlc.arg('blocked_domains')::text [])
+  AND size.total_users > sqlc.arg('small_server_threshold')::integer
+  AND (
+    sqlc.narg('active')::boolean IS NULL
+    OR (incident.closed_at IS NULL) = sqlc.narg('active')::boolean
+  )
+ORDER BY incident.started_at DESC,
+  incident.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+
+-- name: ListIncidentsForInstancePaginated :many
+-- The confirmed incidents of an instance, most recent first.
+SELECT *,
+  COUNT(*) OVER() AS total_count
+FROM incident
+WHERE instance_id = sqlc.arg('instance_id')
+  AND confirmed_at IS NOT NULL
+ORDER BY started_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -29,16 +29,138 @@ CREATE TABLE instance (
   -- from nodeinfo
   software_name varchar(255),
   -- can be null if we haven' t crawled it yet last_crawl_id uuid
-  last_crawl_id uuid
+  last_crawl_id uuid,
+  -- scheduling, see internal/schedule
+  -- null if the instance was never crawled, it is then due immediately
+  next_crawl_at timestamptz,
+  consecutive_failures integer NOT NULL DEFAULT 0 CHECK (consecutive_failures >= 0),
+  -- start of the first failed crawl of the current streak
+  failing_since timestamptz,
+  -- set when the domain could not be resolved for too long, the instance is not crawled anymore
+  tombstoned_at timestamptz,
+  -- from the last completed crawl, as text
+  title varchar(255),
+  description varchar(1024),
+  -- share of the time the instance was up over the last 24 hours, 7, 30 and 90 days
+  -- null if the status was unknown for most of the window, see internal/business/uptime.go
+  uptime_24h float8 CHECK (uptime_24h BETWEEN 0 AND 1),
+  uptime_7d float8 CHECK (uptime_7d BETWEEN 0 AND 1),
+  uptime_30d float8 CHECK (uptime_30d BETWEEN 0 AND 1),
+  uptime_90d float8 CHECK (uptime_90d BETWEEN 0 AND 1),
+  uptime_updated_at timestamptz,
+  -- start of the first crawl of the current streak of unresolved domains, see internal/schedule
+  not_found_since timestamptz,
+  -- from the last crawl, copied to sort the instances with an index
+  last_total_users integer,
+  last_active_month integer,
+  last_local_posts integer,
+  last_number_of_peers integer,
+  last_crawled_at timestamptz
 );
 
 
 CREATE INDEX instance_domain_idx ON instance (domain);
 
 
+CREATE INDEX instance_next_crawl_at_idx ON instance (next_crawl_at)
+WHERE tombstoned_at IS NULL;
+
+
+-- sort keys of the instances listing, see the ListInstancesBy queries
+-- only the instances with users are listed, the missing values are sorted as -1
+CREATE INDEX instance_last_total_users_idx ON instance (last_total_users, id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_last_active_month_idx ON instance ((coalesce(last_active_month, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_last_local_posts_idx ON instance ((coalesce(last_local_posts, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_last_number_of_peers_idx ON instance ((coalesce(last_number_of_peers, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_last_crawled_at_idx ON instance (last_crawled_at, id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_created_at_idx ON instance (created_at, id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_uptime_24h_idx ON instance ((coalesce(uptime_24h, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_uptime_7d_idx ON instance ((coalesce(uptime_7d, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_uptime_30d_idx ON instance ((coalesce(uptime_30d, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+CREATE INDEX instance_uptime_90d_idx ON instance ((coalesce(uptime_90d, -1)), id)
+WHERE last_total_users IS NOT NULL;
+
+
+-- full-text search, the expression must be the same as in SearchInstances
+-- the simple configuration is used as the instances are in every language
+CREATE INDEX instance_search_idx ON instance USING gin (
+  (
+    setweight(to_tsvector('simple', replace(domain, '.', ' ')), 'A') || setweight(to_tsvector('simple', coalesce(title, '')), 'A') || setweight(to_tsvector('simple', coalesce(software_name, '')), 'B') || setweight(to_tsvector('simple', coalesce(description, '')), 'C')
+  )
+);
+
+
+-- fuzzy search, requires the pg_trgm extension (see db/bootstrap)
+CREATE INDEX instance_domain_trgm_idx ON instance USING gin (domain gin_trgm_ops);
+
+
+CREATE INDEX instance_title_trgm_idx ON instance USING gin (title gin_trgm_ops);
+
+
+CREATE TABLE crawl_run (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  started_at timestamptz NOT NULL,
+  -- null while the run is in progress
+  finished_at timestamptz,
+  crawler_version varchar(255) NOT NULL,
+  seeds varchar(512) [] NOT NULL,
+  -- configuration of the crawler for this run, e.g. the number of crawlers
+  config jsonb NOT NULL DEFAULT '{}',
+  -- statistics, computed when the run finishes
+  attempted integer NOT NULL DEFAULT 0 CHECK (attempted >= 0),
+  completed integer NOT NULL DEFAULT 0 CHECK (completed >= 0),
+  failed integer NOT NULL DEFAULT 0 CHECK (failed >= 0),
+  -- number of instances discovered during the run
+  discovered integer NOT NULL DEFAULT 0 CHECK (discovered >= 0),
+  -- number of peers rejected because their domain is invalid
+  rejected integer NOT NULL DEFAULT 0 CHECK (rejected >= 0),
+  -- number of peers skipped because their domain is blocked
+  blocked integer NOT NULL DEFAULT 0 CHECK (blocked >= 0),
+  -- number of crawls not persisted because the run was stopped
+  lost integer NOT NULL DEFAULT 0 CHECK (lost >= 0),
+  -- number of crawls per crawl_error_code and per software name
+  error_counts jsonb NOT NULL DEFAULT '{}',
+  software_counts jsonb NOT NULL DEFAULT '{}',
+  -- number of blocked peers per registrable domain (eTLD+1)
+  blocked_groups jsonb NOT NULL DEFAULT '{}'
+);
+
+
+CREATE INDEX crawl_run_started_at_idx ON crawl_run (started_at);
+
+
 CREATE TABLE crawl (
   id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
   instance_id uuid REFERENCES instance(id) NOT NULL,
+  -- can be null for crawls made before runs were recorded
+  crawl_run_id uuid REFERENCES crawl_run(id),
   status crawl_status NOT NULL DEFAULT 'unknown',
   -- if the crawl failed, this is the reason why
   -- error_code can be returned to the user, error_msg is for debugging
@@ -63,11 +185,25 @@ CREATE TABLE crawl (
   raw_nodeinfo jsonb,
   -- ip address of the instance. not displayed publicly, but useful for
   -- debugging and blocking.
-  addresses inet []
+  addresses inet [],
+  -- maximum number of peers kept by the crawler, null if unlimited
+  max_peers integer CHECK (max_peers > 0),
+  -- true if the instance listed more peers than max_peers
+  peers_truncated boolean NOT NULL DEFAULT false,
+  -- primary language subtags reported in the nodeinfo metadata, null if not reported
+  languages varchar(8) [],
+  -- name and description reported in the nodeinfo metadata, without markup
+  title varchar(255),
+  description varchar(1024)
 );
 
 
-CREATE INDEX crawl_instance_id_idx ON crawl (instance_id);
+-- a crawl is identified by its instance and its start time,
+-- which makes replaying crawls idempotent
+CREATE UNIQUE INDEX crawl_instance_id_started_at_idx ON crawl (instance_id, started_at);
+
+
+CREATE INDEX crawl_crawl_run_id_idx ON crawl (crawl_run_id);
 
 
 ALTER TABLE instance
@@ -77,6 +213,16 @@ ADD CONSTRAINT last_crawl_id FOREIGN KEY (last_crawl_id) REFERENCES crawl(id);
 CREATE TABLE peering_relationship (
   instance_id uuid REFERENCES instance(id),
   peer_id uuid REFERENCES instance(id),
+  -- start time of the crawl in which the peer was first seen,
+  -- reset when a removed peer is seen again,
+  -- null for edges recorded before the peering relationships were tracked
+  first_seen_at timestamptz,
+  -- start time of the last crawl that listed the peer
+  last_seen_at timestamptz NOT NULL DEFAULT NOW(),
+  -- last crawl that listed the peer, null for edges recorded before crawls were tracked
+  last_crawl_id uuid REFERENCES crawl(id),
+  -- set when a complete list of peers no longer contains the peer
+  removed_at timestamptz,
   PRIMARY KEY (instance_id, peer_id)
 );
 
@@ -84,7 +230,103 @@ CREATE TABLE peering_relationship (
 CREATE INDEX peering_relationship_instance_id_idx ON peering_relationship (instance_id);
 
 
+-- to list the instances that have an instance as peer
+CREATE INDEX peering_relationship_peer_id_idx ON peering_relationship (peer_id);
+
+
+-- to list the peers gained and lost by an instance since a date
+CREATE INDEX peering_relationship_instance_id_first_seen_at_idx ON peering_relationship (instance_id, first_seen_at);
+
+
+CREATE INDEX peering_relationship_instance_id_removed_at_idx ON peering_relationship (instance_id, removed_at)
+WHERE removed_at IS NOT NULL;
+
+
 CREATE TABLE crawl_errors (
   error_code crawl_error_code PRIMARY KEY,
   description varchar(1024) NOT NULL
-);
\ No newline at end of file
+);
+
+CREATE TABLE blocked_domain (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  -- a domain, a wildcard (*.example.com) or a regexp (/pattern/)
+  -- see internal/blocklist for the syntax
+  pattern varchar(512) UNIQUE NOT NULL,
+  reason varchar(1024) NOT NULL DEFAULT '',
+  -- where the entry comes from, e.g. "manual" or the name of an imported list
+  source varchar(255) NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT NOW(),
+  updated_at timestamptz,
+  -- entries without an expiry are permanent
+  expires_at timestamptz
+);
+
+
+-- daily aggregates of the crawls of each instance, see RollupInstanceDays
+-- the days are in UTC
+CREATE TABLE instance_daily_rollup (
+  instance_id uuid REFERENCES instance(id) NOT NULL,
+  day date NOT NULL,
+  crawls integer NOT NULL CHECK (crawls > 0),
+  completed_crawls integer NOT NULL CHECK (completed_crawls >= 0),
+  -- null if no crawl of the day reported them
+  last_users integer,
+  min_users integer,
+  max_users integer,
+  last_active_month integer,
+  min_active_month integer,
+  max_active_month integer,
+  last_local_posts integer,
+  min_local_posts integer,
+  max_local_posts integer,
+  rolled_up_at timestamptz NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (instance_id, day)
+);
+
+
+-- daily aggregates of the instances, small servers excluded, see RollupGlobalDays
+CREATE TABLE global_daily_rollup (
+  day date PRIMARY KEY,
+  -- instances crawled during the day
+  instances integer NOT NULL CHECK (instances >= 0),
+  -- instances with at least one completed crawl during the day
+  instances_up integer NOT NULL CHECK (instances_up >= 0),
+  crawls integer NOT NULL CHECK (crawls >= 0),
+  completed_crawls integer NOT NULL CHECK (completed_crawls >= 0),
+  -- sums of the last values of the day of each instance
+  total_users bigint NOT NULL CHECK (total_users >= 0),
+  active_month bigint NOT NULL CHECK (active_month >= 0),
+  local_posts bigint NOT NULL CHECK (local_posts >= 0),
+  rolled_up_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+
+-- outages of the instances, see internal/incident
+CREATE TABLE incident (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  instance_id uuid REFERENCES instance(id) NOT NULL,
+  -- first failed crawl of the outage, its start is the start of the outage
+  first_crawl_id uuid REFERENCES crawl(id) NOT NULL,
+  started_at timestamptz NOT NULL,
+  error_code crawl_error_code,
+  failed_crawls integer NOT NULL CHECK (failed_crawls > 0),
+  -- null until enough consecutive crawls failed, the incident is not exposed until then
+  confirmed_at timestamptz,
+  -- consecutive completed crawls since the last failed one, and the start of the first of them
+  recovered_crawls integer NOT NULL DEFAULT 0 CHECK (recovered_crawls >= 0),
+  recovering_since timestamptz,
+  -- start of the first completed crawl after the outage, null while it lasts
+  closed_at timestamptz CHECK (closed_at >= started_at)
+);
+
+
+-- at most one open incident per instance
+CREATE UNIQUE INDEX incident_instance_id_open_idx ON incident (instance_id)
+WHERE closed_at IS NULL;
+
+
+CREATE INDEX incident_instance_id_started_at_idx ON incident (instance_id, started_at);
+
+
+CREATE INDEX incident_started_at_idx ON incident (started_at)
+WHERE confirmed_at IS NOT NULL;
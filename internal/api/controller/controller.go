@@ -4,12 +4,17 @@ package controller
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"log/slog"
 
 	v1 "github.com/cyclimse/fediverse-blahaj/internal/api/v1"
 	"github.com/cyclimse/fediverse-blahaj/internal/business"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -39,21 +44,143 @@ func (c *APIController) GetInstanceByID(ctx echo.Context, id uuid.UUID) error {
 	return ctx.JSON(http.StatusOK, instanceFromModel(instance))
 }
 
+// GetInstanceByDomain implements v1.ServerInterface
+func (c *APIController) GetInstanceByDomain(ctx echo.Context, domain string) error {
+	id, err := c.Business.GetInstanceIDByDomain(ctx.Request().Context(), domain)
+	if err != nil {
+		if errors.Is(err, business.ErrInvalidDomain) {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
+		if errors.Is(err, business.ErrInstanceNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to get instance by domain", "error", err, "domain", domain)
+		return err
+	}
+
+	// relative to /instances/by-domain/{domain}, so that it does not depend on the base URL
+	return ctx.Redirect(http.StatusFound, "../"+id.String())
+}
+
+// LookupInstances implements v1.ServerInterface
+func (c *APIController) LookupInstances(ctx echo.Context) error {
+	var body v1.LookupInstancesJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "invalid request body",
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+	if len(body.Domains) == 0 || len(body.Domains) > maxLookupDomains {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("expected between 1 and %d domains", maxLookupDomains),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	lookups, err := c.Business.LookupInstances(ctx.Request().Context(), body.Domains)
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to lookup instances", "error", err, "domains", len(body.Domains))
+		return err
+	}
+
+	var resp = v1.LookupInstances200JSONResponse{
+		Results: make([]v1.InstanceLookup, len(lookups)),
+	}
+
+	for i, l := range lookups {
+		resp.Results[i] = instanceLookupFromModel(l)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
 // ListInstances implements v1.InstanceInterface
 func (c *APIController) ListInstances(ctx echo.Context, params v1.ListInstancesParams) error {
 	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
 
-	instances, total, err := c.Business.ListInstances(ctx.Request().Context(), page, pageSize)
+	if params.Cursor != nil && params.Page != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "cursor and page cannot be used together",
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	filter, err := instancesFilterFromParams(params)
 	if err != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	var sortParam string
+	if params.Sort != nil {
+		sortParam = string(*params.Sort)
+	}
+	sort, err := parseInstancesSort(sortParam)
+	if err != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	var after *models.InstancesCursor
+	if params.Cursor != nil {
+		after, err = decodeInstancesCursor(*params.Cursor)
+		if err != nil {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
+	}
+
+	instances, next, err := c.Business.ListInstances(ctx.Request().Context(), filter, sort, after, page, pageSize)
+	if err != nil {
+		if errors.Is(err, business.ErrCursorMismatch) {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
 		slog.ErrorContext(ctx.Request().Context(), "failed to list instances", "error", err)
 		return err
 	}
 
 	var resp = v1.ListInstances200JSONResponse{
-		Results: make([]v1.Instance, len(instances)),
-		Page:    page,
-		PerPage: pageSize,
-		Total:   total,
+		Results:    make([]v1.Instance, len(instances)),
+		PerPage:    pageSize,
+		NextCursor: encodeInstancesCursor(next),
+	}
+
+	// the clients paginating by number need the total to display the pages
+	if params.Page != nil || utils.BoolPtrToVal(params.IncludeTotal) {
+		total, err := c.Business.CountInstances(ctx.Request().Context(), filter)
+		if err != nil {
+			slog.ErrorContext(ctx.Request().Context(), "failed to count instances", "error", err)
+			return err
+		}
+		resp.Total = &total
+	}
+	if params.Page != nil {
+		resp.Page = &page
 	}
 
 	for i, s := range instances {
@@ -67,22 +194,397 @@ func (c *APIController) ListInstances(ctx echo.Context, params v1.ListInstancesP
 func (c *APIController) ListCrawlsForInstance(ctx echo.Context, id uuid.UUID, params v1.ListCrawlsForInstanceParams) error {
 	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
 
-	crawls, total, err := c.Business.ListCrawlsForInstance(ctx.Request().Context(), id, page, pageSize)
+	if params.Cursor != nil && params.Page != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: "cursor and page cannot be used together",
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	var after *models.CrawlsCursor
+	if params.Cursor != nil {
+		var err error
+		after, err = decodeCrawlsCursor(*params.Cursor)
+		if err != nil {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
+	}
+
+	crawls, next, err := c.Business.ListCrawlsForInstance(ctx.Request().Context(), id, after, page, pageSize)
 	if err != nil {
 		slog.ErrorContext(ctx.Request().Context(), "failed to list crawls", "error", err, "instance_id", id)
 		return err
 	}
 
 	var resp = v1.ListCrawlsForInstance200JSONResponse{
-		Results: make([]v1.Crawl, len(crawls)),
+		Results:    make([]v1.Crawl, len(crawls)),
+		PerPage:    pageSize,
+		NextCursor: encodeCrawlsCursor(next),
+	}
+
+	if params.Page != nil || utils.BoolPtrToVal(params.IncludeTotal) {
+		total, err := c.Business.CountCrawlsForInstance(ctx.Request().Context(), id)
+		if err != nil {
+			slog.ErrorContext(ctx.Request().Context(), "failed to count crawls", "error", err, "instance_id", id)
+			return err
+		}
+		resp.Total = &total
+	}
+	if params.Page != nil {
+		resp.Page = &page
+	}
+
+	for i, c := range crawls {
+		resp.Results[i] = crawlFromModel(c)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// ListPeersForInstance implements v1.ServerInterface
+func (c *APIController) ListPeersForInstance(ctx echo.Context, id uuid.UUID, params v1.ListPeersForInstanceParams) error {
+	pageSize := validatePageSize(params.PerPage)
+
+	filter := models.PeersFilter{
+		Direction: models.PeerDirectionOutgoing,
+	}
+	if params.Direction != nil {
+		filter.Direction = models.PeerDirection(*params.Direction)
+	}
+	if filter.Direction != models.PeerDirectionOutgoing && filter.Direction != models.PeerDirectionIncoming {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid direction %q, expected outgoing or incoming", filter.Direction),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+	if params.Status != nil {
+		status, err := parseInstanceStatus(string(*params.Status))
+		if err != nil {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
+		filter.Status = status
+	}
+	if params.Software != nil {
+		filter.Software = strings.ToLower(*params.Software)
+	}
+
+	var after peersCursor
+	if params.Cursor != nil {
+		if err := decodeCursor(*params.Cursor, &after); err != nil {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
+	}
+
+	peers, next, err := c.Business.ListPeers(ctx.Request().Context(), id, filter, after.Domain, pageSize)
+	if err != nil {
+		if errors.Is(err, business.ErrInstanceNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to list peers", "error", err, "instance_id", id)
+		return err
+	}
+
+	var resp = v1.ListPeersForInstance200JSONResponse{
+		Results: make([]v1.PeerSummary, len(peers)),
+		PerPage: pageSize,
+	}
+	if next != "" {
+		resp.NextCursor = utils.ValToPtr(encodeCursor(peersCursor{Domain: next}), true)
+	}
+
+	for i, p := range peers {
+		resp.Results[i] = peerSummaryFromModel(p)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// ListPeerChangesForInstance implements v1.ServerInterface
+func (c *APIController) ListPeerChangesForInstance(ctx echo.Context, id uuid.UUID, params v1.ListPeerChangesForInstanceParams) error {
+	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
+
+	var change models.PeerChangeKind
+	if params.Change != nil {
+		change = models.PeerChangeKind(*params.Change)
+		if change != models.PeerChangeGained && change != models.PeerChangeLost {
+			e := v1.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid change %q, expected gained or lost", change),
+			}
+			return ctx.JSON(http.StatusBadRequest, e)
+		}
+	}
+
+	changes, total, err := c.Business.ListPeerChanges(ctx.Request().Context(), id, params.Since, change, page, pageSize)
+	if err != nil {
+		if errors.Is(err, business.ErrInstanceNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to list peer changes", "error", err, "instance_id", id)
+		return err
+	}
+
+	var resp = v1.ListPeerChangesForInstance200JSONResponse{
+		Results: make([]v1.PeerChange, len(changes)),
 		Page:    page,
 		PerPage: pageSize,
 		Total:   total,
 	}
 
-	for i, c := range crawls {
-		resp.Results[i] = crawlFromModel(c)
+	for i, c := range changes {
+		resp.Results[i] = peerChangeFromModel(c)
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
 }
+
+// ListIncidentsForInstance implements v1.ServerInterface
+func (c *APIController) ListIncidentsForInstance(ctx echo.Context, id uuid.UUID, params v1.ListIncidentsForInstanceParams) error {
+	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
+
+	incidents, total, err := c.Business.ListIncidentsForInstance(ctx.Request().Context(), id, page, pageSize)
+	if err != nil {
+		if errors.Is(err, business.ErrInstanceNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to list incidents", "error", err, "instance_id", id)
+		return err
+	}
+
+	var resp = v1.ListIncidentsForInstance200JSONResponse{
+		Results: make([]v1.Incident, len(incidents)),
+		Page:    page,
+		PerPage: pageSize,
+		Total:   total,
+	}
+
+	now := time.Now()
+	for i, incident := range incidents {
+		resp.Results[i] = incidentFromModel(incident, now)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// ListIncidents implements v1.ServerInterface
+func (c *APIController) ListIncidents(ctx echo.Context, params v1.ListIncidentsParams) error {
+	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
+
+	incidents, total, err := c.Business.ListIncidents(ctx.Request().Context(), params.Active, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to list incidents", "error", err)
+		return err
+	}
+
+	var resp = v1.ListIncidents200JSONResponse{
+		Results: make([]v1.Incident, len(incidents)),
+		Page:    page,
+		PerPage: pageSize,
+		Total:   total,
+	}
+
+	now := time.Now()
+	for i, incident := range incidents {
+		resp.Results[i] = incidentFromModel(incident, now)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetGlobalStats implements v1.ServerInterface
+func (c *APIController) GetGlobalStats(ctx echo.Context) error {
+	stats, err := c.Business.GetGlobalStats(ctx.Request().Context())
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to get global stats", "error", err)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, globalStatsFromModel(stats))
+}
+
+// ListSoftware implements v1.ServerInterface
+func (c *APIController) ListSoftware(ctx echo.Context, params v1.ListSoftwareParams) error {
+	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
+
+	stats, total, err := c.Business.ListSoftwareStats(ctx.Request().Context(), page, pageSize)
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to list software stats", "error", err)
+		return err
+	}
+
+	var resp = v1.ListSoftware200JSONResponse{
+		Results: make([]v1.SoftwareStats, len(stats)),
+		Page:    page,
+		PerPage: pageSize,
+		Total:   total,
+	}
+
+	for i, s := range stats {
+		resp.Results[i] = softwareStatsFromModel(s)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetSoftwareByName implements v1.ServerInterface
+func (c *APIController) GetSoftwareByName(ctx echo.Context, name string) error {
+	stats, err := c.Business.GetSoftwareStats(ctx.Request().Context(), name)
+	if err != nil {
+		if errors.Is(err, business.ErrSoftwareNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to get software stats", "error", err, "software", name)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, softwareStatsFromModel(stats))
+}
+
+// SearchInstances implements v1.ServerInterface
+func (c *APIController) SearchInstances(ctx echo.Context, params v1.SearchInstancesParams) error {
+	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
+
+	q, err := parseSearchQuery(params.Q)
+	if err != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	results, total, err := c.Business.SearchInstances(ctx.Request().Context(), q, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to search instances", "error", err, "query", q)
+		return err
+	}
+
+	var resp = v1.SearchInstances200JSONResponse{
+		Results: make([]v1.SearchResult, len(results)),
+		Page:    page,
+		PerPage: pageSize,
+		Total:   total,
+	}
+
+	for i, r := range results {
+		resp.Results[i] = searchResultFromModel(r)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// ListCrawlRuns implements v1.ServerInterface
+func (c *APIController) ListCrawlRuns(ctx echo.Context, params v1.ListCrawlRunsParams) error {
+	page, pageSize := validatePage(params.Page), validatePageSize(params.PerPage)
+
+	runs, total, err := c.Business.ListCrawlRuns(ctx.Request().Context(), page, pageSize)
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to list crawl runs", "error", err)
+		return err
+	}
+
+	var resp = v1.ListCrawlRuns200JSONResponse{
+		Results: make([]v1.CrawlRun, len(runs)),
+		Page:    page,
+		PerPage: pageSize,
+		Total:   total,
+	}
+
+	for i, r := range runs {
+		resp.Results[i] = crawlRunFromModel(r)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetCrawlRunByID implements v1.ServerInterface
+func (c *APIController) GetCrawlRunByID(ctx echo.Context, id uuid.UUID) error {
+	run, err := c.Business.GetCrawlRunByID(ctx.Request().Context(), id)
+	if err != nil {
+		if errors.Is(err, business.ErrCrawlRunNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to get crawl run", "error", err, "run_id", id)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, crawlRunFromModel(run))
+}
+
+// GetInstanceTimeseries implements v1.ServerInterface
+func (c *APIController) GetInstanceTimeseries(ctx echo.Context, id uuid.UUID, params v1.GetInstanceTimeseriesParams) error {
+	q, err := parseTimeseriesQuery(params.Metric, params.Interval, params.From, params.To, time.Now())
+	if err != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	points, err := c.Business.GetInstanceTimeseries(ctx.Request().Context(), id, q)
+	if err != nil {
+		if errors.Is(err, business.ErrInstanceNotFound) {
+			e := v1.Error{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+			return ctx.JSON(http.StatusNotFound, e)
+		}
+		slog.ErrorContext(ctx.Request().Context(), "failed to get instance time series", "error", err, "instance_id", id)
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, timeseriesFromModel(q, points))
+}
+
+// GetGlobalTimeseries implements v1.ServerInterface
+func (c *APIController) GetGlobalTimeseries(ctx echo.Context, params v1.GetGlobalTimeseriesParams) error {
+	q, err := parseTimeseriesQuery(params.Metric, params.Interval, params.From, params.To, time.Now())
+	if err != nil {
+		e := v1.Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, e)
+	}
+
+	points, err := c.Business.GetGlobalTimeseries(ctx.Request().Context(), q)
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "failed to get global time series", "error", err)
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, timeseriesFromModel(q, points))
+}
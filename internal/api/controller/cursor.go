@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+)
+
+// errInvalidCursor is returned when a cursor was not issued by the API
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor returns an opaque cursor for the position v in a listing.
+// Clients must not rely on its content, it may change at any time.
+func encodeCursor(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		// the positions are plain structs
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor decodes a cursor returned by encodeCursor into v.
+func decodeCursor(cursor string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return errInvalidCursor
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return errInvalidCursor
+	}
+	return nil
+}
+
+// peersCursor is the position in the peers of an instance
+type peersCursor struct {
+	Domain string `json:"d"`
+}
+
+// instancesCursor is the position in the instances listing
+type instancesCursor struct {
+	// Sort is the sort parameter the cursor was issued for
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"i"`
+}
+
+func encodeInstancesCursor(c *models.InstancesCursor) *string {
+	if c == nil {
+		return nil
+	}
+	cursor := encodeCursor(instancesCursor{
+		Sort:  formatInstancesSort(c.Sort),
+		Value: c.Value,
+		ID:    c.ID,
+	})
+	return &cursor
+}
+
+func decodeInstancesCursor(cursor string) (*models.InstancesCursor, error) {
+	var c instancesCursor
+	if err := decodeCursor(cursor, &c); err != nil {
+		return nil, err
+	}
+	sort, err := parseInstancesSort(c.Sort)
+	if err != nil || c.Value == "" {
+		return nil, errInvalidCursor
+	}
+	return &models.InstancesCursor{Sort: sort, Value: c.Value, ID: c.ID}, nil
+}
+
+// crawlsCursor is the position in the crawls of an instance
+type crawlsCursor struct {
+	StartedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+}
+
+func encodeCrawlsCursor(c *models.CrawlsCursor) *string {
+	if c == nil {
+		return nil
+	}
+	cursor := encodeCursor(crawlsCursor(*c))
+	return &cursor
+}
+
+func decodeCrawlsCursor(cursor string) (*models.CrawlsCursor, error) {
+	var c crawlsCursor
+	if err := decodeCursor(cursor, &c); err != nil {
+		return nil, err
+	}
+	if c.StartedAt.IsZero() {
+		return nil, errInvalidCursor
+	}
+	return &models.CrawlsCursor{StartedAt: c.StartedAt, ID: c.ID}, nil
+}
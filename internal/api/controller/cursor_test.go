@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	cursor := encodeCursor(peersCursor{Domain: "mastodon.social"})
+
+	var got peersCursor
+	require.NoError(t, decodeCursor(cursor, &got))
+	assert.Equal(t, peersCursor{Domain: "mastodon.social"}, got)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("mastodon.social"))},
+		{"wrong type", encodeCursor([]string{"mastodon.social"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got peersCursor
+			assert.ErrorIs(t, decodeCursor(tt.cursor, &got), errInvalidCursor)
+		})
+	}
+}
+
+func TestInstancesCursor(t *testing.T) {
+	want := &models.InstancesCursor{
+		Sort:  models.InstancesSort{Key: models.InstanceSortTotalUsers, Descending: true},
+		Value: "1200",
+		ID:    uuid.MustParse("5b5c8a45-4b52-4b5e-9e58-8c2f7a1b0c3d"),
+	}
+
+	got, err := decodeInstancesCursor(*encodeInstancesCursor(want))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = decodeInstancesCursor(encodeCursor(instancesCursor{Sort: "unknown", Value: "1200"}))
+	assert.ErrorIs(t, err, errInvalidCursor)
+
+	_, err = decodeInstancesCursor(encodeCursor(instancesCursor{Sort: "-total_users"}))
+	assert.ErrorIs(t, err, errInvalidCursor)
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	v1 "github.com/cyclimse/fediverse-blahaj/internal/api/v1"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// errInvalidFilter is returned when a filter cannot be applied
+var errInvalidFilter = errors.New("invalid filter")
+
+// errInvalidTimeseries is returned when a time series cannot be computed
+var errInvalidTimeseries = errors.New("invalid time series")
+
+// timeseriesRanges are the default and maximum ranges of the time series, in months, per interval
+var timeseriesRanges = map[models.TimeseriesInterval]struct{ defaultMonths, maxMonths int }{
+	models.TimeseriesDay:   {defaultMonths: 1, maxMonths: 12},
+	models.TimeseriesWeek:  {defaultMonths: 6, maxMonths: 5 * 12},
+	models.TimeseriesMonth: {defaultMonths: 2 * 12, maxMonths: 20 * 12},
+}
+
+// errInvalidSearchQuery is returned when a search query is too short or too long
+var errInvalidSearchQuery = errors.New("invalid search query")
+
+const (
+	// keep in sync with the OpenAPI spec
+	minSearchQueryLength = 2
+	maxSearchQueryLength = 256
+	maxLookupDomains     = 100 // to prevent abuses
+)
+
+func parseInstanceStatus(status string) (models.FediverseInstanceStatus, error) {
+	switch s := models.FediverseInstanceStatus(status); s {
+	case models.FediverseInstanceStatusUnknown, models.FediverseInstanceStatusUp, models.FediverseInstanceStatusDown, models.FediverseInstanceStatusUnhealthy:
+		return s, nil
+	}
+	return "", fmt.Errorf("%w: unknown status %q", errInvalidFilter, status)
+}
+
+// instancesFilterFromParams validates the filters of the instances listing.
+// The software names and the language are matched case-insensitively.
+func instancesFilterFromParams(params v1.ListInstancesParams) (models.InstancesFilter, error) {
+	var filter models.InstancesFilter
+
+	if params.Software != nil {
+		for _, software := range *params.Software {
+			software = strings.ToLower(strings.TrimSpace(software))
+			if software != "" {
+				filter.Software = append(filter.Software, software)
+			}
+		}
+	}
+
+	if params.Status != nil {
+		status, err := parseInstanceStatus(string(*params.Status))
+		if err != nil {
+			return models.InstancesFilter{}, err
+		}
+		filter.Status = status
+	}
+
+	filter.OpenRegistrations = params.OpenRegistrations
+
+	if params.MinUsers != nil && *params.MinUsers < 0 || params.MaxUsers != nil && *params.MaxUsers < 0 {
+		return models.InstancesFilter{}, fmt.Errorf("%w: the number of users must be positive", errInvalidFilter)
+	}
+	if params.MinUsers != nil && params.MaxUsers != nil && *params.MinUsers > *params.MaxUsers {
+		return models.InstancesFilter{}, fmt.Errorf("%w: min_users is greater than max_users", errInvalidFilter)
+	}
+	filter.MinUsers, filter.MaxUsers = params.MinUsers, params.MaxUsers
+
+	if params.Version != nil {
+		filter.VersionPrefix = strings.TrimSpace(*params.Version)
+	}
+
+	if params.Language != nil {
+		filter.Language = strings.ToLower(strings.TrimSpace(*params.Language))
+	}
+
+	return filter, nil
+}
+
+// parseInstancesSort parses the sort of the instances listing,
+// a sort key optionally prefixed with - for a descending order, eg: -total_users.
+func parseInstancesSort(sort string) (models.InstancesSort, error) {
+	if sort == "" {
+		return models.DefaultInstancesSort, nil
+	}
+
+	key, descending := strings.CutPrefix(sort, "-")
+	switch k := models.InstanceSortKey(key); k {
+	case models.InstanceSortTotalUsers,
+		models.InstanceSortActiveUsersMonth,
+		models.InstanceSortLocalPosts,
+		models.InstanceSortNumberOfPeers,
+		models.InstanceSortDomain,
+		models.InstanceSortLastCrawledAt,
+		models.InstanceSortFirstSeenAt,
+		models.InstanceSortUptime24h,
+		models.InstanceSortUptime7d,
+		models.InstanceSortUptime30d,
+		models.InstanceSortUptime90d:
+		return models.InstancesSort{Key: k, Descending: descending}, nil
+	}
+	return models.InstancesSort{}, fmt.Errorf("%w: unknown sort key %q", errInvalidFilter, key)
+}
+
+// formatInstancesSort is the inverse of parseInstancesSort.
+func formatInstancesSort(sort models.InstancesSort) string {
+	if sort.Descending {
+		return "-" + string(sort.Key)
+	}
+	return string(sort.Key)
+}
+
+// parseSearchQuery trims a search query and checks its length, in characters.
+func parseSearchQuery(q string) (string, error) {
+	q = strings.TrimSpace(q)
+	if n := utf8.RuneCountInString(q); n < minSearchQueryLength || n > maxSearchQueryLength {
+		return "", fmt.Errorf("%w: expected between %d and %d characters", errInvalidSearchQuery, minSearchQueryLength, maxSearchQueryLength)
+	}
+	return q, nil
+}
+
+// parseTimeseriesQuery validates the parameters of a time series, and applies the defaults.
+// The range is bounded depending on the interval, to bound the number of points.
+func parseTimeseriesQuery(metric *v1.TimeseriesMetric, interval *v1.TimeseriesInterval, from, to *openapi_types.Date, now time.Time) (models.TimeseriesQuery, error) {
+	q := models.TimeseriesQuery{
+		Metric:   models.TimeseriesUsers,
+		Interval: models.TimeseriesDay,
+	}
+
+	if metric != nil {
+		switch m := models.TimeseriesMetric(*metric); m {
+		case models.TimeseriesUsers, models.TimeseriesActiveUsersMonth, models.TimeseriesLocalPosts, models.TimeseriesUptime:
+			q.Metric = m
+		default:
+			return models.TimeseriesQuery{}, fmt.Errorf("%w: unknown metric %q", errInvalidTimeseries, *metric)
+		}
+	}
+
+	if interval != nil {
+		q.Interval = models.TimeseriesInterval(*interval)
+	}
+	ranges, ok := timeseriesRanges[q.Interval]
+	if !ok {
+		return models.TimeseriesQuery{}, fmt.Errorf("%w: unknown interval %q", errInvalidTimeseries, q.Interval)
+	}
+
+	y, m, d := now.UTC().Date()
+	q.To = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if to != nil {
+		q.To = to.Time
+	}
+	q.From = q.To.AddDate(0, -ranges.defaultMonths, 0)
+	if from != nil {
+		q.From = from.Time
+	}
+
+	if q.From.After(q.To) {
+		return models.TimeseriesQuery{}, fmt.Errorf("%w: from is after to", errInvalidTimeseries)
+	}
+	if q.From.AddDate(0, ranges.maxMonths, 0).Before(q.To) {
+		return models.TimeseriesQuery{}, fmt.Errorf("%w: at most %d months per %s", errInvalidTimeseries, ranges.maxMonths, q.Interval)
+	}
+
+	return q, nil
+}
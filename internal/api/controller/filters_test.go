@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/cyclimse/fediverse-blahaj/internal/api/v1"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancesFilterFromParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  v1.ListInstancesParams
+		want    models.InstancesFilter
+		wantErr bool
+	}{
+		{
+			name:   "no filter",
+			params: v1.ListInstancesParams{},
+			want:   models.InstancesFilter{},
+		},
+		{
+			name: "every filter",
+			params: v1.ListInstancesParams{
+				Software:          &[]string{"Mastodon", " pleroma ", ""},
+				Status:            utils.ValToPtr(v1.ListInstancesParamsStatus("up"), true),
+				OpenRegistrations: utils.ValToPtr(true, true),
+				MinUsers:          utils.ValToPtr(int32(10), true),
+				MaxUsers:          utils.ValToPtr(int32(1000), true),
+				Version:           utils.ValToPtr("4.2", true),
+				Language:          utils.ValToPtr("EN", true),
+			},
+			want: models.InstancesFilter{
+				Software:          []string{"mastodon", "pleroma"},
+				Status:            models.FediverseInstanceStatusUp,
+				OpenRegistrations: utils.ValToPtr(true, true),
+				MinUsers:          utils.ValToPtr(int32(10), true),
+				MaxUsers:          utils.ValToPtr(int32(1000), true),
+				VersionPrefix:     "4.2",
+				Language:          "en",
+			},
+		},
+		{
+			name:    "unknown status",
+			params:  v1.ListInstancesParams{Status: utils.ValToPtr(v1.ListInstancesParamsStatus("sleeping"), true)},
+			wantErr: true,
+		},
+		{
+			name:    "negative users",
+			params:  v1.ListInstancesParams{MinUsers: utils.ValToPtr(int32(-1), true)},
+			wantErr: true,
+		},
+		{
+			name: "min users greater than max users",
+			params: v1.ListInstancesParams{
+				MinUsers: utils.ValToPtr(int32(100), true),
+				MaxUsers: utils.ValToPtr(int32(10), true),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := instancesFilterFromParams(tt.params)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseInstancesSort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		want    models.InstancesSort
+		wantErr bool
+	}{
+		{"", models.DefaultInstancesSort, false},
+		{"total_users", models.InstancesSort{Key: models.InstanceSortTotalUsers}, false},
+		{"-active_users_month", models.InstancesSort{Key: models.InstanceSortActiveUsersMonth, Descending: true}, false},
+		{"domain", models.InstancesSort{Key: models.InstanceSortDomain}, false},
+		{"-first_seen_at", models.InstancesSort{Key: models.InstanceSortFirstSeenAt, Descending: true}, false},
+		{"-uptime_30d", models.InstancesSort{Key: models.InstanceSortUptime30d, Descending: true}, false},
+		{"uptime_1y", models.InstancesSort{}, true},
+		{"+domain", models.InstancesSort{}, true},
+		{"--domain", models.InstancesSort{}, true},
+		{"password", models.InstancesSort{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			got, err := parseInstancesSort(tt.sort)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       string
+		want    string
+		wantErr bool
+	}{
+		{"valid", "mastodon", "mastodon", false},
+		{"trimmed", "  art  ", "art", false},
+		{"two characters", "日本", "日本", false},
+		{"too short", " a ", "", true},
+		{"empty", "", "", true},
+		{"too long", strings.Repeat("a", maxSearchQueryLength+1), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSearchQuery(tt.q)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidSearchQuery)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTimeseriesQuery(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 30, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) *openapi_types.Date {
+		return &openapi_types.Date{Time: time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+	}
+	metric := func(m v1.TimeseriesMetric) *v1.TimeseriesMetric { return &m }
+	interval := func(i v1.TimeseriesInterval) *v1.TimeseriesInterval { return &i }
+
+	tests := []struct {
+		name     string
+		metric   *v1.TimeseriesMetric
+		interval *v1.TimeseriesInterval
+		from, to *openapi_types.Date
+		want     models.TimeseriesQuery
+		wantErr  bool
+	}{
+		{
+			name: "defaults",
+			want: models.TimeseriesQuery{
+				Metric:   models.TimeseriesUsers,
+				Interval: models.TimeseriesDay,
+				From:     date(2026, 9, 19).Time,
+				To:       date(2026, 10, 19).Time,
+			},
+		},
+		{
+			name:     "weekly uptime until a given day",
+			metric:   metric(v1.TimeseriesMetricUptime),
+			interval: interval(v1.Week),
+			to:       date(2026, 6, 1),
+			want: models.TimeseriesQuery{
+				Metric:   models.TimeseriesUptime,
+				Interval: models.TimeseriesWeek,
+				From:     date(2025, 12, 1).Time,
+				To:       date(2026, 6, 1).Time,
+			},
+		},
+		{
+			name:     "single day",
+			interval: interval(v1.Day),
+			from:     date(2026, 1, 1),
+			to:       date(2026, 1, 1),
+			want: models.TimeseriesQuery{
+				Metric:   models.TimeseriesUsers,
+				Interval: models.TimeseriesDay,
+				From:     date(2026, 1, 1).Time,
+				To:       date(2026, 1, 1).Time,
+			},
+		},
+		{"unknown metric", metric("peers"), nil, nil, nil, models.TimeseriesQuery{}, true},
+		{"unknown interval", nil, interval("hour"), nil, nil, models.TimeseriesQuery{}, true},
+		{"from after to", nil, nil, date(2026, 2, 1), date(2026, 1, 1), models.TimeseriesQuery{}, true},
+		{"too many days", nil, interval(v1.Day), date(2024, 1, 1), date(2026, 1, 1), models.TimeseriesQuery{}, true},
+		{"months over two decades", nil, interval(v1.Month), date(2000, 1, 1), date(2026, 1, 1), models.TimeseriesQuery{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeseriesQuery(tt.metric, tt.interval, tt.from, tt.to, now)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidTimeseries)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/cyclimse/fediverse-blahaj/internal/api/v1"
+	"github.com/cyclimse/fediverse-blahaj/internal/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsMiddleware records the duration of the API requests.
+// Requests are labeled with the operationId of the matched route in the OpenAPI spec,
+// routes outside of the spec are not recorded.
+func MetricsMiddleware(baseURL string) (echo.MiddlewareFunc, error) {
+	swagger, err := v1.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+
+	// echo reports the route that matched, eg: /api/v1/instances/:id
+	operations := make(map[string]string)
+	for path, item := range swagger.Paths {
+		route := baseURL + echoPath(path)
+		for method, op := range item.Operations() {
+			operations[method+" "+route] = op.OperationID
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			operationID, ok := operations[c.Request().Method+" "+c.Path()]
+			if !ok {
+				return next(c)
+			}
+
+			start := time.Now()
+			err := next(c)
+
+			code := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				code = he.Code
+			}
+			metrics.APIRequestDuration.WithLabelValues(operationID, strconv.Itoa(code)).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}, nil
+}
+
+// echoPath converts an OpenAPI path template to an echo route, eg: /instances/{id} -> /instances/:id
+func echoPath(path string) string {
+	path = strings.ReplaceAll(path, "{", ":")
+	return strings.ReplaceAll(path, "}", "")
+}
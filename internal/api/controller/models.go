@@ -2,11 +2,13 @@ package controller
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/cyclimse/fediverse-blahaj/internal/api/v1"
 	"github.com/cyclimse/fediverse-blahaj/internal/models"
 	"github.com/cyclimse/fediverse-blahaj/internal/utils"
-	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 func instanceFromModel(instance models.FediverseInstance) v1.Instance {
@@ -15,7 +17,8 @@ func instanceFromModel(instance models.FediverseInstance) v1.Instance {
 		Domain: instance.Domain,
 		Status: v1.InstanceStatus(instance.Status),
 
-		Description: nil,
+		Title:       instance.Title,
+		Description: instance.Description,
 		Software:    instance.SoftwareName,
 		Version:     instance.LastCrawl.SoftwareVersion,
 
@@ -27,6 +30,115 @@ func instanceFromModel(instance models.FediverseInstance) v1.Instance {
 		ActiveUsersMonth:    instance.LastCrawl.ActiveMonth,
 		LocalPosts:          instance.LastCrawl.LocalPosts,
 		LocalComments:       instance.LastCrawl.LocalComments,
+
+		Languages: utils.ValToPtr(instance.LastCrawl.Languages, instance.LastCrawl.Languages != nil),
+
+		Uptime: &v1.Uptime{
+			Last24h: instance.Uptime.Last24h,
+			Last7d:  instance.Uptime.Last7d,
+			Last30d: instance.Uptime.Last30d,
+			Last90d: instance.Uptime.Last90d,
+		},
+	}
+}
+
+func globalStatsFromModel(stats models.GlobalStats) v1.GlobalStats {
+	return v1.GlobalStats{
+		Instances:          stats.Instances,
+		InstancesUp:        stats.Up,
+		InstancesDown:      stats.Down,
+		InstancesUnhealthy: stats.Unhealthy,
+
+		TotalUsers:       stats.TotalUsers,
+		ActiveUsersMonth: stats.ActiveUsersMonth,
+		LocalPosts:       stats.LocalPosts,
+
+		OpenRegistrationsShare: stats.OpenRegistrationsShare,
+		ComputedAt:             stats.ComputedAt,
+	}
+}
+
+func softwareStatsFromModel(stats models.SoftwareStats) v1.SoftwareStats {
+	s := v1.SoftwareStats{
+		Name: stats.Name,
+
+		Instances:          stats.Instances,
+		InstancesUp:        stats.Up,
+		InstancesDown:      stats.Down,
+		InstancesUnhealthy: stats.Unhealthy,
+		UpRatio:            stats.UpRatio(),
+
+		TotalUsers:       stats.TotalUsers,
+		ActiveUsersMonth: stats.ActiveUsersMonth,
+
+		Versions: make([]v1.SoftwareVersion, len(stats.Versions)),
+	}
+	for i, v := range stats.Versions {
+		s.Versions[i] = v1.SoftwareVersion{
+			Version:   utils.ValToPtr(v.Version, v.Version != ""),
+			Fork:      utils.ValToPtr(v.Fork, v.Fork != ""),
+			Instances: v.Instances,
+		}
+	}
+	return s
+}
+
+func timeseriesFromModel(q models.TimeseriesQuery, points []models.TimeseriesPoint) v1.Timeseries {
+	ts := v1.Timeseries{
+		Metric:   v1.TimeseriesMetric(q.Metric),
+		Interval: v1.TimeseriesInterval(q.Interval),
+		From:     openapi_types.Date{Time: q.From},
+		To:       openapi_types.Date{Time: q.To},
+		Points:   make([]v1.TimeseriesPoint, len(points)),
+	}
+	for i, p := range points {
+		ts.Points[i] = v1.TimeseriesPoint{
+			Start: openapi_types.Date{Time: p.Start},
+			Value: p.Value,
+			Min:   p.Min,
+			Max:   p.Max,
+		}
+	}
+	return ts
+}
+
+func incidentFromModel(incident models.Incident, now time.Time) v1.Incident {
+	return v1.Incident{
+		Id:         openapi_types.UUID(incident.ID),
+		InstanceId: openapi_types.UUID(incident.InstanceID),
+		Domain:     incident.Domain,
+
+		StartedAt:       incident.StartedAt,
+		ClosedAt:        incident.ClosedAt,
+		Active:          incident.Active(),
+		DurationSeconds: int64(incident.Duration(now).Seconds()),
+
+		FirstCrawlId: openapi_types.UUID(incident.FirstCrawlID),
+		ErrorCode:    utils.ValToPtr(string(incident.ErrorCode), incident.ErrorCode != ""),
+		FailedCrawls: int32(incident.FailedCrawls),
+	}
+}
+
+func instanceLookupFromModel(lookup models.InstanceLookup) v1.InstanceLookup {
+	l := v1.InstanceLookup{
+		Domain: lookup.Domain,
+		Found:  lookup.Found,
+	}
+	if lookup.Found {
+		l.Id = utils.ValToPtr(openapi_types.UUID(lookup.ID), true)
+		l.Status = utils.ValToPtr(v1.InstanceLookupStatus(lookup.Status), true)
+	}
+	if lookup.Err != nil {
+		l.Error = utils.ValToPtr(lookup.Err.Error(), true)
+	}
+	return l
+}
+
+func searchResultFromModel(result models.SearchResult) v1.SearchResult {
+	return v1.SearchResult{
+		Instance: instanceFromModel(result.Instance),
+		Rank:     result.Rank,
+		Snippet:  utils.ValToPtr(result.Snippet, result.Snippet != ""),
 	}
 }
 
@@ -43,9 +155,11 @@ func crawlFromModel(crawl models.Crawl) v1.Crawl {
 		DurationSeconds:     crawl.FinishedAt.Sub(crawl.StartedAt).Seconds(),
 		Id:                  openapi_types.UUID(crawl.ID),
 		InstanceId:          openapi_types.UUID(crawl.InstanceID),
+		RunId:               utils.ValToPtr(openapi_types.UUID(crawl.RunID), crawl.RunID != uuid.Nil),
 		LocalComments:       crawl.LocalComments,
 		LocalPosts:          crawl.LocalPosts,
 		NumberOfPeers:       crawl.NumberOfPeers,
+		PeersTruncated:      utils.ValToPtr(crawl.PeersTruncated, true),
 		RawNodeinfo:         rawNodeinfo,
 		Status:              v1.CrawlStatus(crawl.Status),
 		TotalUsers:          crawl.TotalUsers,
@@ -58,3 +172,70 @@ func crawlFromModel(crawl models.Crawl) v1.Crawl {
 
 	return c
 }
+
+func peerSummaryFromModel(peer models.Peer) v1.PeerSummary {
+	return v1.PeerSummary{
+		Id:     openapi_types.UUID(peer.ID),
+		Domain: peer.Domain,
+		Status: v1.PeerSummaryStatus(peer.Status),
+
+		Software:   peer.SoftwareName,
+		Version:    peer.SoftwareVersion,
+		TotalUsers: peer.TotalUsers,
+
+		LastCrawledAt: peer.LastCrawledAt,
+		FirstSeenAt:   peer.FirstSeenAt,
+		LastSeenAt:    peer.LastSeenAt,
+	}
+}
+
+func peerChangeFromModel(change models.PeerChange) v1.PeerChange {
+	return v1.PeerChange{
+		InstanceId: openapi_types.UUID(change.PeerID),
+		Domain:     change.Domain,
+		Change:     v1.PeerChangeChange(change.Change),
+		ChangedAt:  change.ChangedAt,
+	}
+}
+
+func crawlRunFromModel(run models.CrawlRun) v1.CrawlRun {
+	config := make(map[string]interface{})
+	// if an error occurs, we can simply ignore it and return an empty config
+	_ = json.Unmarshal(run.Config, &config)
+
+	errorCounts := make(map[string]int, len(run.Stats.Errors))
+	for code, n := range run.Stats.Errors {
+		errorCounts[string(code)] = n
+	}
+
+	software := run.Stats.Software
+	if software == nil {
+		software = make(map[string]int)
+	}
+
+	blockedGroups := run.Stats.BlockedGroups
+	if blockedGroups == nil {
+		blockedGroups = make(map[string]int)
+	}
+
+	return v1.CrawlRun{
+		Id:             openapi_types.UUID(run.ID),
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+		CrawlerVersion: run.CrawlerVersion,
+		Seeds:          run.Seeds,
+		Config:         config,
+		Stats: v1.CrawlRunStats{
+			Attempted:     run.Stats.Attempted,
+			Completed:     run.Stats.Completed,
+			Failed:        run.Stats.Failed,
+			Discovered:    run.Stats.Discovered,
+			Rejected:      run.Stats.Rejected,
+			Blocked:       run.Stats.Blocked,
+			BlockedGroups: blockedGroups,
+			Lost:          run.Stats.Lost,
+			Errors:        errorCounts,
+			Software:      software,
+		},
+	}
+}
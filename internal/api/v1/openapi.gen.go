@@ -38,68 +38,554 @@ const (
 	InstanceStatusUp        InstanceStatus = "up"
 )
 
+// Defines values for InstanceLookupStatus.
+const (
+	InstanceLookupStatusDown      InstanceLookupStatus = "down"
+	InstanceLookupStatusUnhealthy InstanceLookupStatus = "unhealthy"
+	InstanceLookupStatusUnknown   InstanceLookupStatus = "unknown"
+	InstanceLookupStatusUp        InstanceLookupStatus = "up"
+)
+
+// Defines values for PeerChangeChange.
+const (
+	PeerChangeChangeGained PeerChangeChange = "gained"
+	PeerChangeChangeLost   PeerChangeChange = "lost"
+)
+
+// Defines values for PeerSummaryStatus.
+const (
+	PeerSummaryStatusDown      PeerSummaryStatus = "down"
+	PeerSummaryStatusUnhealthy PeerSummaryStatus = "unhealthy"
+	PeerSummaryStatusUnknown   PeerSummaryStatus = "unknown"
+	PeerSummaryStatusUp        PeerSummaryStatus = "up"
+)
+
+// Defines values for TimeseriesInterval.
+const (
+	Day   TimeseriesInterval = "day"
+	Month TimeseriesInterval = "month"
+	Week  TimeseriesInterval = "week"
+)
+
+// Defines values for TimeseriesMetric.
+const (
+	TimeseriesMetricActiveUsersMonth TimeseriesMetric = "active_users_month"
+	TimeseriesMetricLocalPosts       TimeseriesMetric = "local_posts"
+	TimeseriesMetricUptime           TimeseriesMetric = "uptime"
+	TimeseriesMetricUsers            TimeseriesMetric = "users"
+)
+
+// Defines values for ListInstancesParamsStatus.
+const (
+	ListInstancesParamsStatusDown      ListInstancesParamsStatus = "down"
+	ListInstancesParamsStatusUnhealthy ListInstancesParamsStatus = "unhealthy"
+	ListInstancesParamsStatusUnknown   ListInstancesParamsStatus = "unknown"
+	ListInstancesParamsStatusUp        ListInstancesParamsStatus = "up"
+)
+
+// Defines values for ListInstancesParamsSort.
+const (
+	ListInstancesParamsSortActiveUsersMonth      ListInstancesParamsSort = "active_users_month"
+	ListInstancesParamsSortDomain                ListInstancesParamsSort = "domain"
+	ListInstancesParamsSortFirstSeenAt           ListInstancesParamsSort = "first_seen_at"
+	ListInstancesParamsSortLastCrawledAt         ListInstancesParamsSort = "last_crawled_at"
+	ListInstancesParamsSortLocalPosts            ListInstancesParamsSort = "local_posts"
+	ListInstancesParamsSortMinusActiveUsersMonth ListInstancesParamsSort = "-active_users_month"
+	ListInstancesParamsSortMinusDomain           ListInstancesParamsSort = "-domain"
+	ListInstancesParamsSortMinusFirstSeenAt      ListInstancesParamsSort = "-first_seen_at"
+	ListInstancesParamsSortMinusLastCrawledAt    ListInstancesParamsSort = "-last_crawled_at"
+	ListInstancesParamsSortMinusLocalPosts       ListInstancesParamsSort = "-local_posts"
+	ListInstancesParamsSortMinusNumberOfPeers    ListInstancesParamsSort = "-number_of_peers"
+	ListInstancesParamsSortMinusTotalUsers       ListInstancesParamsSort = "-total_users"
+	ListInstancesParamsSortMinusUptime24h        ListInstancesParamsSort = "-uptime_24h"
+	ListInstancesParamsSortMinusUptime30d        ListInstancesParamsSort = "-uptime_30d"
+	ListInstancesParamsSortMinusUptime7d         ListInstancesParamsSort = "-uptime_7d"
+	ListInstancesParamsSortMinusUptime90d        ListInstancesParamsSort = "-uptime_90d"
+	ListInstancesParamsSortNumberOfPeers         ListInstancesParamsSort = "number_of_peers"
+	ListInstancesParamsSortTotalUsers            ListInstancesParamsSort = "total_users"
+	ListInstancesParamsSortUptime24h             ListInstancesParamsSort = "uptime_24h"
+	ListInstancesParamsSortUptime30d             ListInstancesParamsSort = "uptime_30d"
+	ListInstancesParamsSortUptime7d              ListInstancesParamsSort = "uptime_7d"
+	ListInstancesParamsSortUptime90d             ListInstancesParamsSort = "uptime_90d"
+)
+
+// Defines values for ListPeersForInstanceParamsDirection.
+const (
+	Incoming ListPeersForInstanceParamsDirection = "incoming"
+	Outgoing ListPeersForInstanceParamsDirection = "outgoing"
+)
+
+// Defines values for ListPeersForInstanceParamsStatus.
+const (
+	ListPeersForInstanceParamsStatusDown      ListPeersForInstanceParamsStatus = "down"
+	ListPeersForInstanceParamsStatusUnhealthy ListPeersForInstanceParamsStatus = "unhealthy"
+	ListPeersForInstanceParamsStatusUnknown   ListPeersForInstanceParamsStatus = "unknown"
+	ListPeersForInstanceParamsStatusUp        ListPeersForInstanceParamsStatus = "up"
+)
+
+// Defines values for ListPeerChangesForInstanceParamsChange.
+const (
+	ListPeerChangesForInstanceParamsChangeGained ListPeerChangesForInstanceParamsChange = "gained"
+	ListPeerChangesForInstanceParamsChangeLost   ListPeerChangesForInstanceParamsChange = "lost"
+)
+
 // Crawl defines model for Crawl.
 type Crawl struct {
-	ActiveUsersHalfYear  *int32                  `json:"active_users_half_year,omitempty"`
-	ActiveUsersMonth     *int32                  `json:"active_users_month,omitempty"`
-	DurationSeconds      float64                 `json:"duration_seconds"`
-	ErrorCode            *string                 `json:"errorCode,omitempty"`
-	ErrorCodeDescription *string                 `json:"errorCodeDescription,omitempty"`
-	FinishedAt           time.Time               `json:"finished_at"`
-	Id                   openapi_types.UUID      `json:"id"`
-	InstanceId           openapi_types.UUID      `json:"instance_id"`
-	LocalComments        *int32                  `json:"local_comments,omitempty"`
-	LocalPosts           *int32                  `json:"local_posts,omitempty"`
-	NumberOfPeers        *int32                  `json:"number_of_peers,omitempty"`
-	RawNodeinfo          *map[string]interface{} `json:"raw_nodeinfo,omitempty"`
-	StartedAt            time.Time               `json:"started_at"`
-	Status               CrawlStatus             `json:"status"`
-	TotalUsers           *int32                  `json:"total_users,omitempty"`
+	ActiveUsersHalfYear  *int32             `json:"active_users_half_year,omitempty"`
+	ActiveUsersMonth     *int32             `json:"active_users_month,omitempty"`
+	DurationSeconds      float64            `json:"duration_seconds"`
+	ErrorCode            *string            `json:"errorCode,omitempty"`
+	ErrorCodeDescription *string            `json:"errorCodeDescription,omitempty"`
+	FinishedAt           time.Time          `json:"finished_at"`
+	Id                   openapi_types.UUID `json:"id"`
+	InstanceId           openapi_types.UUID `json:"instance_id"`
+	LocalComments        *int32             `json:"local_comments,omitempty"`
+	LocalPosts           *int32             `json:"local_posts,omitempty"`
+	NumberOfPeers        *int32             `json:"number_of_peers,omitempty"`
+
+	// PeersTruncated true if the instance listed more peers than the crawler keeps
+	PeersTruncated *bool                   `json:"peers_truncated,omitempty"`
+	RawNodeinfo    *map[string]interface{} `json:"raw_nodeinfo,omitempty"`
+	RunId          *openapi_types.UUID     `json:"run_id,omitempty"`
+	StartedAt      time.Time               `json:"started_at"`
+	Status         CrawlStatus             `json:"status"`
+	TotalUsers     *int32                  `json:"total_users,omitempty"`
 }
 
 // CrawlStatus defines model for Crawl.Status.
 type CrawlStatus string
 
+// CrawlRun defines model for CrawlRun.
+type CrawlRun struct {
+	// Config configuration of the crawler for this run
+	Config         map[string]interface{} `json:"config"`
+	CrawlerVersion string                 `json:"crawler_version"`
+
+	// FinishedAt not set while the run is in progress
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+	Id         openapi_types.UUID `json:"id"`
+	Seeds      []string           `json:"seeds"`
+	StartedAt  time.Time          `json:"started_at"`
+	Stats      CrawlRunStats      `json:"stats"`
+}
+
+// CrawlRunStats defines model for CrawlRunStats.
+type CrawlRunStats struct {
+	Attempted int `json:"attempted"`
+
+	// Blocked number of peers skipped because their domain is blocked
+	Blocked int `json:"blocked"`
+
+	// BlockedGroups number of blocked peers per registrable domain, e.g. "example.co.uk" for "a.example.co.uk"
+	BlockedGroups map[string]int `json:"blocked_groups"`
+	Completed     int            `json:"completed"`
+
+	// Discovered number of instances discovered during the run
+	Discovered int `json:"discovered"`
+
+	// Errors number of crawls per error code
+	Errors map[string]int `json:"errors"`
+	Failed int            `json:"failed"`
+
+	// Lost number of crawls not persisted because the run was stopped
+	Lost int `json:"lost"`
+
+	// Rejected number of peers rejected because their domain is invalid
+	Rejected int `json:"rejected"`
+
+	// Software number of crawls per software name
+	Software map[string]int `json:"software"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Code    int32  `json:"code"`
 	Message string `json:"message"`
 }
 
+// GlobalStats defines model for GlobalStats.
+type GlobalStats struct {
+	ActiveUsersMonth int64     `json:"active_users_month"`
+	ComputedAt       time.Time `json:"computed_at"`
+
+	// Instances number of instances known
+	Instances          int64 `json:"instances"`
+	InstancesDown      int64 `json:"instances_down"`
+	InstancesUnhealthy int64 `json:"instances_unhealthy"`
+	InstancesUp        int64 `json:"instances_up"`
+	LocalPosts         int64 `json:"local_posts"`
+
+	// OpenRegistrationsShare share of the instances with open registrations, between 0 and 1, among the ones reporting it
+	OpenRegistrationsShare *float64 `json:"open_registrations_share,omitempty"`
+	TotalUsers             int64    `json:"total_users"`
+}
+
+// Incident defines model for Incident.
+type Incident struct {
+	// Active true while the outage lasts
+	Active bool `json:"active"`
+
+	// ClosedAt start of the first completed crawl after the outage, not set while it lasts
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+	Domain   string     `json:"domain"`
+
+	// DurationSeconds duration of the outage, until now while it lasts
+	DurationSeconds int64 `json:"duration_seconds"`
+
+	// ErrorCode error of the first failed crawl
+	ErrorCode *string `json:"error_code,omitempty"`
+
+	// FailedCrawls number of consecutive failed crawls
+	FailedCrawls int32              `json:"failed_crawls"`
+	FirstCrawlId openapi_types.UUID `json:"first_crawl_id"`
+	Id           openapi_types.UUID `json:"id"`
+	InstanceId   openapi_types.UUID `json:"instance_id"`
+
+	// StartedAt start of the first failed crawl
+	StartedAt time.Time `json:"started_at"`
+}
+
 // Instance defines model for Instance.
 type Instance struct {
-	ActiveUsersHalfYear *int32             `json:"active_users_half_year,omitempty"`
-	ActiveUsersMonth    *int32             `json:"active_users_month,omitempty"`
-	Description         *string            `json:"description,omitempty"`
-	Domain              string             `json:"domain"`
-	Id                  openapi_types.UUID `json:"id"`
-	LocalComments       *int32             `json:"local_comments,omitempty"`
-	LocalPosts          *int32             `json:"local_posts,omitempty"`
-	NumberOfPeers       *int32             `json:"number_of_peers,omitempty"`
-	OpenRegistrations   *bool              `json:"open_registrations,omitempty"`
-	Software            *string            `json:"software,omitempty"`
-	Status              InstanceStatus     `json:"status"`
-	TotalUsers          *int32             `json:"total_users,omitempty"`
-	Version             *string            `json:"version,omitempty"`
+	ActiveUsersHalfYear *int32 `json:"active_users_half_year,omitempty"`
+	ActiveUsersMonth    *int32 `json:"active_users_month,omitempty"`
+
+	// Description description reported by the instance, as text
+	Description *string            `json:"description,omitempty"`
+	Domain      string             `json:"domain"`
+	Id          openapi_types.UUID `json:"id"`
+
+	// Languages primary language subtags reported by the instance, not set if not reported
+	Languages         *[]string      `json:"languages,omitempty"`
+	LocalComments     *int32         `json:"local_comments,omitempty"`
+	LocalPosts        *int32         `json:"local_posts,omitempty"`
+	NumberOfPeers     *int32         `json:"number_of_peers,omitempty"`
+	OpenRegistrations *bool          `json:"open_registrations,omitempty"`
+	Software          *string        `json:"software,omitempty"`
+	Status            InstanceStatus `json:"status"`
+
+	// Title name reported by the instance, as text
+	Title      *string `json:"title,omitempty"`
+	TotalUsers *int32  `json:"total_users,omitempty"`
+
+	// Uptime share of the time the instance was up, from 0 to 1, over windows ending at the last crawl run.
+	// Each crawl tells the status of the instance until the next one, the time during which the status is unknown is excluded.
+	// A window is not set if the status is unknown for most of it.
+	Uptime  *Uptime `json:"uptime,omitempty"`
+	Version *string `json:"version,omitempty"`
 }
 
 // InstanceStatus defines model for Instance.Status.
 type InstanceStatus string
 
+// InstanceLookup defines model for InstanceLookup.
+type InstanceLookup struct {
+	// Domain domain as requested
+	Domain string `json:"domain"`
+
+	// Error why the domain is invalid, set if it cannot be normalized
+	Error *string `json:"error,omitempty"`
+	Found bool    `json:"found"`
+
+	// Id ID of the instance, set if found
+	Id *openapi_types.UUID `json:"id,omitempty"`
+
+	// Status status of the instance, set if found
+	Status *InstanceLookupStatus `json:"status,omitempty"`
+}
+
+// InstanceLookupStatus status of the instance, set if found
+type InstanceLookupStatus string
+
+// PeerChange defines model for PeerChange.
+type PeerChange struct {
+	Change PeerChangeChange `json:"change"`
+
+	// ChangedAt start of the crawl in which the change was seen
+	ChangedAt time.Time `json:"changed_at"`
+	Domain    string    `json:"domain"`
+
+	// InstanceId ID of the peer
+	InstanceId openapi_types.UUID `json:"instance_id"`
+}
+
+// PeerChangeChange defines model for PeerChange.Change.
+type PeerChangeChange string
+
+// PeerSummary defines model for PeerSummary.
+type PeerSummary struct {
+	Domain string `json:"domain"`
+
+	// FirstSeenAt when the peering relationship was first seen, not set if it predates the tracking of the peering relationships
+	FirstSeenAt *time.Time         `json:"first_seen_at,omitempty"`
+	Id          openapi_types.UUID `json:"id"`
+
+	// LastCrawledAt not set if the peer has never been crawled
+	LastCrawledAt *time.Time `json:"last_crawled_at,omitempty"`
+
+	// LastSeenAt when the peering relationship was last seen
+	LastSeenAt time.Time         `json:"last_seen_at"`
+	Software   *string           `json:"software,omitempty"`
+	Status     PeerSummaryStatus `json:"status"`
+
+	// TotalUsers not set for small servers
+	TotalUsers *int32  `json:"total_users,omitempty"`
+	Version    *string `json:"version,omitempty"`
+}
+
+// PeerSummaryStatus defines model for PeerSummary.Status.
+type PeerSummaryStatus string
+
+// SearchResult defines model for SearchResult.
+type SearchResult struct {
+	Instance Instance `json:"instance"`
+
+	// Rank relevance of the instance to the query, higher is better
+	Rank float64 `json:"rank"`
+
+	// Snippet excerpt of the description, as HTML: the text is escaped and the matches are in <mark> elements.
+	// Not set if the instance has no description.
+	Snippet *string `json:"snippet,omitempty"`
+}
+
+// SoftwareStats defines model for SoftwareStats.
+type SoftwareStats struct {
+	ActiveUsersMonth   int64  `json:"active_users_month"`
+	Instances          int64  `json:"instances"`
+	InstancesDown      int64  `json:"instances_down"`
+	InstancesUnhealthy int64  `json:"instances_unhealthy"`
+	InstancesUp        int64  `json:"instances_up"`
+	Name               string `json:"name"`
+	TotalUsers         int64  `json:"total_users"`
+
+	// UpRatio share of the instances that are up, between 0 and 1
+	UpRatio float64 `json:"up_ratio"`
+
+	// Versions number of instances per version, the most recent first, then the invalid versions
+	Versions []SoftwareVersion `json:"versions"`
+}
+
+// SoftwareVersion defines model for SoftwareVersion.
+type SoftwareVersion struct {
+	// Fork name of the fork, eg. glitch, not set for the original software
+	Fork      *string `json:"fork,omitempty"`
+	Instances int64   `json:"instances"`
+
+	// Version semantic version without the fork, eg. 4.2.0, not set if the version is not reported or invalid
+	Version *string `json:"version,omitempty"`
+}
+
+// Timeseries defines model for Timeseries.
+type Timeseries struct {
+	From     openapi_types.Date `json:"from"`
+	Interval TimeseriesInterval `json:"interval"`
+
+	// Metric users, monthly active users, local posts, or the share of the crawls that completed
+	Metric TimeseriesMetric   `json:"metric"`
+	Points []TimeseriesPoint  `json:"points"`
+	To     openapi_types.Date `json:"to"`
+}
+
+// TimeseriesInterval defines model for TimeseriesInterval.
+type TimeseriesInterval string
+
+// TimeseriesMetric users, monthly active users, local posts, or the share of the crawls that completed
+type TimeseriesMetric string
+
+// TimeseriesPoint defines model for TimeseriesPoint.
+type TimeseriesPoint struct {
+	// Max highest value of the interval, or highest daily uptime
+	Max *float64 `json:"max,omitempty"`
+
+	// Min lowest value of the interval, or lowest daily uptime
+	Min *float64 `json:"min,omitempty"`
+
+	// Start first day of the interval
+	Start openapi_types.Date `json:"start"`
+
+	// Value last value of the interval, or the uptime over the interval. Not set if never reported.
+	Value *float64 `json:"value,omitempty"`
+}
+
+// Uptime share of the time the instance was up, from 0 to 1, over windows ending at the last crawl run.
+// Each crawl tells the status of the instance until the next one, the time during which the status is unknown is excluded.
+// A window is not set if the status is unknown for most of it.
+type Uptime struct {
+	Last24h *float64 `json:"last_24h,omitempty"`
+	Last30d *float64 `json:"last_30d,omitempty"`
+	Last7d  *float64 `json:"last_7d,omitempty"`
+	Last90d *float64 `json:"last_90d,omitempty"`
+}
+
+// ListCrawlRunsParams defines parameters for ListCrawlRuns.
+type ListCrawlRunsParams struct {
+	// Page page number of results to return
+	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListIncidentsParams defines parameters for ListIncidents.
+type ListIncidentsParams struct {
+	// Active only return the ongoing outages if true, or the ended ones if false
+	Active *bool `form:"active,omitempty" json:"active,omitempty"`
+
+	// Page page number of results to return
+	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
 // ListInstancesParams defines parameters for ListInstances.
 type ListInstancesParams struct {
-	// Software filter by software name.
-	Software *string `form:"software,omitempty" json:"software,omitempty"`
+	// Software filter by software name, can be repeated to match any of them.
+	Software *[]string `form:"software,omitempty" json:"software,omitempty"`
 
-	// Page page number of results to return
+	// Status filter by status.
+	Status *ListInstancesParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// OpenRegistrations filter by whether the instance accepts new users.
+	OpenRegistrations *bool `form:"open_registrations,omitempty" json:"open_registrations,omitempty"`
+
+	// MinUsers only return the instances with at least this number of users.
+	MinUsers *int32 `form:"min_users,omitempty" json:"min_users,omitempty"`
+
+	// MaxUsers only return the instances with at most this number of users.
+	MaxUsers *int32 `form:"max_users,omitempty" json:"max_users,omitempty"`
+
+	// Version filter by software version, matches the versions starting with it.
+	Version *string `form:"version,omitempty" json:"version,omitempty"`
+
+	// Language filter by language, as reported by the instance. Instances that do not report their languages never match.
+	Language *string `form:"language,omitempty" json:"language,omitempty"`
+
+	// Sort sort key, prefixed with - for a descending order.
+	// The instances with the same key are sorted by ID, the instances without a value for the key come last in a descending order, first in an ascending one.
+	Sort *ListInstancesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor cursor returned by the previous page, to fetch the next page. Cannot be used with page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// IncludeTotal return the total number of results, cached for a few minutes
+	IncludeTotal *bool `form:"include_total,omitempty" json:"include_total,omitempty"`
+
+	// Page page number of results to return, deprecated in favor of cursor. Implies include_total.
 	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
 
 	// PerPage number of results to return per page
 	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
 }
 
+// ListInstancesParamsStatus defines parameters for ListInstances.
+type ListInstancesParamsStatus string
+
+// ListInstancesParamsSort defines parameters for ListInstances.
+type ListInstancesParamsSort string
+
+// LookupInstancesJSONBody defines parameters for LookupInstances.
+type LookupInstancesJSONBody struct {
+	Domains []string `json:"domains"`
+}
+
 // ListCrawlsForInstanceParams defines parameters for ListCrawlsForInstance.
 type ListCrawlsForInstanceParams struct {
+	// Cursor cursor returned by the previous page, to fetch the next page. Cannot be used with page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// IncludeTotal return the total number of results
+	IncludeTotal *bool `form:"include_total,omitempty" json:"include_total,omitempty"`
+
+	// Page page number of results to return, deprecated in favor of cursor. Implies include_total.
+	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListIncidentsForInstanceParams defines parameters for ListIncidentsForInstance.
+type ListIncidentsForInstanceParams struct {
+	// Page page number of results to return
+	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListPeersForInstanceParams defines parameters for ListPeersForInstance.
+type ListPeersForInstanceParams struct {
+	// Direction direction of the peering relationship
+	Direction *ListPeersForInstanceParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+
+	// Status filter by status of the peer
+	Status *ListPeersForInstanceParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Software filter by software name of the peer
+	Software *string `form:"software,omitempty" json:"software,omitempty"`
+
+	// Cursor cursor returned by the previous page, to fetch the next page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListPeersForInstanceParamsDirection defines parameters for ListPeersForInstance.
+type ListPeersForInstanceParamsDirection string
+
+// ListPeersForInstanceParamsStatus defines parameters for ListPeersForInstance.
+type ListPeersForInstanceParamsStatus string
+
+// ListPeerChangesForInstanceParams defines parameters for ListPeerChangesForInstance.
+type ListPeerChangesForInstanceParams struct {
+	// Since only return the changes that happened at or after this date
+	Since time.Time `form:"since" json:"since"`
+
+	// Change filter by kind of change
+	Change *ListPeerChangesForInstanceParamsChange `form:"change,omitempty" json:"change,omitempty"`
+
+	// Page page number of results to return
+	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListPeerChangesForInstanceParamsChange defines parameters for ListPeerChangesForInstance.
+type ListPeerChangesForInstanceParamsChange string
+
+// GetInstanceTimeseriesParams defines parameters for GetInstanceTimeseries.
+type GetInstanceTimeseriesParams struct {
+	// Metric metric of the time series
+	Metric *TimeseriesMetric `form:"metric,omitempty" json:"metric,omitempty"`
+
+	// Interval duration covered by each point, the weeks start on Monday
+	Interval *TimeseriesInterval `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// From first day of the time series, in UTC.
+	// Defaults to a month, 6 months or 2 years before the last day, depending on the interval.
+	From *openapi_types.Date `form:"from,omitempty" json:"from,omitempty"`
+
+	// To last day of the time series, in UTC, included. Defaults to today.
+	To *openapi_types.Date `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// SearchInstancesParams defines parameters for SearchInstances.
+type SearchInstancesParams struct {
+	// Q search query, words are matched in any order,
+	// "quoted phrases", "or" and "-word" are supported
+	Q string `form:"q" json:"q"`
+
+	// Page page number of results to return
+	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
+
+	// PerPage number of results to return per page
+	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
+}
+
+// ListSoftwareParams defines parameters for ListSoftware.
+type ListSoftwareParams struct {
 	// Page page number of results to return
 	Page *int32 `form:"page,omitempty" json:"page,omitempty"`
 
@@ -107,17 +593,78 @@ type ListCrawlsForInstanceParams struct {
 	PerPage *int32 `form:"per_page,omitempty" json:"per_page,omitempty"`
 }
 
+// GetGlobalTimeseriesParams defines parameters for GetGlobalTimeseries.
+type GetGlobalTimeseriesParams struct {
+	// Metric metric of the time series
+	Metric *TimeseriesMetric `form:"metric,omitempty" json:"metric,omitempty"`
+
+	// Interval duration covered by each point, the weeks start on Monday
+	Interval *TimeseriesInterval `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// From first day of the time series, in UTC.
+	// Defaults to a month, 6 months or 2 years before the last day, depending on the interval.
+	From *openapi_types.Date `form:"from,omitempty" json:"from,omitempty"`
+
+	// To last day of the time series, in UTC, included. Defaults to today.
+	To *openapi_types.Date `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// LookupInstancesJSONRequestBody defines body for LookupInstances for application/json ContentType.
+type LookupInstancesJSONRequestBody LookupInstancesJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List all crawl runs
+	// (GET /crawl-runs)
+	ListCrawlRuns(ctx echo.Context, params ListCrawlRunsParams) error
+	// Info for a specific crawl run
+	// (GET /crawl-runs/{id})
+	GetCrawlRunByID(ctx echo.Context, id openapi_types.UUID) error
+	// List the outages of the instances
+	// (GET /incidents)
+	ListIncidents(ctx echo.Context, params ListIncidentsParams) error
 	// List all instances
 	// (GET /instances)
 	ListInstances(ctx echo.Context, params ListInstancesParams) error
+	// Find an instance by domain
+	// (GET /instances/by-domain/{domain})
+	GetInstanceByDomain(ctx echo.Context, domain string) error
+	// Find instances by domain
+	// (POST /instances/lookup)
+	LookupInstances(ctx echo.Context) error
 	// Info for a specific instance
 	// (GET /instances/{id})
 	GetInstanceByID(ctx echo.Context, id openapi_types.UUID) error
 	// List all crawls for a instance
 	// (GET /instances/{id}/crawls)
 	ListCrawlsForInstance(ctx echo.Context, id openapi_types.UUID, params ListCrawlsForInstanceParams) error
+	// List the outages of an instance
+	// (GET /instances/{id}/incidents)
+	ListIncidentsForInstance(ctx echo.Context, id openapi_types.UUID, params ListIncidentsForInstanceParams) error
+	// List the peers of an instance
+	// (GET /instances/{id}/peers)
+	ListPeersForInstance(ctx echo.Context, id openapi_types.UUID, params ListPeersForInstanceParams) error
+	// List the peers gained and lost by an instance since a date
+	// (GET /instances/{id}/peers/changes)
+	ListPeerChangesForInstance(ctx echo.Context, id openapi_types.UUID, params ListPeerChangesForInstanceParams) error
+	// Time series of a metric of an instance
+	// (GET /instances/{id}/timeseries)
+	GetInstanceTimeseries(ctx echo.Context, id openapi_types.UUID, params GetInstanceTimeseriesParams) error
+	// Search the instances
+	// (GET /search)
+	SearchInstances(ctx echo.Context, params SearchInstancesParams) error
+	// Stats about each software
+	// (GET /software)
+	ListSoftware(ctx echo.Context, params ListSoftwareParams) error
+	// Stats about a specific software
+	// (GET /software/{name})
+	GetSoftwareByName(ctx echo.Context, name string) error
+	// Global stats about the Fediverse
+	// (GET /stats)
+	GetGlobalStats(ctx echo.Context) error
+	// Time series of a metric over all the instances
+	// (GET /timeseries)
+	GetGlobalTimeseries(ctx echo.Context, params GetGlobalTimeseriesParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -125,19 +672,12 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
-// ListInstances converts echo context to params.
-func (w *ServerInterfaceWrapper) ListInstances(ctx echo.Context) error {
+// ListCrawlRuns converts echo context to params.
+func (w *ServerInterfaceWrapper) ListCrawlRuns(ctx echo.Context) error {
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params ListInstancesParams
-	// ------------- Optional query parameter "software" -------------
-
-	err = runtime.BindQueryParameter("form", true, false, "software", ctx.QueryParams(), &params.Software)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter software: %s", err))
-	}
-
+	var params ListCrawlRunsParams
 	// ------------- Optional query parameter "page" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
@@ -153,12 +693,12 @@ func (w *ServerInterfaceWrapper) ListInstances(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.ListInstances(ctx, params)
+	err = w.Handler.ListCrawlRuns(ctx, params)
 	return err
 }
 
-// GetInstanceByID converts echo context to params.
-func (w *ServerInterfaceWrapper) GetInstanceByID(ctx echo.Context) error {
+// GetCrawlRunByID converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCrawlRunByID(ctx echo.Context) error {
 	var err error
 	// ------------- Path parameter "id" -------------
 	var id openapi_types.UUID
@@ -169,23 +709,23 @@ func (w *ServerInterfaceWrapper) GetInstanceByID(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetInstanceByID(ctx, id)
+	err = w.Handler.GetCrawlRunByID(ctx, id)
 	return err
 }
 
-// ListCrawlsForInstance converts echo context to params.
-func (w *ServerInterfaceWrapper) ListCrawlsForInstance(ctx echo.Context) error {
+// ListIncidents converts echo context to params.
+func (w *ServerInterfaceWrapper) ListIncidents(ctx echo.Context) error {
 	var err error
-	// ------------- Path parameter "id" -------------
-	var id openapi_types.UUID
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListIncidentsParams
+	// ------------- Optional query parameter "active" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "active", ctx.QueryParams(), &params.Active)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter active: %s", err))
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListCrawlsForInstanceParams
 	// ------------- Optional query parameter "page" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
@@ -201,61 +741,658 @@ func (w *ServerInterfaceWrapper) ListCrawlsForInstance(ctx echo.Context) error {
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.ListCrawlsForInstance(ctx, id, params)
+	err = w.Handler.ListIncidents(ctx, params)
 	return err
 }
 
-// This is a simple interface which specifies echo.Route addition functions which
-// are present on both echo.Echo and echo.Group, since we want to allow using
-// either of them for path registration
-type EchoRouter interface {
-	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
-}
+// ListInstances converts echo context to params.
+func (w *ServerInterfaceWrapper) ListInstances(ctx echo.Context) error {
+	var err error
 
-// RegisterHandlers adds each server route to the EchoRouter.
-func RegisterHandlers(router EchoRouter, si ServerInterface) {
-	RegisterHandlersWithBaseURL(router, si, "")
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListInstancesParams
+	// ------------- Optional query parameter "software" -------------
 
-// Registers handlers, and prepends BaseURL to the paths, so that the paths
-// can be served under a prefix.
-func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+	err = runtime.BindQueryParameter("form", true, false, "software", ctx.QueryParams(), &params.Software)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter software: %s", err))
+	}
 
-	wrapper := ServerInterfaceWrapper{
-		Handler: si,
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
 	}
 
-	router.GET(baseURL+"/instances", wrapper.ListInstances)
-	router.GET(baseURL+"/instances/:id", wrapper.GetInstanceByID)
-	router.GET(baseURL+"/instances/:id/crawls", wrapper.ListCrawlsForInstance)
+	// ------------- Optional query parameter "open_registrations" -------------
 
-}
+	err = runtime.BindQueryParameter("form", true, false, "open_registrations", ctx.QueryParams(), &params.OpenRegistrations)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter open_registrations: %s", err))
+	}
 
-type ListInstancesRequestObject struct {
-	Params ListInstancesParams
-}
+	// ------------- Optional query parameter "min_users" -------------
 
-type ListInstancesResponseObject interface {
-	VisitListInstancesResponse(w http.ResponseWriter) error
-}
+	err = runtime.BindQueryParameter("form", true, false, "min_users", ctx.QueryParams(), &params.MinUsers)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min_users: %s", err))
+	}
 
-type ListInstances200JSONResponse struct {
-	Page    int32      `json:"page"`
-	PerPage int32      `json:"per_page"`
-	Results []Instance `json:"results"`
-	Total   int64      `json:"total"`
-}
+	// ------------- Optional query parameter "max_users" -------------
 
-func (response ListInstances200JSONResponse) VisitListInstancesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
+	err = runtime.BindQueryParameter("form", true, false, "max_users", ctx.QueryParams(), &params.MaxUsers)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max_users: %s", err))
+	}
+
+	// ------------- Optional query parameter "version" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "version", ctx.QueryParams(), &params.Version)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter version: %s", err))
+	}
+
+	// ------------- Optional query parameter "language" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "language", ctx.QueryParams(), &params.Language)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter language: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "include_total" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_total", ctx.QueryParams(), &params.IncludeTotal)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter include_total: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListInstances(ctx, params)
+	return err
+}
+
+// GetInstanceByDomain converts echo context to params.
+func (w *ServerInterfaceWrapper) GetInstanceByDomain(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "domain" -------------
+	var domain string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "domain", runtime.ParamLocationPath, ctx.Param("domain"), &domain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter domain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetInstanceByDomain(ctx, domain)
+	return err
+}
+
+// LookupInstances converts echo context to params.
+func (w *ServerInterfaceWrapper) LookupInstances(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.LookupInstances(ctx)
+	return err
+}
+
+// GetInstanceByID converts echo context to params.
+func (w *ServerInterfaceWrapper) GetInstanceByID(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetInstanceByID(ctx, id)
+	return err
+}
+
+// ListCrawlsForInstance converts echo context to params.
+func (w *ServerInterfaceWrapper) ListCrawlsForInstance(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListCrawlsForInstanceParams
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "include_total" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_total", ctx.QueryParams(), &params.IncludeTotal)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter include_total: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListCrawlsForInstance(ctx, id, params)
+	return err
+}
+
+// ListIncidentsForInstance converts echo context to params.
+func (w *ServerInterfaceWrapper) ListIncidentsForInstance(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListIncidentsForInstanceParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListIncidentsForInstance(ctx, id, params)
+	return err
+}
+
+// ListPeersForInstance converts echo context to params.
+func (w *ServerInterfaceWrapper) ListPeersForInstance(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListPeersForInstanceParams
+	// ------------- Optional query parameter "direction" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "direction", ctx.QueryParams(), &params.Direction)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter direction: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "software" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "software", ctx.QueryParams(), &params.Software)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter software: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListPeersForInstance(ctx, id, params)
+	return err
+}
+
+// ListPeerChangesForInstance converts echo context to params.
+func (w *ServerInterfaceWrapper) ListPeerChangesForInstance(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListPeerChangesForInstanceParams
+	// ------------- Required query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "since", ctx.QueryParams(), &params.Since)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since: %s", err))
+	}
+
+	// ------------- Optional query parameter "change" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "change", ctx.QueryParams(), &params.Change)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter change: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListPeerChangesForInstance(ctx, id, params)
+	return err
+}
+
+// GetInstanceTimeseries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetInstanceTimeseries(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetInstanceTimeseriesParams
+	// ------------- Optional query parameter "metric" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "metric", ctx.QueryParams(), &params.Metric)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter metric: %s", err))
+	}
+
+	// ------------- Optional query parameter "interval" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "interval", ctx.QueryParams(), &params.Interval)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter interval: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetInstanceTimeseries(ctx, id, params)
+	return err
+}
+
+// SearchInstances converts echo context to params.
+func (w *ServerInterfaceWrapper) SearchInstances(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchInstancesParams
+	// ------------- Required query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "q", ctx.QueryParams(), &params.Q)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter q: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.SearchInstances(ctx, params)
+	return err
+}
+
+// ListSoftware converts echo context to params.
+func (w *ServerInterfaceWrapper) ListSoftware(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListSoftwareParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "per_page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "per_page", ctx.QueryParams(), &params.PerPage)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter per_page: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListSoftware(ctx, params)
+	return err
+}
+
+// GetSoftwareByName converts echo context to params.
+func (w *ServerInterfaceWrapper) GetSoftwareByName(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetSoftwareByName(ctx, name)
+	return err
+}
+
+// GetGlobalStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGlobalStats(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGlobalStats(ctx)
+	return err
+}
+
+// GetGlobalTimeseries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGlobalTimeseries(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGlobalTimeseriesParams
+	// ------------- Optional query parameter "metric" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "metric", ctx.QueryParams(), &params.Metric)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter metric: %s", err))
+	}
+
+	// ------------- Optional query parameter "interval" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "interval", ctx.QueryParams(), &params.Interval)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter interval: %s", err))
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGlobalTimeseries(ctx, params)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.GET(baseURL+"/crawl-runs", wrapper.ListCrawlRuns)
+	router.GET(baseURL+"/crawl-runs/:id", wrapper.GetCrawlRunByID)
+	router.GET(baseURL+"/incidents", wrapper.ListIncidents)
+	router.GET(baseURL+"/instances", wrapper.ListInstances)
+	router.GET(baseURL+"/instances/by-domain/:domain", wrapper.GetInstanceByDomain)
+	router.POST(baseURL+"/instances/lookup", wrapper.LookupInstances)
+	router.GET(baseURL+"/instances/:id", wrapper.GetInstanceByID)
+	router.GET(baseURL+"/instances/:id/crawls", wrapper.ListCrawlsForInstance)
+	router.GET(baseURL+"/instances/:id/incidents", wrapper.ListIncidentsForInstance)
+	router.GET(baseURL+"/instances/:id/peers", wrapper.ListPeersForInstance)
+	router.GET(baseURL+"/instances/:id/peers/changes", wrapper.ListPeerChangesForInstance)
+	router.GET(baseURL+"/instances/:id/timeseries", wrapper.GetInstanceTimeseries)
+	router.GET(baseURL+"/search", wrapper.SearchInstances)
+	router.GET(baseURL+"/software", wrapper.ListSoftware)
+	router.GET(baseURL+"/software/:name", wrapper.GetSoftwareByName)
+	router.GET(baseURL+"/stats", wrapper.GetGlobalStats)
+	router.GET(baseURL+"/timeseries", wrapper.GetGlobalTimeseries)
+
+}
+
+type ListCrawlRunsRequestObject struct {
+	Params ListCrawlRunsParams
+}
+
+type ListCrawlRunsResponseObject interface {
+	VisitListCrawlRunsResponse(w http.ResponseWriter) error
+}
+
+type ListCrawlRuns200JSONResponse struct {
+	Page    int32      `json:"page"`
+	PerPage int32      `json:"per_page"`
+	Results []CrawlRun `json:"results"`
+	Total   int64      `json:"total"`
+}
+
+func (response ListCrawlRuns200JSONResponse) VisitListCrawlRunsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListCrawlRunsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListCrawlRunsdefaultJSONResponse) VisitListCrawlRunsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetCrawlRunByIDRequestObject struct {
+	Id openapi_types.UUID `json:"id"`
+}
+
+type GetCrawlRunByIDResponseObject interface {
+	VisitGetCrawlRunByIDResponse(w http.ResponseWriter) error
+}
+
+type GetCrawlRunByID200JSONResponse CrawlRun
+
+func (response GetCrawlRunByID200JSONResponse) VisitGetCrawlRunByIDResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetCrawlRunByIDdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetCrawlRunByIDdefaultJSONResponse) VisitGetCrawlRunByIDResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListIncidentsRequestObject struct {
+	Params ListIncidentsParams
+}
+
+type ListIncidentsResponseObject interface {
+	VisitListIncidentsResponse(w http.ResponseWriter) error
+}
+
+type ListIncidents200JSONResponse struct {
+	Page    int32      `json:"page"`
+	PerPage int32      `json:"per_page"`
+	Results []Incident `json:"results"`
+	Total   int64      `json:"total"`
+}
+
+func (response ListIncidents200JSONResponse) VisitListIncidentsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListIncidentsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListIncidentsdefaultJSONResponse) VisitListIncidentsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListInstancesRequestObject struct {
+	Params ListInstancesParams
+}
+
+type ListInstancesResponseObject interface {
+	VisitListInstancesResponse(w http.ResponseWriter) error
+}
+
+type ListInstances200JSONResponse struct {
+	// NextCursor cursor of the next page, not set on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Page only set if the page was requested by number
+	Page    *int32     `json:"page,omitempty"`
+	PerPage int32      `json:"per_page"`
+	Results []Instance `json:"results"`
+
+	// Total only set if include_total is set, cached for a few minutes
+	Total *int64 `json:"total,omitempty"`
+}
+
+func (response ListInstances200JSONResponse) VisitListInstancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
@@ -273,6 +1410,71 @@ func (response ListInstancesdefaultJSONResponse) VisitListInstancesResponse(w ht
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type GetInstanceByDomainRequestObject struct {
+	Domain string `json:"domain"`
+}
+
+type GetInstanceByDomainResponseObject interface {
+	VisitGetInstanceByDomainResponse(w http.ResponseWriter) error
+}
+
+type GetInstanceByDomain302ResponseHeaders struct {
+	Location string
+}
+
+type GetInstanceByDomain302Response struct {
+	Headers GetInstanceByDomain302ResponseHeaders
+}
+
+func (response GetInstanceByDomain302Response) VisitGetInstanceByDomainResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(302)
+	return nil
+}
+
+type GetInstanceByDomaindefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetInstanceByDomaindefaultJSONResponse) VisitGetInstanceByDomainResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type LookupInstancesRequestObject struct {
+	Body *LookupInstancesJSONRequestBody
+}
+
+type LookupInstancesResponseObject interface {
+	VisitLookupInstancesResponse(w http.ResponseWriter) error
+}
+
+type LookupInstances200JSONResponse struct {
+	Results []InstanceLookup `json:"results"`
+}
+
+func (response LookupInstances200JSONResponse) VisitLookupInstancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LookupInstancesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response LookupInstancesdefaultJSONResponse) VisitLookupInstancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type GetInstanceByIDRequestObject struct {
 	Id openapi_types.UUID `json:"id"`
 }
@@ -312,25 +1514,320 @@ type ListCrawlsForInstanceResponseObject interface {
 }
 
 type ListCrawlsForInstance200JSONResponse struct {
-	Page    int32   `json:"page"`
+	// NextCursor cursor of the next page, not set on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Page only set if the page was requested by number
+	Page    *int32  `json:"page,omitempty"`
 	PerPage int32   `json:"per_page"`
 	Results []Crawl `json:"results"`
-	Total   int64   `json:"total"`
+
+	// Total only set if include_total is set
+	Total *int64 `json:"total,omitempty"`
+}
+
+func (response ListCrawlsForInstance200JSONResponse) VisitListCrawlsForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListCrawlsForInstancedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListCrawlsForInstancedefaultJSONResponse) VisitListCrawlsForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListIncidentsForInstanceRequestObject struct {
+	Id     openapi_types.UUID `json:"id"`
+	Params ListIncidentsForInstanceParams
+}
+
+type ListIncidentsForInstanceResponseObject interface {
+	VisitListIncidentsForInstanceResponse(w http.ResponseWriter) error
+}
+
+type ListIncidentsForInstance200JSONResponse struct {
+	Page    int32      `json:"page"`
+	PerPage int32      `json:"per_page"`
+	Results []Incident `json:"results"`
+	Total   int64      `json:"total"`
+}
+
+func (response ListIncidentsForInstance200JSONResponse) VisitListIncidentsForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListIncidentsForInstancedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListIncidentsForInstancedefaultJSONResponse) VisitListIncidentsForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListPeersForInstanceRequestObject struct {
+	Id     openapi_types.UUID `json:"id"`
+	Params ListPeersForInstanceParams
+}
+
+type ListPeersForInstanceResponseObject interface {
+	VisitListPeersForInstanceResponse(w http.ResponseWriter) error
+}
+
+type ListPeersForInstance200JSONResponse struct {
+	// NextCursor cursor of the next page, not set on the last page
+	NextCursor *string       `json:"next_cursor,omitempty"`
+	PerPage    int32         `json:"per_page"`
+	Results    []PeerSummary `json:"results"`
+}
+
+func (response ListPeersForInstance200JSONResponse) VisitListPeersForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListPeersForInstancedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListPeersForInstancedefaultJSONResponse) VisitListPeersForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListPeerChangesForInstanceRequestObject struct {
+	Id     openapi_types.UUID `json:"id"`
+	Params ListPeerChangesForInstanceParams
+}
+
+type ListPeerChangesForInstanceResponseObject interface {
+	VisitListPeerChangesForInstanceResponse(w http.ResponseWriter) error
+}
+
+type ListPeerChangesForInstance200JSONResponse struct {
+	Page    int32        `json:"page"`
+	PerPage int32        `json:"per_page"`
+	Results []PeerChange `json:"results"`
+	Total   int64        `json:"total"`
+}
+
+func (response ListPeerChangesForInstance200JSONResponse) VisitListPeerChangesForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListPeerChangesForInstancedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListPeerChangesForInstancedefaultJSONResponse) VisitListPeerChangesForInstanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetInstanceTimeseriesRequestObject struct {
+	Id     openapi_types.UUID `json:"id"`
+	Params GetInstanceTimeseriesParams
+}
+
+type GetInstanceTimeseriesResponseObject interface {
+	VisitGetInstanceTimeseriesResponse(w http.ResponseWriter) error
+}
+
+type GetInstanceTimeseries200JSONResponse Timeseries
+
+func (response GetInstanceTimeseries200JSONResponse) VisitGetInstanceTimeseriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetInstanceTimeseriesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetInstanceTimeseriesdefaultJSONResponse) VisitGetInstanceTimeseriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type SearchInstancesRequestObject struct {
+	Params SearchInstancesParams
+}
+
+type SearchInstancesResponseObject interface {
+	VisitSearchInstancesResponse(w http.ResponseWriter) error
+}
+
+type SearchInstances200JSONResponse struct {
+	Page    int32          `json:"page"`
+	PerPage int32          `json:"per_page"`
+	Results []SearchResult `json:"results"`
+	Total   int64          `json:"total"`
+}
+
+func (response SearchInstances200JSONResponse) VisitSearchInstancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchInstancesdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response SearchInstancesdefaultJSONResponse) VisitSearchInstancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListSoftwareRequestObject struct {
+	Params ListSoftwareParams
+}
+
+type ListSoftwareResponseObject interface {
+	VisitListSoftwareResponse(w http.ResponseWriter) error
+}
+
+type ListSoftware200JSONResponse struct {
+	Page    int32           `json:"page"`
+	PerPage int32           `json:"per_page"`
+	Results []SoftwareStats `json:"results"`
+	Total   int64           `json:"total"`
+}
+
+func (response ListSoftware200JSONResponse) VisitListSoftwareResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListSoftwaredefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response ListSoftwaredefaultJSONResponse) VisitListSoftwareResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetSoftwareByNameRequestObject struct {
+	Name string `json:"name"`
+}
+
+type GetSoftwareByNameResponseObject interface {
+	VisitGetSoftwareByNameResponse(w http.ResponseWriter) error
+}
+
+type GetSoftwareByName200JSONResponse SoftwareStats
+
+func (response GetSoftwareByName200JSONResponse) VisitGetSoftwareByNameResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetSoftwareByNamedefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
 }
 
-func (response ListCrawlsForInstance200JSONResponse) VisitListCrawlsForInstanceResponse(w http.ResponseWriter) error {
+func (response GetSoftwareByNamedefaultJSONResponse) VisitGetSoftwareByNameResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetGlobalStatsRequestObject struct {
+}
+
+type GetGlobalStatsResponseObject interface {
+	VisitGetGlobalStatsResponse(w http.ResponseWriter) error
+}
+
+type GetGlobalStats200JSONResponse GlobalStats
+
+func (response GetGlobalStats200JSONResponse) VisitGetGlobalStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGlobalStatsdefaultJSONResponse struct {
+	Body       Error
+	StatusCode int
+}
+
+func (response GetGlobalStatsdefaultJSONResponse) VisitGetGlobalStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetGlobalTimeseriesRequestObject struct {
+	Params GetGlobalTimeseriesParams
+}
+
+type GetGlobalTimeseriesResponseObject interface {
+	VisitGetGlobalTimeseriesResponse(w http.ResponseWriter) error
+}
+
+type GetGlobalTimeseries200JSONResponse Timeseries
+
+func (response GetGlobalTimeseries200JSONResponse) VisitGetGlobalTimeseriesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListCrawlsForInstancedefaultJSONResponse struct {
+type GetGlobalTimeseriesdefaultJSONResponse struct {
 	Body       Error
 	StatusCode int
 }
 
-func (response ListCrawlsForInstancedefaultJSONResponse) VisitListCrawlsForInstanceResponse(w http.ResponseWriter) error {
+func (response GetGlobalTimeseriesdefaultJSONResponse) VisitGetGlobalTimeseriesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(response.StatusCode)
 
@@ -339,15 +1836,57 @@ func (response ListCrawlsForInstancedefaultJSONResponse) VisitListCrawlsForInsta
 
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
+	// List all crawl runs
+	// (GET /crawl-runs)
+	ListCrawlRuns(ctx context.Context, request ListCrawlRunsRequestObject) (ListCrawlRunsResponseObject, error)
+	// Info for a specific crawl run
+	// (GET /crawl-runs/{id})
+	GetCrawlRunByID(ctx context.Context, request GetCrawlRunByIDRequestObject) (GetCrawlRunByIDResponseObject, error)
+	// List the outages of the instances
+	// (GET /incidents)
+	ListIncidents(ctx context.Context, request ListIncidentsRequestObject) (ListIncidentsResponseObject, error)
 	// List all instances
 	// (GET /instances)
 	ListInstances(ctx context.Context, request ListInstancesRequestObject) (ListInstancesResponseObject, error)
+	// Find an instance by domain
+	// (GET /instances/by-domain/{domain})
+	GetInstanceByDomain(ctx context.Context, request GetInstanceByDomainRequestObject) (GetInstanceByDomainResponseObject, error)
+	// Find instances by domain
+	// (POST /instances/lookup)
+	LookupInstances(ctx context.Context, request LookupInstancesRequestObject) (LookupInstancesResponseObject, error)
 	// Info for a specific instance
 	// (GET /instances/{id})
 	GetInstanceByID(ctx context.Context, request GetInstanceByIDRequestObject) (GetInstanceByIDResponseObject, error)
 	// List all crawls for a instance
 	// (GET /instances/{id}/crawls)
 	ListCrawlsForInstance(ctx context.Context, request ListCrawlsForInstanceRequestObject) (ListCrawlsForInstanceResponseObject, error)
+	// List the outages of an instance
+	// (GET /instances/{id}/incidents)
+	ListIncidentsForInstance(ctx context.Context, request ListIncidentsForInstanceRequestObject) (ListIncidentsForInstanceResponseObject, error)
+	// List the peers of an instance
+	// (GET /instances/{id}/peers)
+	ListPeersForInstance(ctx context.Context, request ListPeersForInstanceRequestObject) (ListPeersForInstanceResponseObject, error)
+	// List the peers gained and lost by an instance since a date
+	// (GET /instances/{id}/peers/changes)
+	ListPeerChangesForInstance(ctx context.Context, request ListPeerChangesForInstanceRequestObject) (ListPeerChangesForInstanceResponseObject, error)
+	// Time series of a metric of an instance
+	// (GET /instances/{id}/timeseries)
+	GetInstanceTimeseries(ctx context.Context, request GetInstanceTimeseriesRequestObject) (GetInstanceTimeseriesResponseObject, error)
+	// Search the instances
+	// (GET /search)
+	SearchInstances(ctx context.Context, request SearchInstancesRequestObject) (SearchInstancesResponseObject, error)
+	// Stats about each software
+	// (GET /software)
+	ListSoftware(ctx context.Context, request ListSoftwareRequestObject) (ListSoftwareResponseObject, error)
+	// Stats about a specific software
+	// (GET /software/{name})
+	GetSoftwareByName(ctx context.Context, request GetSoftwareByNameRequestObject) (GetSoftwareByNameResponseObject, error)
+	// Global stats about the Fediverse
+	// (GET /stats)
+	GetGlobalStats(ctx context.Context, request GetGlobalStatsRequestObject) (GetGlobalStatsResponseObject, error)
+	// Time series of a metric over all the instances
+	// (GET /timeseries)
+	GetGlobalTimeseries(ctx context.Context, request GetGlobalTimeseriesRequestObject) (GetGlobalTimeseriesResponseObject, error)
 }
 
 type StrictHandlerFunc = strictecho.StrictEchoHandlerFunc
@@ -362,6 +1901,81 @@ type strictHandler struct {
 	middlewares []StrictMiddlewareFunc
 }
 
+// ListCrawlRuns operation middleware
+func (sh *strictHandler) ListCrawlRuns(ctx echo.Context, params ListCrawlRunsParams) error {
+	var request ListCrawlRunsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListCrawlRuns(ctx.Request().Context(), request.(ListCrawlRunsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListCrawlRuns")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListCrawlRunsResponseObject); ok {
+		return validResponse.VisitListCrawlRunsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetCrawlRunByID operation middleware
+func (sh *strictHandler) GetCrawlRunByID(ctx echo.Context, id openapi_types.UUID) error {
+	var request GetCrawlRunByIDRequestObject
+
+	request.Id = id
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetCrawlRunByID(ctx.Request().Context(), request.(GetCrawlRunByIDRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetCrawlRunByID")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetCrawlRunByIDResponseObject); ok {
+		return validResponse.VisitGetCrawlRunByIDResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ListIncidents operation middleware
+func (sh *strictHandler) ListIncidents(ctx echo.Context, params ListIncidentsParams) error {
+	var request ListIncidentsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListIncidents(ctx.Request().Context(), request.(ListIncidentsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListIncidents")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListIncidentsResponseObject); ok {
+		return validResponse.VisitListIncidentsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // ListInstances operation middleware
 func (sh *strictHandler) ListInstances(ctx echo.Context, params ListInstancesParams) error {
 	var request ListInstancesRequestObject
@@ -387,6 +2001,60 @@ func (sh *strictHandler) ListInstances(ctx echo.Context, params ListInstancesPar
 	return nil
 }
 
+// GetInstanceByDomain operation middleware
+func (sh *strictHandler) GetInstanceByDomain(ctx echo.Context, domain string) error {
+	var request GetInstanceByDomainRequestObject
+
+	request.Domain = domain
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetInstanceByDomain(ctx.Request().Context(), request.(GetInstanceByDomainRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetInstanceByDomain")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetInstanceByDomainResponseObject); ok {
+		return validResponse.VisitGetInstanceByDomainResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// LookupInstances operation middleware
+func (sh *strictHandler) LookupInstances(ctx echo.Context) error {
+	var request LookupInstancesRequestObject
+
+	var body LookupInstancesJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.LookupInstances(ctx.Request().Context(), request.(LookupInstancesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "LookupInstances")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(LookupInstancesResponseObject); ok {
+		return validResponse.VisitLookupInstancesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // GetInstanceByID operation middleware
 func (sh *strictHandler) GetInstanceByID(ctx echo.Context, id openapi_types.UUID) error {
 	var request GetInstanceByIDRequestObject
@@ -438,28 +2106,319 @@ func (sh *strictHandler) ListCrawlsForInstance(ctx echo.Context, id openapi_type
 	return nil
 }
 
+// ListIncidentsForInstance operation middleware
+func (sh *strictHandler) ListIncidentsForInstance(ctx echo.Context, id openapi_types.UUID, params ListIncidentsForInstanceParams) error {
+	var request ListIncidentsForInstanceRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListIncidentsForInstance(ctx.Request().Context(), request.(ListIncidentsForInstanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListIncidentsForInstance")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListIncidentsForInstanceResponseObject); ok {
+		return validResponse.VisitListIncidentsForInstanceResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ListPeersForInstance operation middleware
+func (sh *strictHandler) ListPeersForInstance(ctx echo.Context, id openapi_types.UUID, params ListPeersForInstanceParams) error {
+	var request ListPeersForInstanceRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListPeersForInstance(ctx.Request().Context(), request.(ListPeersForInstanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListPeersForInstance")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListPeersForInstanceResponseObject); ok {
+		return validResponse.VisitListPeersForInstanceResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ListPeerChangesForInstance operation middleware
+func (sh *strictHandler) ListPeerChangesForInstance(ctx echo.Context, id openapi_types.UUID, params ListPeerChangesForInstanceParams) error {
+	var request ListPeerChangesForInstanceRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListPeerChangesForInstance(ctx.Request().Context(), request.(ListPeerChangesForInstanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListPeerChangesForInstance")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListPeerChangesForInstanceResponseObject); ok {
+		return validResponse.VisitListPeerChangesForInstanceResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetInstanceTimeseries operation middleware
+func (sh *strictHandler) GetInstanceTimeseries(ctx echo.Context, id openapi_types.UUID, params GetInstanceTimeseriesParams) error {
+	var request GetInstanceTimeseriesRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetInstanceTimeseries(ctx.Request().Context(), request.(GetInstanceTimeseriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetInstanceTimeseries")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetInstanceTimeseriesResponseObject); ok {
+		return validResponse.VisitGetInstanceTimeseriesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// SearchInstances operation middleware
+func (sh *strictHandler) SearchInstances(ctx echo.Context, params SearchInstancesParams) error {
+	var request SearchInstancesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchInstances(ctx.Request().Context(), request.(SearchInstancesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchInstances")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(SearchInstancesResponseObject); ok {
+		return validResponse.VisitSearchInstancesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ListSoftware operation middleware
+func (sh *strictHandler) ListSoftware(ctx echo.Context, params ListSoftwareParams) error {
+	var request ListSoftwareRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListSoftware(ctx.Request().Context(), request.(ListSoftwareRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListSoftware")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListSoftwareResponseObject); ok {
+		return validResponse.VisitListSoftwareResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetSoftwareByName operation middleware
+func (sh *strictHandler) GetSoftwareByName(ctx echo.Context, name string) error {
+	var request GetSoftwareByNameRequestObject
+
+	request.Name = name
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetSoftwareByName(ctx.Request().Context(), request.(GetSoftwareByNameRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetSoftwareByName")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetSoftwareByNameResponseObject); ok {
+		return validResponse.VisitGetSoftwareByNameResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetGlobalStats operation middleware
+func (sh *strictHandler) GetGlobalStats(ctx echo.Context) error {
+	var request GetGlobalStatsRequestObject
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGlobalStats(ctx.Request().Context(), request.(GetGlobalStatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGlobalStats")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGlobalStatsResponseObject); ok {
+		return validResponse.VisitGetGlobalStatsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetGlobalTimeseries operation middleware
+func (sh *strictHandler) GetGlobalTimeseries(ctx echo.Context, params GetGlobalTimeseriesParams) error {
+	var request GetGlobalTimeseriesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGlobalTimeseries(ctx.Request().Context(), request.(GetGlobalTimeseriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGlobalTimeseries")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGlobalTimeseriesResponseObject); ok {
+		return validResponse.VisitGetGlobalTimeseriesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xYTW/jNhD9KwTbo1ZykqIHnZomaWFg0V0gvQWBMJFGFrfix5KjOIbh/16QkiXZVmIt",
-	"2kNa7E2xZjhf772hsuW5lkYrVOR4uuUur1BCeLyxsK79g7HaoCWB4WfISTxj1ji0LqugLrMNgvVvSm0l",
-	"EE+5UHR1ySNOG4Ptn7hCy3fRobPUiqqZjkVjgYRWmcNcq8IduBW6eapx8FONfGrd0Fptb3SB3r5768gK",
-	"tTp4e4sut8L4AJOGpVDCVVhkPtw4MBB+ICFHsQcnURzYNo0oJs2UI1A5ZjPta51DneVayv3MZrSvdTLa",
-	"zfZoe5jpMjOIdq6XhXWmdIFClXrUSf30BXPyBo7A0jc20hFQEzJA1UiePvBG/aX0WvEooLdGQt+qEkSN",
-	"BX+cOII0Qd3CblYlvhT82giLhY8XJjEe1EEhh/iYwGpfwpDa0JE7D8JTmuUdaGc0XaJzsJqC+FEZ4czB",
-	"fiqbZVfku+H9GWYWWoKYfvV/4JM2qDKLK+GoxZQblfqkdY2gAkV0SWuw0zL3Fn8a4wHbPasKoaZq869Q",
-	"KOLPaN303Kbo1U3yDa7sglq2wpJrRZAHEUEJouYpByMIQf7i1rBaoY2F5hFXIP0R9+1v7Przkv2JIH21",
-	"1jtVRCZNkpHPMej4NXPgRSY4UwXEGoeOATNIjrRFBo6BYvjSmpFmBUqtwsiQlQjUWHRMKEYVsk8GlT/p",
-	"Kl4wZzAXpcjDaHnEa5GjcmGKXeLXBvIK2WW8OEjZpUmyXq9jCK9jbVdJ5+uSj8ubuz/u7z5cxou4IlmH",
-	"4aGV7lN5j/ZZeHKf1p0Ek8QPUlA97tnnrkw+mihfxBfxYry+HE8ftkcZ7hsUj8I8X/LdYwtsMIKn/Cqc",
-	"FHEDVAVkJaMjt3yFYcheiUKXlgVP+UfhaNlbeV8LEilA82F7NL9S1ISWPW3YniXMNzfmEe9GxlMuwZEu",
-	"whC8nPCvDdrNgJ+eX1F3Q3qFaJtwmOcG30XHiRhYIWt1gOmSWXRNTc7jxSI19rXY3u0gboElNDXx9CI6",
-	"paEUSkjP8ouprXac0hvZMIOWdbEn00KbvZ7a1WIqN3jpclsszmT66BXCGe0R7Q++XCz2tEcVEAHG1B1z",
-	"ki+ulZkhkcPdZbr1OEO0+rJmXnjatnlrQSjDw48WS57yH5Lhap109+qk36+7/jSwFja9wh7H/fmn89eT",
-	"fRL7I6I9ZvpipsX0BJ5CAWHBQkYeFAMTg3U3228Yw1utaC8+E4k0Cl8M5j4T7Gwi7hopwW468jOo63F2",
-	"u2gkG8lWFLtXteN37KXj183y9px6LG99I7xw78/3FCmR8mpPDK9cAy/CMhumQ7bBMUPO3Ef+MfDnge+0",
-	"6X11++jvaeRLVWpWasugX5n9OKaGn+T+2/Xt/RE+b91v2vZdeWdI+L4/vu+PEwa1/5T5TyyPjoPvcXO0",
-	"qXWKMhKS3e7vAAAA//92k4A/EBMAAA==",
+	"H4sIAAAAAAAC/+w972/ctpL/CqG7j/LadXp9eAYOuDZpcwaSNqiT96UbLGhpdsVniVRJyuu9wP/7gUNS",
+	"EiVqVxs7ffaDP3kl8cdwOL85HH9JMlHVggPXKrn4kqisgIriz9eSbkvzo5aiBqkZ4GuaaXYLq0aBVKuC",
+	"luvVDqg0X9ZCVlQnFwnj+tV5kiZ6V4N9hA3I5D4NO1eC62Jmx7yRVDPBVwoywXMVdMtFc11C14831bXt",
+	"BlIK+VrkYNq7r0pLxjfB1zegMslqM0G04ZpxpgrIV1SHE1MNJ5pVvbm7TiwP2jYNy6PNuNKUZ7Ca2b4U",
+	"GS1Xmagqv2cz0Gc71ULN7mFxuBLrVQ0g5/bCtistG55RDbigvI/bRMsGCFsTXQDxKyclUxpyUgkJBEcg",
+	"uqAc22SGCEGSG4BadVNeC1EC5WZKSbcrLnJgfC16myeu/wmZxgYNn4tbpanUR26z0lQ3iB/gTZVc/JE0",
+	"/IaLLU9S5K0SDCLSZE1ZCXnyOTKEFpqWlilm4dksCv5smDQY/iPBtfTJKFhISL0RTmqX8DkdIw+FwO8N",
+	"H8uBTPA124x32L53kxCxDrZxLSTRBVNENjyJTOfarW5BqpnMGM7OhSYKNNkWrAScWjacMEUYJ7UUGwnK",
+	"rPhROVgBWIHENFQqCrN7QaWku4cQGo7+nxLWyUXyH6ed6D51cvvU79cVNo5SSkAbQ4T71aR+f/28+4jj",
+	"ykM20BRaQ1U7MTCWFdelyG5iMsKKHkM6VhioG1bXkJNryGijcFeZJLmoKMOt9QOl05OsNlI0tYUqz5mZ",
+	"iJYfAmjHfaegcmM66GqQRMKGKS3pdQkOrpTAYrMgywTuqJEBi0wsmptlghywTOhi8D7KDK30iIPHVCZu",
+	"Qe5HoZcLinTtSd4YovLsEUUc6sZHQhgSmcUUDksyo5EjK3YyMjp4KZTet1A3iREAtSFmpUOSQUGwpYoo",
+	"LQw5RVctwYAyhyh9y0mqZPyWliw+jRJrvaUSHh29fmDCaRXB8EAcdAwaVVUBifVw07HuiL/cNrXk01tq",
+	"TID8bFrFVEsOgVycNjgqUIpuYubdYK2O5Hz7GDRvS3FNyylhdtBy/eH7KIQGsc2xsr7l2nms7e2NGeC0",
+	"nVa56XPx5bhODS+AlrrYHd+zntllj6060UPUwFdeBhs0qZUqHHuFyMPX3ijp8LdluiBmFBKMkpJr0FsA",
+	"Ts4I5Tn5LiW0Ek5wCg5GCtRCaiNLmU7SOR7JHlsvurqhBm8JY4Db0c7Gdy0EIOqQhTsQEnCMbS55xnLg",
+	"eopnJpyAzkITjaYbICW1841N/KwUasLeQ2PGb+iaSaVJK8msYCR0rUH2JkpJaCQy3U49jzmtjI/aeTE3",
+	"NQQ4H1jGHqaGa1YSLrZ7oJrmABS3Ky83wxnxW4giK+AtfmIrtN9X+H2vCMoEV5A1ZpuDQYdgT0hvhMZO",
+	"M9dF+0ZedWiQH6SxAQLn0M1hp83R1cBCd0wUddzCfRrhM86tdsonE9gJQy8DZumenKg1BtcukN4poYpo",
+	"uNNHsurccAvlm4ZuYpq4lqyickd8E6Kaa003ag+oXvKwNf70DZP0CN/xSQeAxqq4t6aeSO9bwEeFVFDV",
+	"OQXXqbVoVIXpMiIOOa3g64jp2DCNARZlwQGf/ZNtdZ8m02GPmPToy4up+I1n93dC3DT1mOk7BhlwHr43",
+	"2DDTgtIQ5Q7wBnzYe1tYtI68odRTP9Mko9zwwDUQbjBZsv+Lz7EWDc/jVMQijtrlm6F9105qR0pnaQNH",
+	"gCNNoBt1cPyvp9rBNrc7bEeObfAHAPm6oHwTkehZ+95DtKGMQ+7dtBjX2D4zFKE1rhg39kpW2FfY1zrZ",
+	"APwxDKqBHp/aaiOiDm/shC090L4OawEqpjB/1VRGBezjqzE9o5I2CIoieVsAb9dkPAsJpZWkBasRtzgA",
+	"YjhQKEyTWoJBtML+WtLsxgzQw9FwvEePhZbUGyAHArSsA4oUVBEOtyDJNQB3weJ8Nmg45wPwWVKHztkz",
+	"/hXaK1Q2cSSaYKKqaFkSBfLWenQz1NLD1MwA4THWuAIqs+J3UE0ZcQtZzwTdpxdbU9VARfnNGA8SSrg1",
+	"TYYimWiBz382IHcpKdimAImxYtAa5DxXXXFW1xAhKbjLQNatJOx9RPvhfz++f3eBX4wlYWYFlVETwTZB",
+	"BPO+ojorQBEqDchk2ZydvcoqKm/wFxAoAY27xZL/GvJLu0DkGdGfe7Hks0Ve4jAa3TxH3I8dCAtiWv8W",
+	"kSoMsl58Oci8M4Zq6hXay7NDVrqgGumnqUdhqnnk7aTAzBBjDZK4HqmlYaE0kZAB11Yj4WvuwERrj7RT",
+	"9PybfRzvSe8ftt/Y+xmQs4tyPzwo1qJ/VnysXdY+9vlHJ2RDBloLeTPhlvgYg5A3KYHNgmxKprMiJX2B",
+	"b1oIyTaM07IN+B8MIs+gwZ5aGJAgVJRrlvn9xHipaPQA2O8X54uzwCQx330fpgKXlwg5PiI5ILXiCP/I",
+	"KlAgHXYHuJaiGkXd47jSIG9peYhCu8kufQ88hdCSZfP7vrft79OkFsw58bMYpBvig+kYCw9oMWPBAwQ7",
+	"+HtoSC3qcLgWyv3Yv+yhMIc1Rd2f5HTX84js0xbgJkkTy04x42eEqWBIz5shkeLblOCg5Y5YxiXuLYY/",
+	"CIY/UuJ4KBCs7gwNpWr/KKw14ebHy53Tv39ddvtG9FrRuzH7ofWiNLmlZdPTBBbbuBzfIqes3BEHwCwl",
+	"UMWc/1Js98/nGhw/HXqQ4wmtR5PT3XC2oUUeY10EM7IIuncJ5oWFnJgTzqDBgvSsLuuYeLG1mLPOAXfZ",
+	"RceY51MbH9qj8xHGwPYzTotR+4ZFyZmxdb9L7Sq2jOdiqwjw3Lg61IpoRIX112Vj7MSfaVa4FxrK0vqL",
+	"8eCGO5gwr7ixZgWHtAPLJRJ0IQA3CFPEOT3mJ9xlZZNDvljyHx2IXh30FMW4q9F2aGYYS0Rb+zbkF/RE",
+	"zr8vZqYDYvNXZ/kxzf92VOu/zxz8fnw2j1rI5rBlgmuaIaNARVmZXCS0Zhpo9T9qSzcbkAu0VawBmlzZ",
+	"d+THD5fkI9DKCCFpOhVa1xenp70+ozSCH4nCRBTsjOKvUaAIJTVopYUE49FQTly+iiG2HCrBMb4LZA1U",
+	"NxIwv8ps4m81cDPSq8UZUTVkbM0y9LaNmGQZcIXk7gD/saZZAeR8cRaArC5OT7fb7YLi54WQm1PXV52+",
+	"u3z9869XP5+cL84Wha5Q/WqQlfptfQXylmUQW/cpNjlN2qhwi7MPbplJz/5JzhbfLc4GJtQfXwYQegQt",
+	"etPcnif3n20UnNYsuUhe4UhpUlNdIMWeItudyMYa3hvrXxqaRjRd5slF8o4p7XOrFHaWtAKN3sQfo1MI",
+	"c/LQWewSnW5l9kmCbqS1eZOLBF3hjmZMtyR1+b6Bfv0uEkOoGGeV0YTfxU6op/2HETToR7i5o2CBXE2D",
+	"9uosBhu9c7CdnR2A9HOaSFC1MJRkBj4/O/Ps5g6waV2XjmJP/6msMdwBEgqf2iWezMqLlasjmju0zTYL",
+	"PbHE7UFNy+G8c1INPBB+iNTTTLuYz1EhNiJPxqkx+BGiNl/JaCKVjr1IK6Hcfh+xNfvQY9OMIsA1HO5q",
+	"m8YFrk2aKB/TRT4kJrrWQYwtejx8+oXl95OM/BZaPv5pd/nmECt38ex2QsM5a9BZ4fnFCJKOXVie9DdN",
+	"ywb6jHMoHv5QfphHk2O8d8vz0z+lbb/ka4G2B21VWLchlgCYy3npy/CBXuU+rwXNP5djFM8gMLtOec++",
+	"Q3PKWXlLbmI6TBHBy51hFSFz4z6bhpSsYRskYTg/xo6+WPJLbWzB0eyDHJn0wHBt88WSf1LgnKv/xjwe",
+	"H5LoQkVZIyVwXe6IiblYs22s4C5bFB7gCrdu1B428Woj8FgD0avQfDR076164BY/9tOalmpK2bSpFR09",
+	"Dc8Yx8rtRd++6FtPu89D37bC6jmo2y4nbuSKKi97e2HNSfv5kncx4b3iZc1KbU4ed2HydEoyyk1ygoQa",
+	"EJ9a2HMbQrmPU1QmHOA8I4yjUaVFLrjZKbirS8zHs0o5xny94G2H2WMukezMtEhoyX26Z2HoWS8mREB7",
+	"rNfB8JAEhmkwtgXoAkJtQWiWQa0V4bC1wbopMCOZRccJ7qEeGaT/Uk1KoEiBTPUE/F6gKsbbo4KI2TUS",
+	"52dzxPlhQCtxLJz07vHhjHBOe0LkTzh7JwDKWkIYLzLrYDrgnuT7xfkE9L3LScP9nkN3Pj8vtelM8cyv",
+	"BbkMz9Zy0TuvcLc7/Eg+XQFXGa4CpgwA3/e4VSgz+Q3sUlJLWLM7yC3yTpyBalq7YJ+xC+ViyT+OScYs",
+	"VNEKzEh4aKhaHFy+SSNEZg53qIueegPP9M1E5cKJjEdmT515aT5yQruPHKwZ2OHpJBpJj0tJqePmSXIS",
+	"HtZ5sRW+PZlxoncyI65/Ej4O8yPT5GT8qs2dOGl/DTNkzMCjV2GOUJqcDF/Y0DWGPtPkJHhyD3/Le1/w",
+	"wf1+ddb/Yp/cw9+DT+ZpjoTPGqmEdBKr46xawi0TjULzM20d2S6UbN4vyOs2DbBRnrrxywQ52NmO46Ke",
+	"MEViGNvvRt1nBeSOrYwfVDHeaFATYDCOMe2Vt9Ui5ImuRzqhmWoJ7oq0tQ+OczJS0o1guG1Nb22Ov8XO",
+	"glxWdckwLNsDczHXN3nxR+b4I4aIV44cLyaYwpmwLb13x+OCd2czbsUji8+7MBH7oJ9DR13SZZuqa1jQ",
+	"bsK8hLBv7i51iVyT7tL0GgMaJkyZ93v59es9r4e7Wq2r8gTDmFOO1On1zumn0y/27/1kXOtjkNbdZW77",
+	"HCDYXJD3zg1aXImMmeNUpoh3jRYK36VL3mYLObI1rSTkTNqVuAy+1j6LhJHeQuvm/bR74xXsXm/PgR5J",
+	"F1wznseDrK3qng60Hgqsvjo7j2Uv2sUOl5qkSQE0d8lj74Qlllj/kmKU7tPv74Yr2qsf758Qcf7CeB4E",
+	"P693jr6GRFp2VxiEmqBMr29cYmVr96J1inc7WvJVeBptf7u8j8iFBH9OjQn4qXPAuF1JNLKJQPaDD466",
+	"fxL57gG6xsG8P0BQ0btL+9GrQv94II/Ojx6XeiHZ3z+qDv1aVWLxfDBB0A8/R5y3QtwSmqel5KkxS+ep",
+	"TbLKoUOpTmoecygVSMtneSbVGSJj1Lerey5HUqxbzGjzT7uru/szDNQvQl52OuNJUcK/paP54k6+uJMv",
+	"7uTDs10e0Zf8l3qMTlA/2awX5dTOXm3zbNIh9mciPCdd+JKG8JKG8JKG8FelIfTEUVQAtiUyosLvt0bb",
+	"lCVshsGJ8MjNFUAdFqNYcsYzUR3q6Uv5dWfqCtsvlvyqPeezjuKUCDR32Z+V+LORs14dpdjt7glh0/ad",
+	"OFUUbrt6J4q9V35Ljkx+CG9XuHoF/9J0jCDdZQBYd07bprQckcLyTR25R/TWXhyLx3Isvq2e61famBvx",
+	"O15fQVvc80lqI6sB5uqiU1u8ZI9BjgMaQ9rWhCFYJ4M5fUxoXQOV7bWedvKBfjK2eGmUOfambaIw6qUW",
+	"n4QLUgq+AWmMc00ZV/Y+1/ueHeAAtvPv01O22s2z0lbDjC6/Vjx2KAyuzQ5QTYRsyxUyRdyNx6jcZXbd",
+	"M4DcW5NuWj3cmFizWDtYp6Su/zhWVodKDb24NC8uTVzUWwZ/Hk4NClEva5+u2nAyvhXX17sgwoLShFAr",
+	"cGI6RQc1FqIK5bUrE2tvJZup7fVwutlI2GBVqvCu/Vib9O9gd3mIrnlBb8EoEqxFcCAboFcS4mjV8Ndo",
+	"BFtxIbji3QIcTeD1FRrmkc241ETEhfKVaH1V+OsdAXMxHDFss0JNpQaXt2sMwfeC2woO8SOM9s7+sUB2",
+	"tTRiGmlQG6CHrNTYJ58+vl4s+RvLeCigqa0CkZIf7A9l9Oo52aFFcw1rIaGzaXO6w+OPNlU1LAWwnFI7",
+	"rj7GhMKdQwMlPbiw1Aes8wXpr1CLnO6mzl+0OAqub3ng2mPEiAzrLfhJnrp+7MFnDG/Sce3ICFdY8mxS",
+	"Ov7SlOUJlgOzDbuiE/7/ReDF9LRf0AvFdeulDy/DGHkpSpCU2wSiXS1ac92OqXAAHFctiLO1sWCabq3s",
+	"q371OIwv+WINKSnZTZtFM4o4xSSwLfs2++aNw4Sr0bYVMrcQ2LsDuc0i37nM8iVfJn82wmxVXUiqQC2T",
+	"lCwTIZcJrnOZnJghlolNcG9qm+c/yb9/7hXoFb17B3yji+Ti/L9+QGOsfX4xZV9M2TnFzPpFEJ+FMesY",
+	"8gkmPllcxm4D9ktxxhNWDY5UJ297xXBGRfXE2lpAftA0rK7ZL2Tz0ZfAw2QS38GHL8hHV9DGSjT/LxQI",
+	"3WBVZXTxoSvCY6vyRCMeV12A9aUMyIvEmVU+0f3zr2chcrx5ozzMT0bkWO69Ni5oIBRCuXP6xZDs3mxL",
+	"vy0/7X6l1UFG7p/GdHLI1FccHsWEHiq3Y399jvpj2v0DShwjP9z3J2n99wmgl3Y5IANfJPcrdA9SVa+I",
+	"/D5V82Bd8hZ0//9bfcO9708TwfoGPz/hfX/bB9Buv9m7XyBnZmfcxj9qRKxz6iaowBy1KLKFsvSXGHr3",
+	"dW16FEjAeiuYumWiA/uIYH6A7CVQ9RKoeglUPddAlVE8Rh4MHaf7+/8fAGVMH/35ewAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
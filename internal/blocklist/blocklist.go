@@ -0,0 +1,240 @@
+// Package blocklist decides which domains must not be crawled nor exposed by the API.
+// It is shared by the orchestrator and the business layer.
+package blocklist
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+type Kind string
+
+const (
+	// KindDomain matches the domain and all of its subdomains.
+	// e.g. "ngrok.io" matches "ngrok.io" and "a.ngrok.io" but not "notngrok.io".
+	KindDomain Kind = "domain"
+	// KindWildcard matches the subdomains of the domain, but not the domain itself.
+	// e.g. "*.ngrok.io" matches "a.ngrok.io" but not "ngrok.io".
+	KindWildcard Kind = "wildcard"
+	// KindRegexp matches the domains matching the regular expression.
+	// e.g. "/^mastodon[0-9]+\.example$/".
+	KindRegexp Kind = "regexp"
+)
+
+var (
+	ErrEmptyPattern = errors.New("pattern is empty")
+	ErrPublicSuffix = errors.New("pattern is a public suffix")
+)
+
+// Rule is a single entry of the blocklist.
+type Rule struct {
+	Pattern string
+	Kind    Kind
+	Reason  string
+
+	// domain is the normalized domain for KindDomain and KindWildcard rules
+	domain string
+	re     *regexp.Regexp
+}
+
+func (r Rule) String() string {
+	return r.Pattern
+}
+
+// ParseRule parses a rule from its pattern.
+// Patterns between slashes are regular expressions,
+// patterns starting with "*." are wildcards, all the others are domains.
+//
+// To avoid blocking a whole TLD by mistake, domain rules on a public suffix
+// managed by ICANN (e.g. "com" or "co.uk") are refused: use a wildcard instead.
+func ParseRule(pattern, reason string) (Rule, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" {
+		return Rule{}, ErrEmptyPattern
+	}
+
+	if len(p) > 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+		re, err := regexp.Compile(p[1 : len(p)-1])
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid regexp %q: %w", p, err)
+		}
+		return Rule{Pattern: p, Kind: KindRegexp, Reason: reason, re: re}, nil
+	}
+
+	kind := KindDomain
+	if strings.HasPrefix(p, "*.") {
+		kind = KindWildcard
+		p = strings.TrimPrefix(p, "*.")
+	}
+
+	domain, err := normalize(p)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid domain %q: %w", pattern, err)
+	}
+
+	if kind == KindDomain {
+		if suffix, icann := publicsuffix.PublicSuffix(domain); icann && suffix == domain {
+			return Rule{}, fmt.Errorf("%w: %q, use \"*.%s\" to block all of its subdomains", ErrPublicSuffix, pattern, domain)
+		}
+	}
+
+	r := Rule{Kind: kind, Reason: reason, domain: domain}
+	switch kind {
+	case KindWildcard:
+		r.Pattern = "*." + domain
+	default:
+		r.Pattern = domain
+	}
+	return r, nil
+}
+
+// MustParseRule is like ParseRule but panics on error.
+func MustParseRule(pattern, reason string) Rule {
+	r, err := ParseRule(pattern, reason)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func normalize(domain string) (string, error) {
+	d := strings.TrimSuffix(strings.ToLower(domain), ".")
+	if d == "" {
+		return "", ErrEmptyPattern
+	}
+	return idna.Lookup.ToASCII(d)
+}
+
+// Match describes why a domain is blocked.
+type Match struct {
+	Rule Rule
+	// Group is the registrable domain (eTLD+1) of the blocked domain.
+	Group string
+}
+
+// Blocklist is a set of rules.
+// It is safe for concurrent use and its rules can be replaced at runtime.
+type Blocklist struct {
+	mu sync.RWMutex
+
+	rules []Rule
+
+	domains   map[string]Rule
+	wildcards map[string]Rule
+	regexps   []Rule
+}
+
+func New(rules ...Rule) *Blocklist {
+	b := &Blocklist{}
+	b.Replace(rules...)
+	return b
+}
+
+// Replace replaces all the rules of the blocklist.
+func (b *Blocklist) Replace(rules ...Rule) {
+	domains := make(map[string]Rule, len(rules))
+	wildcards := make(map[string]Rule)
+	var regexps []Rule
+
+	for _, r := range rules {
+		switch r.Kind {
+		case KindDomain:
+			domains[r.domain] = r
+		case KindWildcard:
+			wildcards[r.domain] = r
+		case KindRegexp:
+			regexps = append(regexps, r)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rules = rules
+	b.domains = domains
+	b.wildcards = wildcards
+	b.regexps = regexps
+}
+
+// Rules returns the rules of the blocklist.
+func (b *Blocklist) Rules() []Rule {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.rules
+}
+
+// Len returns the number of rules.
+func (b *Blocklist) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.rules)
+}
+
+// Match returns the rule blocking the domain, if any.
+// The domain is expected to be normalized (see the hostname package).
+func (b *Blocklist) Match(domain string) (Match, bool) {
+	d := strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// walk up the labels, so that "a.b.ngrok.io" is checked against
+	// "a.b.ngrok.io", "b.ngrok.io", "ngrok.io" and finally "io"
+	// matching on label boundaries means "notngrok.io" is never checked against "ngrok.io"
+	parent := d
+	for isSubdomain := false; ; isSubdomain = true {
+		if r, ok := b.domains[parent]; ok {
+			return Match{Rule: r, Group: Group(d)}, true
+		}
+		if r, ok := b.wildcards[parent]; ok && isSubdomain {
+			return Match{Rule: r, Group: Group(d)}, true
+		}
+
+		i := strings.IndexByte(parent, '.')
+		if i < 0 {
+			break
+		}
+		parent = parent[i+1:]
+	}
+
+	for _, r := range b.regexps {
+		if r.re.MatchString(d) {
+			return Match{Rule: r, Group: Group(d)}, true
+		}
+	}
+
+	return Match{}, false
+}
+
+// IsBlocked returns true if the domain is blocked.
+func (b *Blocklist) IsBlocked(domain string) bool {
+	_, ok := b.Match(domain)
+	return ok
+}
+
+// Group returns the registrable domain (eTLD+1) of the domain,
+// according to the Public Suffix List.
+// e.g. "a.b.example.co.uk" is grouped with "example.co.uk".
+// If the domain is itself a public suffix, it is returned as is.
+func Group(domain string) string {
+	group, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return domain
+	}
+	return group
+}
+
+// Default returns the blocklist used when no other source is configured.
+func Default() *Blocklist {
+	return New(
+		MustParseRule("localhost", "Not a public instance."),
+		MustParseRule("ngrok.io", "Tunnels to development instances."),
+		MustParseRule("activitypub-troll.cf", "Spam instance flooding the peers lists."),
+	)
+}
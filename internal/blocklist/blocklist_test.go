@@ -0,0 +1,110 @@
+package blocklist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklist_Match(t *testing.T) {
+	b := New(
+		MustParseRule("localhost", "local"),
+		MustParseRule("ngrok.io", "tunnel"),
+		MustParseRule("*.example.co.uk", "wildcard"),
+		MustParseRule("/^mastodon[0-9]+\\.evil\\.social$/", "regexp"),
+	)
+
+	tests := []struct {
+		domain string
+		want   bool
+		rule   string
+		group  string
+	}{
+		{
+			domain: "localhost",
+			want:   true,
+			rule:   "localhost",
+			group:  "localhost",
+		},
+		{
+			domain: "subdomain.localhost",
+			want:   true,
+			rule:   "localhost",
+		},
+		{
+			domain: "ngrok.io",
+			want:   true,
+			rule:   "ngrok.io",
+		},
+		{
+			domain: "reallylong.toto.subdomain.ngrok.io",
+			want:   true,
+			rule:   "ngrok.io",
+			// ngrok.io is a private public suffix
+			group: "subdomain.ngrok.io",
+		},
+		{
+			domain: "notngrok.io",
+			want:   false,
+		},
+		{
+			domain: "example.co.uk",
+			want:   false,
+		},
+		{
+			domain: "a.b.example.co.uk",
+			want:   true,
+			rule:   "*.example.co.uk",
+			group:  "example.co.uk",
+		},
+		{
+			domain: "mastodon42.evil.social",
+			want:   true,
+			rule:   "/^mastodon[0-9]+\\.evil\\.social$/",
+			group:  "evil.social",
+		},
+		{
+			domain: "mastodon.evil.social",
+			want:   false,
+		},
+		{
+			domain: "mastodon.social",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			m, got := b.Match(tt.domain)
+			assert.Equal(t, tt.want, got)
+			if !tt.want {
+				return
+			}
+			assert.Equal(t, tt.rule, m.Rule.Pattern)
+			assert.NotEmpty(t, m.Rule.Reason)
+			if tt.group != "" {
+				assert.Equal(t, tt.group, m.Group)
+			}
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	r, err := ParseRule(" NGROK.io. ", "tunnel")
+	require.NoError(t, err)
+	assert.Equal(t, KindDomain, r.Kind)
+	assert.Equal(t, "ngrok.io", r.Pattern)
+
+	r, err = ParseRule("*.cf", "tld")
+	require.NoError(t, err)
+	assert.Equal(t, KindWildcard, r.Kind)
+
+	_, err = ParseRule("co.uk", "tld")
+	assert.ErrorIs(t, err, ErrPublicSuffix)
+
+	_, err = ParseRule("/[/", "invalid")
+	assert.Error(t, err)
+
+	_, err = ParseRule("", "empty")
+	assert.ErrorIs(t, err, ErrEmptyPattern)
+}
@@ -0,0 +1,134 @@
+package blocklist
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	// FormatMastodon is the CSV export of the domain blocks of a Mastodon instance.
+	// e.g. "#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate"
+	FormatMastodon Format = "mastodon"
+	// FormatText is a plain-text list with one pattern per line.
+	// Everything after a "#" is a comment, used as the reason of the pattern on the same line.
+	FormatText Format = "text"
+)
+
+// mastodonSeverityBlocked is the only Mastodon severity considered as a block.
+// Silenced instances are still part of the Fediverse and can be crawled.
+const mastodonSeverityBlocked = "suspend"
+
+// Entry is a pattern read from an external list.
+type Entry struct {
+	Pattern string
+	Reason  string
+}
+
+// Parse reads the entries of a list in the given format.
+func Parse(r io.Reader, format Format) ([]Entry, error) {
+	switch format {
+	case FormatMastodon:
+		return ParseMastodonCSV(r)
+	case FormatText:
+		return ParseText(r)
+	}
+	return nil, fmt.Errorf("unknown format: %s", format)
+}
+
+// ParseMastodonCSV reads a Mastodon domain_blocks CSV export.
+// Only suspended domains are returned. Obfuscated domains (e.g. "ex*mple.com")
+// cannot be matched and are skipped.
+func ParseMastodonCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	// some exports omit the trailing empty columns
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimPrefix(strings.TrimSpace(name), "#")] = i
+	}
+
+	domainCol, ok := columns["domain"]
+	if !ok {
+		return nil, errors.New("missing domain column")
+	}
+	severityCol, hasSeverity := columns["severity"]
+	commentCol, hasComment := columns["public_comment"]
+
+	column := func(record []string, i int) string {
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		domain := column(record, domainCol)
+		if domain == "" || strings.Contains(strings.TrimPrefix(domain, "*."), "*") {
+			continue
+		}
+
+		// the severity defaults to "suspend" on Mastodon
+		if hasSeverity {
+			if severity := column(record, severityCol); severity != "" && severity != mastodonSeverityBlocked {
+				continue
+			}
+		}
+
+		e := Entry{Pattern: domain}
+		if hasComment {
+			e.Reason = column(record, commentCol)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// ParseText reads a plain-text list.
+func ParseText(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		var reason string
+		if i := strings.Index(line, "#"); i >= 0 {
+			reason = strings.TrimSpace(line[i+1:])
+			line = line[:i]
+		}
+
+		pattern := strings.TrimSpace(line)
+		if pattern == "" {
+			continue
+		}
+
+		entries = append(entries, Entry{Pattern: pattern, Reason: reason})
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,42 @@
+package blocklist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMastodonCSV(t *testing.T) {
+	export := `#domain,#severity,#reject_media,#reject_reports,#public_comment,#obfuscate
+spam.example,suspend,true,true,Spam,false
+silenced.example,silence,false,false,,false
+ex*mple.com,suspend,false,false,Hidden,true
+*.evil.example,suspend,false,false,"Harassment, spam",false
+`
+
+	entries, err := ParseMastodonCSV(strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Pattern: "spam.example", Reason: "Spam"},
+		{Pattern: "*.evil.example", Reason: "Harassment, spam"},
+	}, entries)
+}
+
+func TestParseText(t *testing.T) {
+	list := `# a list of domains
+spam.example # Spam
+
+*.evil.example
+/^bot[0-9]+\.example$/
+`
+
+	entries, err := ParseText(strings.NewReader(list))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Pattern: "spam.example", Reason: "Spam"},
+		{Pattern: "*.evil.example"},
+		{Pattern: `/^bot[0-9]+\.example$/`},
+	}, entries)
+}
@@ -0,0 +1,122 @@
+package business
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LoadBlocklist replaces the rules of the blocklist
+// with the active entries stored in the database.
+func (b *Business) LoadBlocklist(ctx context.Context) error {
+	rows, err := b.queries.ListActiveBlockedDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]blocklist.Rule, 0, len(rows))
+	for _, row := range rows {
+		r, err := blocklist.ParseRule(row.Pattern, row.Reason)
+		if err != nil {
+			// a single invalid entry should not disable the whole blocklist
+			slog.ErrorContext(ctx, "skipping invalid blocklist entry", "pattern", row.Pattern, "source", row.Source, "error", err)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	b.blocklist.Replace(rules...)
+	b.blockedInstanceDomains.Clear()
+	// the totals exclude the blocked instances
+	b.instanceTotals.Clear()
+	slog.DebugContext(ctx, "loaded blocklist", "rules", len(rules))
+
+	return nil
+}
+
+// blockedDomains returns the domains of the instances blocked by the blocklist,
+// so that the queries can exclude them before paginating and counting.
+// The blocklist cannot be applied in the queries, the regexps are not the ones of Postgres.
+func (b *Business) blockedDomains(ctx context.Context) ([]string, error) {
+	if domains, ok := b.blockedInstanceDomains.Get(struct{}{}); ok {
+		return domains, nil
+	}
+
+	all, err := b.queries.ListInstanceDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// not nil, a null array would exclude every instance
+	domains := make([]string, 0)
+	for _, domain := range all {
+		if b.isBlocked(domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	b.blockedInstanceDomains.Set(struct{}{}, domains)
+	return domains, nil
+}
+
+// WatchBlocklist reloads the blocklist every interval until the context is cancelled.
+// Errors are logged and the previous rules are kept.
+func (b *Business) WatchBlocklist(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.LoadBlocklist(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to reload blocklist", "error", err)
+			}
+		}
+	}
+}
+
+// ImportBlocklist stores the entries in the database.
+// Existing patterns are updated. Invalid patterns are skipped.
+// Returns the number of imported and skipped entries.
+func (b *Business) ImportBlocklist(ctx context.Context, entries []blocklist.Entry, source string, expiresAt *time.Time) (int, int, error) {
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := b.queries.WithTx(tx)
+
+	expires := pgtype.Timestamptz{}
+	if expiresAt != nil {
+		expires = pgtype.Timestamptz{Time: *expiresAt, Valid: true}
+	}
+
+	imported, skipped := 0, 0
+	for _, e := range entries {
+		// store the normalized pattern so that duplicates are detected
+		r, err := blocklist.ParseRule(e.Pattern, e.Reason)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping invalid pattern", "pattern", e.Pattern, "error", err)
+			skipped++
+			continue
+		}
+
+		err = qtx.UpsertBlockedDomain(ctx, db.UpsertBlockedDomainParams{
+			Pattern:   r.Pattern,
+			Reason:    r.Reason,
+			Source:    source,
+			ExpiresAt: expires,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		imported++
+	}
+
+	return imported, skipped, tx.Commit(ctx)
+}
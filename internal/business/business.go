@@ -1,19 +1,40 @@
 package business
 
+//go:generate go run ../../db/sql/gen -o ../../db/sql/instances.sql
 //go:generate go run github.com/sqlc-dev/sqlc/cmd/sqlc generate -f ../../sqlc.yaml
 
 import (
 	"context"
 	"math/rand"
-	"strings"
+	"slices"
+	"sync/atomic"
 	"time"
 
-	"log/slog"
-
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
 	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/incident"
 	"github.com/cyclimse/fediverse-blahaj/internal/models"
-	"github.com/jackc/pgx/v5"
+	"github.com/cyclimse/fediverse-blahaj/internal/schedule"
+	"github.com/cyclimse/fediverse-blahaj/internal/spool"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/cyclimse/fediverse-blahaj/internal/business")
+
+const (
+	// counting the instances scans them all, while the count barely changes between crawl runs
+	instanceTotalsTTL        = 5 * time.Minute
+	instanceTotalsMaxEntries = 1000
+
+	// the blocked instances change with the blocklist, which clears them when it is reloaded,
+	// and with the instances discovered by crawlers using another blocklist
+	blockedDomainsTTL = 5 * time.Minute
+
+	// the global stats are computed again after each crawl run,
+	// or after a while for the crawls written outside of runs, eg: replayed from the spool
+	globalStatsTTL = time.Hour
 )
 
 var (
@@ -25,19 +46,60 @@ var (
 	}
 )
 
-func New(conn *pgxpool.Pool) *Business {
+// InitialSeedDomains returns the domains used to start a crawl when nothing is known yet.
+func InitialSeedDomains() []string {
+	return slices.Clone(initialSeedDomains)
+}
+
+func New(conn *pgxpool.Pool, blocklist *blocklist.Blocklist) *Business {
 	return &Business{
-		conn:                  conn,
-		queries:               db.New(conn),
-		errorCodeDescriptions: newCachedErrorCodeDescriptions(),
+		conn:                   conn,
+		queries:                db.New(conn),
+		blocklist:              blocklist,
+		schedulePolicy:         schedule.DefaultPolicy,
+		incidentPolicy:         incident.DefaultPolicy,
+		errorCodeDescriptions:  newCachedErrorCodeDescriptions(),
+		instanceTotals:         newExpiringCache[string, int64](instanceTotalsTTL, instanceTotalsMaxEntries),
+		blockedInstanceDomains: newExpiringCache[struct{}, []string](blockedDomainsTTL, 1),
+		globalStats:            newExpiringCache[uuid.UUID, models.GlobalStats](globalStatsTTL, 1),
+		softwareStats:          newExpiringCache[uuid.UUID, []models.SoftwareStats](globalStatsTTL, 1),
 	}
 }
 
+// SetSchedulePolicy sets the policy used to schedule the next crawl of an instance.
+func (b *Business) SetSchedulePolicy(p schedule.Policy) {
+	b.schedulePolicy = p
+}
+
+// SetIncidentPolicy sets the policy used to record the outages of the instances.
+func (b *Business) SetIncidentPolicy(p incident.Policy) {
+	b.incidentPolicy = p
+}
+
 type Business struct {
 	conn    *pgxpool.Pool
 	queries *db.Queries
 
+	// shared with the orchestrator
+	blocklist *blocklist.Blocklist
+
+	schedulePolicy schedule.Policy
+	incidentPolicy incident.Policy
+
 	errorCodeDescriptions cachedErrorCodeDescriptions
+	// instanceTotals caches the number of instances per filter
+	instanceTotals *expiringCache[string, int64]
+	// blockedInstanceDomains caches the domains of the blocked instances, see blockedDomains
+	blockedInstanceDomains *expiringCache[struct{}, []string]
+	// globalStats caches the stats per last finished crawl run
+	globalStats *expiringCache[uuid.UUID, models.GlobalStats]
+	// softwareStats caches the stats of every software per last finished crawl run, most used first
+	softwareStats *expiringCache[uuid.UUID, []models.SoftwareStats]
+
+	// spool receives the crawls that could not be written by Run, can be nil
+	spool *spool.Writer
+	// lostCrawls counts the crawls that could not be written by Run, nor to the spool
+	lostCrawls atomic.Int64
 }
 
 func newCachedErrorCodeDescriptions() cachedErrorCodeDescriptions {
@@ -58,40 +120,10 @@ type cachedErrorCodeDescriptions struct {
 	ExpireAfter  time.Duration
 }
 
-// Run runs the business logic.
-// Handles the results from the crawler, but is not aware of the crawler logic.
-func (b *Business) Run(ctx context.Context, crawls chan models.Crawl) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case crawl, ok := <-crawls:
-			if !ok {
-				return nil
-			}
-			slog.InfoContext(ctx, "received crawl", "crawl", crawl.Domain, "status", crawl.Status)
-			// check if the instance is already in the db
-			var instance db.Instance
-			instance, err := b.queries.GetInstanceByDomain(ctx, crawl.Domain)
-			if err != nil {
-				if err != pgx.ErrNoRows {
-					return err
-				}
-				// instance is not in the db, add it
-				instance, err = b.AddInstance(ctx, crawl.Domain, crawl.SoftwareName)
-				if err != nil {
-					slog.ErrorContext(ctx, "failed to add instance", "error", err)
-					return err
-				}
-			}
-			// add the crawl to the instance
-			err = b.AddCrawlToFediverseInstance(ctx, crawl, instance)
-			if err != nil {
-				slog.ErrorContext(ctx, "failed to add crawl to instance", "error", err)
-				return err
-			}
-		}
-	}
+// GetNotDueDomains returns the domains that must not be crawled when discovered as peers:
+// the tombstoned ones, and the ones whose next crawl is not due yet.
+func (b *Business) GetNotDueDomains(ctx context.Context) ([]string, error) {
+	return b.queries.ListNotDueDomains(ctx)
 }
 
 func (b *Business) GetCrawlerSeedDomains(ctx context.Context, count int) ([]string, error) {
@@ -111,9 +143,10 @@ func (b *Business) GetCrawlerSeedDomains(ctx context.Context, count int) ([]stri
 		if len(newDomains) == 0 {
 			break
 		}
+		offset += len(newDomains)
 
 		for _, domain := range newDomains {
-			if !isBlocked(domain) {
+			if !b.isBlocked(domain) {
 				domains = append(domains, domain)
 			}
 		}
@@ -135,18 +168,9 @@ func (b *Business) GetCrawlerSeedDomains(ctx context.Context, count int) ([]stri
 }
 
 // isBlocked returns true if the domain is blocked.
-func isBlocked(domain string) bool {
-	// we need to block domains and subdomains
-	// e.g. blocking ngrok.io should also block a.ngrok.io
-	for _, blockedDomain := range BlockedDomains {
-		if domain == blockedDomain {
-			return true
-		}
-
-		if strings.HasSuffix(domain, blockedDomain) {
-			return true
-		}
+func (b *Business) isBlocked(domain string) bool {
+	if b.blocklist == nil {
+		return false
 	}
-
-	return false
+	return b.blocklist.IsBlocked(domain)
 }
@@ -0,0 +1,61 @@
+package business
+
+import (
+	"sync"
+	"time"
+)
+
+// expiringCache is a concurrency-safe cache whose values expire after a fixed duration.
+// It is cleared once it holds maxEntries, which is enough for the few keys requested in practice.
+type expiringCache[K comparable, V any] struct {
+	mu         sync.Mutex
+	entries    map[K]expiringEntry[V]
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+type expiringEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func newExpiringCache[K comparable, V any](ttl time.Duration, maxEntries int) *expiringCache[K, V] {
+	return &expiringCache[K, V]{
+		entries:    make(map[K]expiringEntry[V]),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the value of the key, if it has not expired.
+func (c *expiringCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (c *expiringCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		clear(c.entries)
+	}
+	c.entries[key] = expiringEntry[V]{value: value, expiresAt: c.now().Add(c.ttl)}
+}
+
+// Clear removes all the entries.
+func (c *expiringCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
@@ -0,0 +1,47 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiringCache(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	c := newExpiringCache[string, int](time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// expired
+	now = now.Add(time.Minute)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+
+	// replacing a key does not clear the cache
+	c.Set("a", 2)
+	c.Set("b", 3)
+	c.Set("b", 4)
+	v, ok = c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+
+	// full
+	c.Set("c", 5)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	v, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 5, v)
+
+	c.Clear()
+	_, ok = c.Get("c")
+	assert.False(t, ok)
+}
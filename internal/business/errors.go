@@ -5,4 +5,12 @@ import "errors"
 var (
 	// ErrInstanceNotFound is returned when a instance is not found
 	ErrInstanceNotFound = errors.New("instance not found")
+	// ErrSoftwareNotFound is returned when no instance runs a software
+	ErrSoftwareNotFound = errors.New("software not found")
+	// ErrCrawlRunNotFound is returned when a crawl run is not found
+	ErrCrawlRunNotFound = errors.New("crawl run not found")
+	// ErrInvalidDomain is returned when a domain cannot be normalized, it wraps the error of the hostname package
+	ErrInvalidDomain = errors.New("invalid domain")
+	// ErrCursorMismatch is returned when a cursor was issued for another sort
+	ErrCursorMismatch = errors.New("cursor does not match the sort")
 )
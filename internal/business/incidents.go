@@ -0,0 +1,199 @@
+package business
+
+import (
+	"context"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/incident"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ListIncidents returns a page of the confirmed incidents, most recent first, small servers excluded.
+// All the incidents are returned if active is nil, otherwise only the open or the closed ones.
+func (b *Business) ListIncidents(ctx context.Context, active *bool, page, pageSize int32) ([]models.Incident, int64, error) {
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := b.queries.ListIncidentsPaginated(ctx, db.ListIncidentsPaginatedParams{
+		SmallServerThreshold: smallServerThreshold,
+		BlockedDomains:       blocked,
+		Active:               pgtype.Bool{Bool: utils.BoolPtrToVal(active), Valid: active != nil},
+		Limit:                pageSize,
+		Offset:               (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	total := rows[0].TotalCount
+
+	incidents := make([]models.Incident, 0, len(rows))
+	for _, row := range rows {
+		incidents = append(incidents, incidentFromRow(db.Incident{
+			ID:           row.ID,
+			InstanceID:   row.InstanceID,
+			FirstCrawlID: row.FirstCrawlID,
+			StartedAt:    row.StartedAt,
+			ErrorCode:    row.ErrorCode,
+			FailedCrawls: row.FailedCrawls,
+			ClosedAt:     row.ClosedAt,
+		}, row.Domain))
+	}
+
+	return incidents, total, nil
+}
+
+// ListIncidentsForInstance returns a page of the confirmed incidents of an instance, most recent first.
+func (b *Business) ListIncidentsForInstance(ctx context.Context, instanceID uuid.UUID, page, pageSize int32) ([]models.Incident, int64, error) {
+	instance, err := b.GetInstanceByID(ctx, instanceID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := b.queries.ListIncidentsForInstancePaginated(ctx, db.ListIncidentsForInstancePaginatedParams{
+		InstanceID: pgtype.UUID{Bytes: instanceID, Valid: true},
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	total := rows[0].TotalCount
+
+	incidents := make([]models.Incident, 0, len(rows))
+	for _, row := range rows {
+		incidents = append(incidents, incidentFromRow(db.Incident{
+			ID:           row.ID,
+			InstanceID:   row.InstanceID,
+			FirstCrawlID: row.FirstCrawlID,
+			StartedAt:    row.StartedAt,
+			ErrorCode:    row.ErrorCode,
+			FailedCrawls: row.FailedCrawls,
+			ClosedAt:     row.ClosedAt,
+		}, instance.Domain))
+	}
+
+	return incidents, total, nil
+}
+
+func incidentFromRow(row db.Incident, domain string) models.Incident {
+	return models.Incident{
+		ID:         row.ID.Bytes,
+		InstanceID: row.InstanceID.Bytes,
+		Domain:     domain,
+
+		StartedAt:    row.StartedAt.Time,
+		FirstCrawlID: row.FirstCrawlID.Bytes,
+		ErrorCode:    models.CrawlErrCode(row.ErrorCode.CrawlErrorCode),
+		FailedCrawls: int(row.FailedCrawls),
+
+		ClosedAt: utils.ValToPtr(row.ClosedAt.Time, row.ClosedAt.Valid),
+	}
+}
+
+// writeIncidents opens, updates and closes the incidents of the instances following their crawls.
+// It must run once the instances are updated: only the crawl which is the last crawl of its instance changes its incident.
+// crawlIDs are the IDs of the crawls, in the same order.
+func (b *Business) writeIncidents(ctx context.Context, qtx *db.Queries, instances map[string]db.Instance, crawls []models.Crawl, crawlIDs []pgtype.UUID) error {
+	instanceIDs := make([]pgtype.UUID, 0, len(crawls))
+	for _, crawl := range crawls {
+		instanceIDs = append(instanceIDs, instances[crawl.Domain].ID)
+	}
+
+	rows, err := qtx.ListOpenIncidentsByInstanceIDs(ctx, instanceIDs)
+	if err != nil {
+		return err
+	}
+	open := make(map[uuid.UUID]db.Incident, len(rows))
+	for _, row := range rows {
+		open[row.InstanceID.Bytes] = row
+	}
+
+	changes := decideIncidents(b.incidentPolicy, instances, open, crawls, crawlIDs)
+
+	var batchErr error
+	if len(changes.created) > 0 {
+		qtx.CreateIncident(ctx, changes.created).Exec(collectBatchError(&batchErr))
+	}
+	if batchErr == nil && len(changes.updated) > 0 {
+		qtx.UpdateIncident(ctx, changes.updated).Exec(collectBatchError(&batchErr))
+	}
+	if batchErr == nil && len(changes.deleted) > 0 {
+		qtx.DeleteIncident(ctx, changes.deleted).Exec(collectBatchError(&batchErr))
+	}
+	return batchErr
+}
+
+// incidentChanges are the statements writing the incidents of a batch of crawls
+type incidentChanges struct {
+	created []db.CreateIncidentParams
+	updated []db.UpdateIncidentParams
+	deleted []db.DeleteIncidentParams
+}
+
+// decideIncidents returns the changes of the incidents following the crawls, see writeIncidents.
+// open are the open incidents per instance ID.
+func decideIncidents(policy incident.Policy, instances map[string]db.Instance, open map[uuid.UUID]db.Incident, crawls []models.Crawl, crawlIDs []pgtype.UUID) incidentChanges {
+	var changes incidentChanges
+	for i, crawl := range crawls {
+		instance := instances[crawl.Domain]
+		row, ok := open[instance.ID.Bytes]
+
+		// the status of the instance is the one before the crawl, the instances are read before being updated
+		d := policy.Next(incidentState(row, ok), instance.Status == db.InstanceStatusUp, crawl)
+		confirmedAt := pgtype.Timestamptz{Time: crawl.StartedAt, Valid: d.Confirmed}
+
+		switch d.Action {
+		case incident.ActionOpen:
+			changes.created = append(changes.created, db.CreateIncidentParams{
+				ID:           pgtype.UUID{Bytes: uuid.New(), Valid: true},
+				InstanceID:   instance.ID,
+				CrawlID:      crawlIDs[i],
+				FailedCrawls: int32(d.FailedCrawls),
+				ConfirmedAt:  confirmedAt,
+			})
+		case incident.ActionUpdate, incident.ActionClose:
+			changes.updated = append(changes.updated, db.UpdateIncidentParams{
+				ID:              row.ID,
+				CrawlID:         crawlIDs[i],
+				FailedCrawls:    int32(d.FailedCrawls),
+				ConfirmedAt:     confirmedAt,
+				RecoveredCrawls: int32(d.RecoveredCrawls),
+				RecoveringSince: pgtype.Timestamptz{Time: d.RecoveringSince, Valid: !d.RecoveringSince.IsZero()},
+				ClosedAt:        pgtype.Timestamptz{Time: d.ClosedAt, Valid: d.Action == incident.ActionClose},
+			})
+		case incident.ActionDiscard:
+			changes.deleted = append(changes.deleted, db.DeleteIncidentParams{
+				ID:      row.ID,
+				CrawlID: crawlIDs[i],
+			})
+		}
+	}
+	return changes
+}
+
+// incidentState returns the state of the open incident of an instance, if any.
+func incidentState(row db.Incident, open bool) incident.State {
+	if !open {
+		return incident.State{}
+	}
+	return incident.State{
+		Open:            true,
+		Confirmed:       row.ConfirmedAt.Valid,
+		FailedCrawls:    int(row.FailedCrawls),
+		RecoveredCrawls: int(row.RecoveredCrawls),
+		RecoveringSince: row.RecoveringSince.Time,
+	}
+}
@@ -0,0 +1,121 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/incident"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncidentState(t *testing.T) {
+	startedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, incident.State{}, incidentState(db.Incident{}, false))
+
+	row := db.Incident{
+		FailedCrawls:    3,
+		ConfirmedAt:     pgtype.Timestamptz{Time: startedAt, Valid: true},
+		RecoveredCrawls: 1,
+		RecoveringSince: pgtype.Timestamptz{Time: startedAt.Add(time.Hour), Valid: true},
+	}
+	assert.Equal(t, incident.State{
+		Open:            true,
+		Confirmed:       true,
+		FailedCrawls:    3,
+		RecoveredCrawls: 1,
+		RecoveringSince: startedAt.Add(time.Hour),
+	}, incidentState(row, true))
+
+	// not confirmed yet
+	assert.Equal(t, incident.State{Open: true, FailedCrawls: 1}, incidentState(db.Incident{FailedCrawls: 1}, true))
+}
+
+func TestIncidentFromRow(t *testing.T) {
+	startedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	row := db.Incident{
+		ID:           pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		InstanceID:   pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		FirstCrawlID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		StartedAt:    pgtype.Timestamptz{Time: startedAt, Valid: true},
+		ErrorCode:    db.NullCrawlErrorCode{CrawlErrorCode: db.CrawlErrorCodeUnreachable, Valid: true},
+		FailedCrawls: 4,
+	}
+
+	active := incidentFromRow(row, "mastodon.social")
+	assert.True(t, active.Active())
+	assert.Equal(t, models.CrawlErrCodeUnreachable, active.ErrorCode)
+	assert.Equal(t, 2*time.Hour, active.Duration(startedAt.Add(2*time.Hour)))
+
+	row.ClosedAt = pgtype.Timestamptz{Time: startedAt.Add(time.Hour), Valid: true}
+	closed := incidentFromRow(row, "mastodon.social")
+	assert.False(t, closed.Active())
+	assert.Equal(t, time.Hour, closed.Duration(startedAt.Add(2*time.Hour)))
+}
+
+func TestDecideIncidents(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	id := func() pgtype.UUID { return pgtype.UUID{Bytes: uuid.New(), Valid: true} }
+
+	instances := map[string]db.Instance{
+		"goes.down":  {ID: id(), Status: db.InstanceStatusUp},
+		"flapping":   {ID: id(), Status: db.InstanceStatusDown},
+		"recovered":  {ID: id(), Status: db.InstanceStatusUp},
+		"still.up":   {ID: id(), Status: db.InstanceStatusUp},
+		"never.seen": {ID: id(), Status: db.InstanceStatusUnknown},
+	}
+	open := map[uuid.UUID]db.Incident{
+		instances["flapping"].ID.Bytes: {ID: id(), FailedCrawls: 1},
+		instances["recovered"].ID.Bytes: {
+			ID:              id(),
+			FailedCrawls:    5,
+			ConfirmedAt:     pgtype.Timestamptz{Time: now.Add(-48 * time.Hour), Valid: true},
+			RecoveredCrawls: 1,
+			RecoveringSince: pgtype.Timestamptz{Time: now.Add(-6 * time.Hour), Valid: true},
+		},
+	}
+
+	failed := func(domain string) models.Crawl {
+		return models.Crawl{Domain: domain, StartedAt: now, Status: models.CrawlStatusFailed, Err: &models.CrawlError{Code: models.CrawlErrCodeTimeout}}
+	}
+	completed := func(domain string) models.Crawl {
+		return models.Crawl{Domain: domain, StartedAt: now, Status: models.CrawlStatusCompleted}
+	}
+	crawls := []models.Crawl{
+		completed("still.up"),
+		failed("goes.down"),
+		completed("flapping"),
+		failed("never.seen"),
+		completed("recovered"),
+	}
+	crawlIDs := []pgtype.UUID{id(), id(), id(), id(), id()}
+
+	got := decideIncidents(incident.DefaultPolicy, instances, open, crawls, crawlIDs)
+
+	if assert.Len(t, got.created, 1) {
+		assert.Equal(t, instances["goes.down"].ID, got.created[0].InstanceID)
+		assert.Equal(t, crawlIDs[1], got.created[0].CrawlID)
+		assert.Equal(t, int32(1), got.created[0].FailedCrawls)
+		assert.False(t, got.created[0].ConfirmedAt.Valid)
+	}
+
+	assert.Equal(t, []db.DeleteIncidentParams{
+		{ID: open[instances["flapping"].ID.Bytes].ID, CrawlID: crawlIDs[2]},
+	}, got.deleted)
+
+	assert.Equal(t, []db.UpdateIncidentParams{
+		{
+			ID:              open[instances["recovered"].ID.Bytes].ID,
+			CrawlID:         crawlIDs[4],
+			FailedCrawls:    5,
+			ConfirmedAt:     pgtype.Timestamptz{Time: now, Valid: true},
+			RecoveredCrawls: 2,
+			RecoveringSince: pgtype.Timestamptz{Time: now.Add(-6 * time.Hour), Valid: true},
+			ClosedAt:        pgtype.Timestamptz{Time: now.Add(-6 * time.Hour), Valid: true},
+		},
+	}, got.updated)
+}
@@ -0,0 +1,82 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/hostname"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetInstanceIDByDomain returns the ID of the instance with the given domain, once normalized.
+// The instances that cannot be fetched by ID are not found.
+func (b *Business) GetInstanceIDByDomain(ctx context.Context, domain string) (uuid.UUID, error) {
+	normalized, err := hostname.Normalize(domain)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrInvalidDomain, err)
+	}
+
+	row, err := b.queries.GetInstanceByDomain(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrInstanceNotFound
+		}
+		return uuid.Nil, err
+	}
+	if !b.isExposed(row) {
+		return uuid.Nil, ErrInstanceNotFound
+	}
+
+	return row.ID.Bytes, nil
+}
+
+// LookupInstances returns the status of the instances with the given domains, in the same order.
+func (b *Business) LookupInstances(ctx context.Context, domains []string) ([]models.InstanceLookup, error) {
+	lookups := make([]models.InstanceLookup, len(domains))
+	normalized := make([]string, len(domains))
+	valid := make([]string, 0, len(domains))
+	for i, domain := range domains {
+		lookups[i].Domain = domain
+
+		n, err := hostname.Normalize(domain)
+		if err != nil {
+			lookups[i].Err = fmt.Errorf("%w: %w", ErrInvalidDomain, err)
+			continue
+		}
+		normalized[i] = n
+		valid = append(valid, n)
+	}
+
+	rows, err := b.queries.GetInstancesByDomains(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]db.Instance, len(rows))
+	for _, row := range rows {
+		if b.isExposed(row) {
+			instances[row.Domain] = row
+		}
+	}
+
+	for i := range lookups {
+		row, ok := instances[normalized[i]]
+		if !ok {
+			continue
+		}
+		lookups[i].Found = true
+		lookups[i].ID = row.ID.Bytes
+		lookups[i].Status = models.FediverseInstanceStatus(row.Status)
+	}
+
+	return lookups, nil
+}
+
+// isExposed returns true if the instance can be fetched by ID:
+// it is not deleted nor blocked, and it has been crawled.
+func (b *Business) isExposed(instance db.Instance) bool {
+	return !instance.DeletedAt.Valid && instance.LastCrawlID.Valid && !b.isBlocked(instance.Domain)
+}
@@ -0,0 +1,36 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExposed(t *testing.T) {
+	rule, err := blocklist.ParseRule("*.blocked.example", "test")
+	require.NoError(t, err)
+	b := &Business{blocklist: blocklist.New(rule)}
+
+	crawled := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	tests := []struct {
+		name     string
+		instance db.Instance
+		want     bool
+	}{
+		{"crawled", db.Instance{Domain: "mastodon.social", LastCrawlID: crawled}, true},
+		{"never crawled", db.Instance{Domain: "mastodon.social"}, false},
+		{"deleted", db.Instance{Domain: "mastodon.social", LastCrawlID: crawled, DeletedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, false},
+		{"blocked", db.Instance{Domain: "social.blocked.example", LastCrawlID: crawled}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, b.isExposed(tt.instance))
+		})
+	}
+}
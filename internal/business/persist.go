@@ -0,0 +1,435 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"log/slog"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/hostname"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/schedule"
+	"github.com/cyclimse/fediverse-blahaj/internal/spool"
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// persistBatchSize is the maximum number of crawls written in a single transaction
+	persistBatchSize = 50
+	// persistFlushInterval bounds the time a crawl waits for its batch to be full
+	persistFlushInterval = time.Second
+
+	// peersChunkSize is the number of peers inserted per statement.
+	peersChunkSize = 1000
+)
+
+// Run runs the business logic.
+// Handles the results from the crawler, but is not aware of the crawler logic.
+// The crawls are written in batches, a crawl that cannot be written is logged and counted as lost.
+// It returns once the crawls channel is closed, or once the context is done.
+func (b *Business) Run(ctx context.Context, crawls <-chan models.Crawl) error {
+	batch := make([]models.Crawl, 0, persistBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			b.persistCrawls(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+
+	ticker := time.NewTicker(persistFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			flush()
+		case crawl, ok := <-crawls:
+			if !ok {
+				flush()
+				return nil
+			}
+
+			crawl, ok = b.prepareCrawl(ctx, crawl)
+			if !ok {
+				continue
+			}
+
+			// an instance is updated at most once per batch
+			// so that its state is read after the previous update
+			if containsDomain(batch, crawl.Domain) {
+				flush()
+			}
+
+			batch = append(batch, crawl)
+			if len(batch) >= persistBatchSize {
+				flush()
+			}
+		}
+	}
+}
+
+// LostCrawls returns the number of crawls that could not be written by Run,
+// nor to the spool.
+func (b *Business) LostCrawls() int {
+	return int(b.lostCrawls.Load())
+}
+
+// SetSpool sets the spool receiving the crawls that cannot be written to the database.
+func (b *Business) SetSpool(w *spool.Writer) {
+	b.spool = w
+}
+
+// WriteCrawls writes crawls, eg: replayed from the spool.
+// Crawls already written are ignored, so the same crawls can be written several times.
+// Returns the number of crawls written, and of crawls ignored as they were already written.
+// The other crawls are skipped, eg: their domain is blocked.
+func (b *Business) WriteCrawls(ctx context.Context, crawls []models.Crawl) (written, duplicates int, err error) {
+	batch := make([]models.Crawl, 0, len(crawls))
+	write := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		var inserted int
+		err := withRetry(ctx, func() error {
+			var err error
+			inserted, err = b.writeCrawls(ctx, batch)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		written += inserted
+		duplicates += len(batch) - inserted
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, crawl := range crawls {
+		crawl, ok := b.prepareCrawl(ctx, crawl)
+		if !ok {
+			continue
+		}
+		if containsDomain(batch, crawl.Domain) {
+			if err := write(); err != nil {
+				return written, duplicates, err
+			}
+		}
+		batch = append(batch, crawl)
+	}
+
+	err = write()
+	return written, duplicates, err
+}
+
+// prepareCrawl checks that a crawl can be written.
+// Returns false if the crawl must be skipped.
+func (b *Business) prepareCrawl(ctx context.Context, crawl models.Crawl) (models.Crawl, bool) {
+	slog.InfoContext(ctx, "received crawl", "crawl", crawl.Domain, "status", crawl.Status)
+	domain, err := hostname.Normalize(crawl.Domain)
+	if err != nil {
+		// should not happen as the orchestrator only crawls valid domains
+		slog.ErrorContext(ctx, "skipping crawl with invalid domain", "domain", crawl.Domain, "error", err)
+		return crawl, false
+	}
+	crawl.Domain = domain
+	// the blocklist may have been reloaded since the domain was requested
+	if b.isBlocked(crawl.Domain) {
+		slog.InfoContext(ctx, "skipping crawl of blocked domain", "domain", crawl.Domain)
+		return crawl, false
+	}
+
+	// the crawler already normalizes the peers,
+	// but the business layer should not rely on it
+	peers := hostname.NormalizeAll(crawl.Peers, nil)
+
+	// blocked domains must not be created as instances at all
+	allowed := peers[:0]
+	for _, peer := range peers {
+		if !b.isBlocked(peer) {
+			allowed = append(allowed, peer)
+		}
+	}
+	crawl.Peers = allowed
+
+	return crawl, true
+}
+
+// persistCrawls writes a batch of crawls.
+// If the database is unavailable, the crawls are written to the spool.
+// Otherwise, if the batch cannot be written as a whole, the crawls are written one by one
+// so that a single invalid crawl does not cause the loss of the others.
+func (b *Business) persistCrawls(ctx context.Context, crawls []models.Crawl) {
+	links := make([]trace.Link, 0, len(crawls))
+	for _, crawl := range crawls {
+		links = append(links, trace.Link{SpanContext: crawl.SpanContext})
+	}
+	ctx, span := tracer.Start(ctx, "business.PersistCrawls",
+		trace.WithLinks(links...),
+		trace.WithAttributes(attribute.Int("crawls", len(crawls))),
+	)
+	defer span.End()
+
+	err := withRetry(ctx, func() error {
+		_, err := b.writeCrawls(ctx, crawls)
+		return err
+	})
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	if isTransient(err) || len(crawls) == 1 {
+		b.spoolCrawls(ctx, crawls, err)
+		return
+	}
+
+	slog.WarnContext(ctx, "failed to write batch of crawls, writing them one by one", "crawls", len(crawls), "error", err)
+	for i := range crawls {
+		b.persistCrawls(ctx, crawls[i:i+1])
+	}
+}
+
+// spoolCrawls writes the crawls that failed to be written to the database to the spool.
+func (b *Business) spoolCrawls(ctx context.Context, crawls []models.Crawl, cause error) {
+	for _, crawl := range crawls {
+		if b.spool == nil {
+			b.lostCrawls.Add(1)
+			slog.ErrorContext(ctx, "failed to write crawl", "domain", crawl.Domain, "error", cause)
+			continue
+		}
+
+		if err := b.spool.Write(crawl); err != nil {
+			b.lostCrawls.Add(1)
+			slog.ErrorContext(ctx, "failed to write crawl to the spool", "domain", crawl.Domain, "error", errors.Join(cause, err))
+			continue
+		}
+		slog.WarnContext(ctx, "failed to write crawl, written to the spool", "domain", crawl.Domain, "spool", b.spool.Path(), "error", cause)
+	}
+}
+
+// writeCrawls writes the crawls, their instances and their peers in a single transaction.
+// Returns the number of crawls inserted, the crawls already written are ignored.
+func (b *Business) writeCrawls(ctx context.Context, crawls []models.Crawl) (int, error) {
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := b.queries.WithTx(tx)
+
+	domains := make([]string, 0, len(crawls))
+	for _, crawl := range crawls {
+		domains = append(domains, crawl.Domain)
+	}
+
+	// add the instances that are not in the db yet,
+	// their software name is set with the last crawl below
+	err = qtx.CreateInstancesFromDomainList(ctx, domains)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := qtx.GetInstancesByDomains(ctx, domains)
+	if err != nil {
+		return 0, err
+	}
+	instances := make(map[string]db.Instance, len(rows))
+	for _, row := range rows {
+		instances[row.Domain] = row
+	}
+
+	crawlIDs := make([]pgtype.UUID, 0, len(crawls))
+	crawlParams := make([]db.CreateCrawlParams, 0, len(crawls))
+	instanceParams := make([]db.UpdateInstanceFromLastCrawlParams, 0, len(crawls))
+	for _, crawl := range crawls {
+		instance, ok := instances[crawl.Domain]
+		if !ok {
+			return 0, fmt.Errorf("instance %q not found after insert", crawl.Domain)
+		}
+		crawlID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+		crawlIDs = append(crawlIDs, crawlID)
+
+		crawlParams = append(crawlParams, createCrawlParams(crawlID, instance, crawl))
+		instanceParams = append(instanceParams, b.updateInstanceParams(crawlID, instance, crawl))
+	}
+
+	// the crawls must be inserted before the instances reference them
+	var batchErr error
+	inserted := 0
+	qtx.CreateCrawl(ctx, crawlParams).QueryRow(func(_ int, _ pgtype.UUID, err error) {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// already written
+		case err != nil:
+			collectBatchError(&batchErr)(0, err)
+		default:
+			inserted++
+		}
+	})
+	if batchErr != nil {
+		return 0, batchErr
+	}
+	qtx.UpdateInstanceFromLastCrawl(ctx, instanceParams).Exec(collectBatchError(&batchErr))
+	if batchErr != nil {
+		return 0, batchErr
+	}
+
+	// the instances have been read before being updated, with the status preceding the crawls
+	err = b.writeIncidents(ctx, qtx, instances, crawls, crawlIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, crawl := range crawls {
+		err = writePeers(ctx, qtx, instances[crawl.Domain].ID, crawl)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return inserted, tx.Commit(ctx)
+}
+
+// writePeers records the peers listed by the crawl of an instance,
+// in chunks to avoid statements with huge array parameters.
+// The peers missing from a complete list are marked as removed.
+func writePeers(ctx context.Context, qtx *db.Queries, instanceID pgtype.UUID, crawl models.Crawl) error {
+	seenAt := pgtype.Timestamptz{Time: crawl.StartedAt, Valid: true}
+
+	for start := 0; start < len(crawl.Peers); start += peersChunkSize {
+		chunk := crawl.Peers[start:min(start+peersChunkSize, len(crawl.Peers))]
+
+		// add the peers if they are not already in the db
+		err := qtx.CreateInstancesFromDomainList(ctx, chunk)
+		if err != nil {
+			return err
+		}
+
+		// update the relations between the server and the peers
+		err = qtx.UpdatePeeringRelationships(ctx, db.UpdatePeeringRelationshipsParams{
+			InstanceID: instanceID,
+			SeenAt:     seenAt,
+			Domains:    chunk,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !hasCompletePeers(crawl) {
+		return nil
+	}
+	return qtx.RemoveUnseenPeeringRelationships(ctx, db.RemoveUnseenPeeringRelationshipsParams{
+		InstanceID: instanceID,
+		SeenAt:     seenAt,
+	})
+}
+
+// hasCompletePeers returns true if the crawl listed every peer of the instance.
+// A failed crawl or a truncated list says nothing about the missing peers.
+func hasCompletePeers(crawl models.Crawl) bool {
+	return crawl.Status == models.CrawlStatusCompleted && !crawl.PeersTruncated
+}
+
+func createCrawlParams(id pgtype.UUID, instance db.Instance, crawl models.Crawl) db.CreateCrawlParams {
+	params := db.CreateCrawlParams{
+		ID:         id,
+		InstanceID: instance.ID,
+
+		Status: db.CrawlStatus(crawl.Status),
+
+		StartedAt:  pgtype.Timestamptz{Time: crawl.StartedAt, Valid: true},
+		FinishedAt: pgtype.Timestamptz{Time: crawl.FinishedAt, Valid: true},
+
+		SoftwareName:    pgtype.Text{String: utils.StringPtrToVal(crawl.SoftwareName), Valid: crawl.SoftwareName != nil},
+		SoftwareVersion: pgtype.Text{String: utils.StringPtrToVal(crawl.SoftwareVersion), Valid: crawl.SoftwareVersion != nil},
+
+		NumberOfPeers:     pgtype.Int4{Int32: int32(utils.IntPtrToVal(crawl.NumberOfPeers)), Valid: crawl.NumberOfPeers != nil},
+		OpenRegistrations: pgtype.Bool{Bool: utils.BoolPtrToVal(crawl.OpenRegistrations), Valid: crawl.OpenRegistrations != nil},
+		TotalUsers:        pgtype.Int4{Int32: int32(utils.IntPtrToVal(crawl.TotalUsers)), Valid: crawl.TotalUsers != nil},
+		ActiveHalfYear:    pgtype.Int4{Int32: int32(utils.IntPtrToVal(crawl.ActiveHalfyear)), Valid: crawl.ActiveHalfyear != nil},
+		ActiveMonth:       pgtype.Int4{Int32: int32(utils.IntPtrToVal(crawl.ActiveMonth)), Valid: crawl.ActiveMonth != nil},
+		LocalPosts:        pgtype.Int4{Int32: int32(utils.IntPtrToVal(crawl.LocalPosts)), Valid: crawl.LocalPosts != nil},
+		LocalComments:     pgtype.Int4{Int32: int32(utils.IntPtrToVal(crawl.LocalComments)), Valid: crawl.LocalComments != nil},
+		Languages:         crawl.Languages,
+		Title:             pgtype.Text{String: utils.StringPtrToVal(crawl.Title), Valid: crawl.Title != nil},
+		Description:       pgtype.Text{String: utils.StringPtrToVal(crawl.Description), Valid: crawl.Description != nil},
+
+		RawNodeinfo: []byte(crawl.RawNodeinfo),
+		Addresses:   crawl.Addresses,
+
+		CrawlRunID: pgtype.UUID{Bytes: crawl.RunID, Valid: crawl.RunID != uuid.Nil},
+
+		MaxPeers:       pgtype.Int4{Int32: int32(crawl.MaxPeers), Valid: crawl.MaxPeers > 0},
+		PeersTruncated: crawl.PeersTruncated,
+	}
+
+	if crawl.Err != nil {
+		params.ErrorMsg = pgtype.Text{String: crawl.Err.Error(), Valid: true}
+		params.ErrorCode = db.NullCrawlErrorCode{CrawlErrorCode: db.CrawlErrorCode(crawl.Err.Code), Valid: true}
+	}
+
+	return params
+}
+
+func (b *Business) updateInstanceParams(crawlID pgtype.UUID, instance db.Instance, crawl models.Crawl) db.UpdateInstanceFromLastCrawlParams {
+	instanceStatus := db.InstanceStatusUp
+	if crawl.Err != nil {
+		instanceStatus = db.InstanceStatusDown
+	}
+
+	// schedule the next crawl of the instance
+	decision := b.schedulePolicy.Next(schedule.State{
+		ConsecutiveFailures: int(instance.ConsecutiveFailures),
+		FailingSince:        instance.FailingSince.Time,
+		NotFoundSince:       instance.NotFoundSince.Time,
+	}, crawl)
+
+	return db.UpdateInstanceFromLastCrawlParams{
+		ID:     instance.ID,
+		Status: instanceStatus,
+
+		// While this is considered immutable, it is not enforced by the db.
+		// It's important to update it here because when creating instances in bulk,
+		// the software name is not known yet and therefore not set.
+		SoftwareName: pgtype.Text{String: utils.StringPtrToVal(crawl.SoftwareName), Valid: crawl.SoftwareName != nil},
+
+		LastCrawlID: crawlID,
+
+		NextCrawlAt:         pgtype.Timestamptz{Time: decision.NextCrawlAt, Valid: !decision.Tombstoned},
+		ConsecutiveFailures: int32(decision.ConsecutiveFailures),
+		FailingSince:        pgtype.Timestamptz{Time: decision.FailingSince, Valid: !decision.FailingSince.IsZero()},
+		NotFoundSince:       pgtype.Timestamptz{Time: decision.NotFoundSince, Valid: !decision.NotFoundSince.IsZero()},
+		TombstonedAt:        pgtype.Timestamptz{Time: crawl.StartedAt, Valid: decision.Tombstoned},
+	}
+}
+
+// collectBatchError keeps the first error of a batch.
+func collectBatchError(err *error) func(int, error) {
+	return func(_ int, e error) {
+		if e != nil && *err == nil {
+			*err = e
+		}
+	}
+}
+
+func containsDomain(crawls []models.Crawl, domain string) bool {
+	for _, crawl := range crawls {
+		if crawl.Domain == domain {
+			return true
+		}
+	}
+	return false
+}
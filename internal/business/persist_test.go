@@ -0,0 +1,27 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasCompletePeers(t *testing.T) {
+	tests := []struct {
+		name  string
+		crawl models.Crawl
+		want  bool
+	}{
+		{"completed", models.Crawl{Status: models.CrawlStatusCompleted, Peers: []string{"mastodon.social"}}, true},
+		{"completed without peers", models.Crawl{Status: models.CrawlStatusCompleted, Peers: []string{}}, true},
+		{"truncated", models.Crawl{Status: models.CrawlStatusCompleted, PeersTruncated: true}, false},
+		{"failed", models.Crawl{Status: models.CrawlStatusFailed}, false},
+		{"unknown", models.Crawl{Status: models.CrawlStatusUnknown}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hasCompletePeers(tt.crawl))
+		})
+	}
+}
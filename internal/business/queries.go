@@ -3,7 +3,9 @@ package business
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/cyclimse/fediverse-blahaj/internal/db"
@@ -45,131 +47,406 @@ func (b *Business) GetInstanceByID(ctx context.Context, id uuid.UUID) (models.Fe
 		return models.FediverseInstance{}, err
 	}
 
-	instance := models.FediverseInstance{
+	// blocked instances are not exposed by the API
+	if b.isBlocked(row.Instance.Domain) {
+		return models.FediverseInstance{}, ErrInstanceNotFound
+	}
+
+	return b.instanceFromRow(ctx, row.Instance, row.Crawl)
+}
+
+// instanceRow is a row of the ListInstancesBy queries, which all have the same columns.
+// The queries are generated from a single template, see db/sql/gen.
+type instanceRow struct {
+	Instance db.Instance
+	Crawl    db.Crawl
+}
+
+// instancesPage are the parameters of the ListInstancesBy queries, which all have the same
+type instancesPage = db.ListInstancesByTotalUsersDescParams
+
+type listInstancesQuery func(ctx context.Context, q *db.Queries, params instancesPage) ([]instanceRow, error)
+
+// listInstancesBy adapts a ListInstancesBy query to listInstancesQuery
+func listInstancesBy[P ~struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               db.NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}, R ~struct {
+	Instance db.Instance
+	Crawl    db.Crawl
+}](query func(*db.Queries, context.Context, P) ([]R, error)) listInstancesQuery {
+	return func(ctx context.Context, q *db.Queries, params instancesPage) ([]instanceRow, error) {
+		rows, err := query(q, ctx, P(params))
+		if err != nil {
+			return nil, err
+		}
+		converted := make([]instanceRow, 0, len(rows))
+		for _, row := range rows {
+			converted = append(converted, instanceRow(row))
+		}
+		return converted, nil
+	}
+}
+
+// instanceSort is a sort key of the instances listing
+type instanceSort struct {
+	asc, desc listInstancesQuery
+	// value returns the sort key of an instance as text, as compared by the queries
+	value func(db.Instance) string
+	// parse checks a value returned by value, it comes from a cursor sent by the client
+	parse func(string) error
+}
+
+// instanceSorts maps the sort keys to the ListInstancesBy queries
+var instanceSorts = map[models.InstanceSortKey]instanceSort{
+	models.InstanceSortTotalUsers: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByTotalUsersAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByTotalUsersDesc),
+		value: func(i db.Instance) string { return intSortValue(i.LastTotalUsers) },
+		parse: parseIntSortValue,
+	},
+	models.InstanceSortActiveUsersMonth: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByActiveMonthAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByActiveMonthDesc),
+		value: func(i db.Instance) string { return intSortValue(i.LastActiveMonth) },
+		parse: parseIntSortValue,
+	},
+	models.InstanceSortLocalPosts: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByLocalPostsAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByLocalPostsDesc),
+		value: func(i db.Instance) string { return intSortValue(i.LastLocalPosts) },
+		parse: parseIntSortValue,
+	},
+	models.InstanceSortNumberOfPeers: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByNumberOfPeersAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByNumberOfPeersDesc),
+		value: func(i db.Instance) string { return intSortValue(i.LastNumberOfPeers) },
+		parse: parseIntSortValue,
+	},
+	models.InstanceSortDomain: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByDomainAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByDomainDesc),
+		value: func(i db.Instance) string { return i.Domain },
+		parse: func(string) error { return nil },
+	},
+	models.InstanceSortLastCrawledAt: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByLastCrawledAtAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByLastCrawledAtDesc),
+		value: func(i db.Instance) string { return timeSortValue(i.LastCrawledAt) },
+		parse: parseTimeSortValue,
+	},
+	models.InstanceSortFirstSeenAt: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByFirstSeenAtAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByFirstSeenAtDesc),
+		value: func(i db.Instance) string { return timeSortValue(i.CreatedAt) },
+		parse: parseTimeSortValue,
+	},
+	models.InstanceSortUptime24h: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByUptime24hAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByUptime24hDesc),
+		value: func(i db.Instance) string { return floatSortValue(i.Uptime24h) },
+		parse: parseFloatSortValue,
+	},
+	models.InstanceSortUptime7d: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByUptime7dAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByUptime7dDesc),
+		value: func(i db.Instance) string { return floatSortValue(i.Uptime7d) },
+		parse: parseFloatSortValue,
+	},
+	models.InstanceSortUptime30d: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByUptime30dAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByUptime30dDesc),
+		value: func(i db.Instance) string { return floatSortValue(i.Uptime30d) },
+		parse: parseFloatSortValue,
+	},
+	models.InstanceSortUptime90d: {
+		asc:   listInstancesBy((*db.Queries).ListInstancesByUptime90dAsc),
+		desc:  listInstancesBy((*db.Queries).ListInstancesByUptime90dDesc),
+		value: func(i db.Instance) string { return floatSortValue(i.Uptime90d) },
+		parse: parseFloatSortValue,
+	},
+}
+
+// the missing values are sorted as -1, see the indexes of the sort keys
+func intSortValue(v pgtype.Int4) string {
+	if !v.Valid {
+		return "-1"
+	}
+	return strconv.FormatInt(int64(v.Int32), 10)
+}
+
+func parseIntSortValue(s string) error {
+	_, err := strconv.ParseInt(s, 10, 32)
+	return err
+}
+
+func floatSortValue(v pgtype.Float8) string {
+	if !v.Valid {
+		return "-1"
+	}
+	return strconv.FormatFloat(v.Float64, 'g', -1, 64)
+}
+
+func parseFloatSortValue(s string) error {
+	_, err := strconv.ParseFloat(s, 64)
+	return err
+}
+
+func timeSortValue(v pgtype.Timestamptz) string {
+	return v.Time.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTimeSortValue(s string) error {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err
+}
+
+// ListInstances returns a page of the instances matching the filter, small servers excluded.
+// The page starts after the cursor if set, otherwise at the given page number.
+// The page number is deprecated: the instances move between pages as they are crawled.
+// next is the cursor of the next page, nil on the last page.
+func (b *Business) ListInstances(ctx context.Context, filter models.InstancesFilter, sort models.InstancesSort, after *models.InstancesCursor, page, pageSize int32) (instances []models.FediverseInstance, next *models.InstancesCursor, err error) {
+	key, ok := instanceSorts[sort.Key]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown sort key %q", sort.Key)
+	}
+	query := key.asc
+	if sort.Descending {
+		query = key.desc
+	}
+
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := instancesPage{
+		// fetch one more row to know if there is a next page
+		Limit:                pageSize + 1,
+		Offset:               (page - 1) * pageSize,
+		SmallServerThreshold: smallServerThreshold,
+		BlockedDomains:       blocked,
+
+		Software:          filter.Software,
+		Status:            db.NullInstanceStatus{InstanceStatus: db.InstanceStatus(filter.Status), Valid: filter.Status != ""},
+		OpenRegistrations: pgtype.Bool{Bool: utils.BoolPtrToVal(filter.OpenRegistrations), Valid: filter.OpenRegistrations != nil},
+		MinUsers:          pgtype.Int4{Int32: utils.IntPtrToVal(filter.MinUsers), Valid: filter.MinUsers != nil},
+		MaxUsers:          pgtype.Int4{Int32: utils.IntPtrToVal(filter.MaxUsers), Valid: filter.MaxUsers != nil},
+		VersionPrefix:     pgtype.Text{String: filter.VersionPrefix, Valid: filter.VersionPrefix != ""},
+		Language:          pgtype.Text{String: filter.Language, Valid: filter.Language != ""},
+	}
+	if after != nil {
+		if after.Sort != sort || key.parse(after.Value) != nil {
+			return nil, nil, ErrCursorMismatch
+		}
+		params.Offset = 0
+		params.AfterValue = pgtype.Text{String: after.Value, Valid: true}
+		params.AfterID = pgtype.UUID{Bytes: after.ID, Valid: true}
+	}
+
+	rows, err := query(ctx, b.queries, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(rows) > int(pageSize) {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1].Instance
+		next = &models.InstancesCursor{Sort: sort, Value: key.value(last), ID: last.ID.Bytes}
+	}
+
+	instances = make([]models.FediverseInstance, 0, len(rows))
+	for _, row := range rows {
+		instance, err := b.instanceFromRow(ctx, row.Instance, row.Crawl)
+		if err != nil {
+			return nil, nil, err
+		}
+		instances = append(instances, instance)
+	}
+
+	return instances, next, nil
+}
+
+// instanceFromRow returns an instance with its last crawl
+func (b *Business) instanceFromRow(ctx context.Context, row db.Instance, lastCrawl db.Crawl) (models.FediverseInstance, error) {
+	crawl, err := b.crawlFromRow(ctx, lastCrawl)
+	if err != nil {
+		return models.FediverseInstance{}, err
+	}
+	crawl.Domain = row.Domain
+
+	return models.FediverseInstance{
 		ID:     row.ID.Bytes,
 		Domain: row.Domain,
 		Status: string(row.Status),
 
 		SoftwareName: utils.ValToPtr(row.SoftwareName.String, row.SoftwareName.Valid),
 
-		LastCrawl: &models.Crawl{
-			ID:     row.LastCrawlID.Bytes,
-			Domain: row.Domain,
-
-			Status: models.CrawlStatus(row.Status_2),
+		Title:       utils.ValToPtr(row.Title.String, row.Title.Valid),
+		Description: utils.ValToPtr(row.Description.String, row.Description.Valid),
 
-			StartedAt:  row.StartedAt.Time,
-			FinishedAt: row.FinishedAt.Time,
+		Uptime: uptimeFromRow(row.Uptime24h, row.Uptime7d, row.Uptime30d, row.Uptime90d),
 
-			Peers:         nil,
-			NumberOfPeers: utils.ValToPtr(row.NumberOfPeers.Int32, row.NumberOfPeers.Valid),
-
-			SoftwareName:    utils.ValToPtr(row.SoftwareName_2.String, row.SoftwareName_2.Valid),
-			SoftwareVersion: utils.ValToPtr(row.SoftwareVersion.String, row.SoftwareVersion.Valid),
-
-			OpenRegistrations: utils.ValToPtr(row.OpenRegistrations.Bool, row.OpenRegistrations.Valid),
-			TotalUsers:        utils.ValToPtr(row.TotalUsers.Int32, row.TotalUsers.Valid),
-			ActiveHalfyear:    utils.ValToPtr(row.ActiveHalfYear.Int32, row.ActiveHalfYear.Valid),
-			ActiveMonth:       utils.ValToPtr(row.ActiveMonth.Int32, row.ActiveMonth.Valid),
-			LocalPosts:        utils.ValToPtr(row.LocalPosts.Int32, row.LocalPosts.Valid),
-			LocalComments:     utils.ValToPtr(row.LocalComments.Int32, row.LocalComments.Valid),
+		LastCrawl: &crawl,
+	}, nil
+}
 
-			RawNodeinfo: json.RawMessage(row.RawNodeinfo),
-			Addresses:   row.Addresses,
-		},
+// crawlFromRow returns a crawl, with the description of its error
+func (b *Business) crawlFromRow(ctx context.Context, row db.Crawl) (models.Crawl, error) {
+	c := models.Crawl{
+		ID:         row.ID.Bytes,
+		RunID:      row.CrawlRunID.Bytes,
+		InstanceID: row.InstanceID.Bytes,
+
+		Status: models.CrawlStatus(row.Status),
+
+		StartedAt:  row.StartedAt.Time,
+		FinishedAt: row.FinishedAt.Time,
+
+		Peers:          nil,
+		NumberOfPeers:  utils.ValToPtr(row.NumberOfPeers.Int32, row.NumberOfPeers.Valid),
+		PeersTruncated: row.PeersTruncated,
+		MaxPeers:       int(row.MaxPeers.Int32),
+
+		SoftwareName:    utils.ValToPtr(row.SoftwareName.String, row.SoftwareName.Valid),
+		SoftwareVersion: utils.ValToPtr(row.SoftwareVersion.String, row.SoftwareVersion.Valid),
+
+		OpenRegistrations: utils.ValToPtr(row.OpenRegistrations.Bool, row.OpenRegistrations.Valid),
+		TotalUsers:        utils.ValToPtr(row.TotalUsers.Int32, row.TotalUsers.Valid),
+		ActiveHalfyear:    utils.ValToPtr(row.ActiveHalfYear.Int32, row.ActiveHalfYear.Valid),
+		ActiveMonth:       utils.ValToPtr(row.ActiveMonth.Int32, row.ActiveMonth.Valid),
+		LocalPosts:        utils.ValToPtr(row.LocalPosts.Int32, row.LocalPosts.Valid),
+		LocalComments:     utils.ValToPtr(row.LocalComments.Int32, row.LocalComments.Valid),
+		Languages:         row.Languages,
+		Title:             utils.ValToPtr(row.Title.String, row.Title.Valid),
+		Description:       utils.ValToPtr(row.Description.String, row.Description.Valid),
+
+		RawNodeinfo: json.RawMessage(row.RawNodeinfo),
+		Addresses:   row.Addresses,
 	}
 
 	if row.ErrorMsg.Valid {
 		d, err := b.errorCodeDescriptions.Description(ctx, b.queries, models.CrawlErrCode(row.ErrorCode.CrawlErrorCode))
 		if err != nil {
-			return models.FediverseInstance{}, err
+			return models.Crawl{}, err
 		}
 
-		instance.LastCrawl.Err = &models.CrawlError{
+		c.Err = &models.CrawlError{
 			Msg:         row.ErrorMsg.String,
 			Code:        models.CrawlErrCode(row.ErrorCode.CrawlErrorCode),
 			Description: d,
 		}
 	}
 
-	return instance, nil
+	return c, nil
 }
 
-func (b *Business) ListInstances(ctx context.Context, page, pageSize int32) ([]models.FediverseInstance, int64, error) {
-	rows, err := b.queries.ListInstancesPaginated(ctx, db.ListInstancesPaginatedParams{
-		Limit:      pageSize,
-		Offset:     (page - 1) * pageSize,
-		TotalUsers: pgtype.Int4{Int32: smallServerThreshold, Valid: true},
-	})
+// CountInstances returns the number of instances matching the filter, small servers excluded.
+// The count is cached for a few minutes.
+func (b *Business) CountInstances(ctx context.Context, filter models.InstancesFilter) (int64, error) {
+	key, err := json.Marshal(filter)
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
-
-	if len(rows) == 0 {
-		return nil, 0, nil
+	if total, ok := b.instanceTotals.Get(string(key)); ok {
+		return total, nil
 	}
-	total := rows[0].TotalCount
-
-	instances := make([]models.FediverseInstance, 0, len(rows))
-	for _, row := range rows {
-		instance := models.FediverseInstance{
-			ID:     row.ID.Bytes,
-			Domain: row.Domain,
-			Status: string(row.Status),
 
-			SoftwareName: utils.ValToPtr(row.SoftwareName.String, row.SoftwareName.Valid),
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return 0, err
+	}
 
-			LastCrawl: &models.Crawl{
-				ID:     row.LastCrawlID.Bytes,
-				Domain: row.Domain,
+	total, err := b.queries.CountInstances(ctx, db.CountInstancesParams{
+		SmallServerThreshold: smallServerThreshold,
+		BlockedDomains:       blocked,
+
+		Software:          filter.Software,
+		Status:            db.NullInstanceStatus{InstanceStatus: db.InstanceStatus(filter.Status), Valid: filter.Status != ""},
+		OpenRegistrations: pgtype.Bool{Bool: utils.BoolPtrToVal(filter.OpenRegistrations), Valid: filter.OpenRegistrations != nil},
+		MinUsers:          pgtype.Int4{Int32: utils.IntPtrToVal(filter.MinUsers), Valid: filter.MinUsers != nil},
+		MaxUsers:          pgtype.Int4{Int32: utils.IntPtrToVal(filter.MaxUsers), Valid: filter.MaxUsers != nil},
+		VersionPrefix:     pgtype.Text{String: filter.VersionPrefix, Valid: filter.VersionPrefix != ""},
+		Language:          pgtype.Text{String: filter.Language, Valid: filter.Language != ""},
+	})
+	if err != nil {
+		return 0, err
+	}
 
-				Status: models.CrawlStatus(row.Status_2),
+	b.instanceTotals.Set(string(key), total)
+	return total, nil
+}
 
-				StartedAt:  row.StartedAt.Time,
-				FinishedAt: row.FinishedAt.Time,
+// ListCrawlsForInstance returns a page of the crawls of an instance, most recent first.
+// The page starts after the cursor if set, otherwise at the given page number, which is deprecated.
+// next is the cursor of the next page, nil on the last page.
+func (b *Business) ListCrawlsForInstance(ctx context.Context, instanceID uuid.UUID, after *models.CrawlsCursor, page, pageSize int32) (crawls []models.Crawl, next *models.CrawlsCursor, err error) {
+	params := db.ListCrawlsPaginatedParams{
+		InstanceID: pgtype.UUID{Bytes: instanceID, Valid: true},
+		// fetch one more row to know if there is a next page
+		Limit:  pageSize + 1,
+		Offset: (page - 1) * pageSize,
+	}
+	if after != nil {
+		params.Offset = 0
+		params.AfterStartedAt = pgtype.Timestamptz{Time: after.StartedAt, Valid: true}
+		params.AfterID = pgtype.UUID{Bytes: after.ID, Valid: true}
+	}
 
-				Peers:         nil,
-				NumberOfPeers: utils.ValToPtr(row.NumberOfPeers.Int32, row.NumberOfPeers.Valid),
+	rows, err := b.queries.ListCrawlsPaginated(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
 
-				SoftwareName:    utils.ValToPtr(row.SoftwareName.String, row.SoftwareName.Valid),
-				SoftwareVersion: utils.ValToPtr(row.SoftwareVersion.String, row.SoftwareVersion.Valid),
+	if len(rows) > int(pageSize) {
+		rows = rows[:pageSize]
+		last := rows[len(rows)-1]
+		next = &models.CrawlsCursor{StartedAt: last.StartedAt.Time, ID: last.ID.Bytes}
+	}
 
-				OpenRegistrations: utils.ValToPtr(row.OpenRegistrations.Bool, row.OpenRegistrations.Valid),
-				TotalUsers:        utils.ValToPtr(row.TotalUsers.Int32, row.TotalUsers.Valid),
-				ActiveHalfyear:    utils.ValToPtr(row.ActiveHalfYear.Int32, row.ActiveHalfYear.Valid),
-				ActiveMonth:       utils.ValToPtr(row.ActiveMonth.Int32, row.ActiveMonth.Valid),
-				LocalPosts:        utils.ValToPtr(row.LocalPosts.Int32, row.LocalPosts.Valid),
-				LocalComments:     utils.ValToPtr(row.LocalComments.Int32, row.LocalComments.Valid),
+	crawls = make([]models.Crawl, 0, len(rows))
 
-				RawNodeinfo: json.RawMessage(row.RawNodeinfo),
-				Addresses:   row.Addresses,
-			},
+	for _, row := range rows {
+		c, err := b.crawlFromRow(ctx, row)
+		if err != nil {
+			return nil, nil, err
 		}
+		crawls = append(crawls, c)
+	}
 
-		if row.ErrorMsg.Valid {
-			d, err := b.errorCodeDescriptions.Description(ctx, b.queries, models.CrawlErrCode(row.ErrorCode.CrawlErrorCode))
-			if err != nil {
-				return nil, 0, err
-			}
-
-			instance.LastCrawl.Err = &models.CrawlError{
-				Msg:         row.ErrorMsg.String,
-				Code:        models.CrawlErrCode(row.ErrorCode.CrawlErrorCode),
-				Description: d,
-			}
-		}
+	return crawls, next, nil
+}
 
-		instances = append(instances, instance)
+// ListPeerChanges returns the peers gained and lost by an instance since a date, most recent first.
+// All the changes are returned if change is empty.
+func (b *Business) ListPeerChanges(ctx context.Context, instanceID uuid.UUID, since time.Time, change models.PeerChangeKind, page, pageSize int32) ([]models.PeerChange, int64, error) {
+	if _, err := b.GetInstanceByID(ctx, instanceID); err != nil {
+		return nil, 0, err
 	}
 
-	return instances, total, nil
-}
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 
-func (b *Business) ListCrawlsForInstance(ctx context.Context, instanceID uuid.UUID, page, pageSize int32) ([]models.Crawl, int64, error) {
-	rows, err := b.queries.ListCrawlsPaginated(ctx, db.ListCrawlsPaginatedParams{
-		InstanceID: pgtype.UUID{Bytes: instanceID, Valid: true},
-		Limit:      pageSize,
-		Offset:     (page - 1) * pageSize,
+	rows, err := b.queries.ListPeeringChangesPaginated(ctx, db.ListPeeringChangesPaginatedParams{
+		InstanceID:     pgtype.UUID{Bytes: instanceID, Valid: true},
+		Since:          pgtype.Timestamptz{Time: since, Valid: true},
+		BlockedDomains: blocked,
+		Change:         pgtype.Text{String: string(change), Valid: change != ""},
+		Limit:          pageSize,
+		Offset:         (page - 1) * pageSize,
 	})
 	if err != nil {
 		return nil, 0, err
@@ -180,49 +457,84 @@ func (b *Business) ListCrawlsForInstance(ctx context.Context, instanceID uuid.UU
 	}
 	total := rows[0].TotalCount
 
-	crawls := make([]models.Crawl, 0, len(rows))
-
+	changes := make([]models.PeerChange, 0, len(rows))
 	for _, row := range rows {
-		c := models.Crawl{
-			ID: row.ID.Bytes,
-
-			Status: models.CrawlStatus(row.Status),
+		changes = append(changes, models.PeerChange{
+			PeerID:    row.PeerID.Bytes,
+			Domain:    row.Domain,
+			Change:    models.PeerChangeKind(row.Change),
+			ChangedAt: row.ChangedAt.Time,
+		})
+	}
 
-			StartedAt:  row.StartedAt.Time,
-			FinishedAt: row.FinishedAt.Time,
+	return changes, total, nil
+}
 
-			Peers:         nil,
-			NumberOfPeers: utils.ValToPtr(row.NumberOfPeers.Int32, row.NumberOfPeers.Valid),
+// ListPeers returns the peers of an instance in the given direction, by domain.
+// The page starts after the domain after, if set.
+// next is the domain to start the next page after, empty on the last page.
+func (b *Business) ListPeers(ctx context.Context, instanceID uuid.UUID, filter models.PeersFilter, after string, pageSize int32) (peers []models.Peer, next string, err error) {
+	if _, err := b.GetInstanceByID(ctx, instanceID); err != nil {
+		return nil, "", err
+	}
 
-			SoftwareName:    utils.ValToPtr(row.SoftwareName.String, row.SoftwareName.Valid),
-			SoftwareVersion: utils.ValToPtr(row.SoftwareVersion.String, row.SoftwareVersion.Valid),
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return nil, "", err
+	}
 
-			OpenRegistrations: utils.ValToPtr(row.OpenRegistrations.Bool, row.OpenRegistrations.Valid),
-			TotalUsers:        utils.ValToPtr(row.TotalUsers.Int32, row.TotalUsers.Valid),
-			ActiveHalfyear:    utils.ValToPtr(row.ActiveHalfYear.Int32, row.ActiveHalfYear.Valid),
-			ActiveMonth:       utils.ValToPtr(row.ActiveMonth.Int32, row.ActiveMonth.Valid),
-			LocalPosts:        utils.ValToPtr(row.LocalPosts.Int32, row.LocalPosts.Valid),
-			LocalComments:     utils.ValToPtr(row.LocalComments.Int32, row.LocalComments.Valid),
+	// fetch one more row to know if there is a next page
+	params := db.ListOutgoingPeersPaginatedParams{
+		InstanceID:     pgtype.UUID{Bytes: instanceID, Valid: true},
+		BlockedDomains: blocked,
+		Status:         db.NullInstanceStatus{InstanceStatus: db.InstanceStatus(filter.Status), Valid: filter.Status != ""},
+		Software:       pgtype.Text{String: filter.Software, Valid: filter.Software != ""},
+		After:          pgtype.Text{String: after, Valid: after != ""},
+		Limit:          pageSize + 1,
+	}
 
-			RawNodeinfo: json.RawMessage(row.RawNodeinfo),
-			Addresses:   row.Addresses,
+	var rows []db.ListOutgoingPeersPaginatedRow
+	switch filter.Direction {
+	case models.PeerDirectionIncoming:
+		incoming, err := b.queries.ListIncomingPeersPaginated(ctx, db.ListIncomingPeersPaginatedParams(params))
+		if err != nil {
+			return nil, "", err
 		}
-
-		if row.ErrorMsg.Valid {
-			d, err := b.errorCodeDescriptions.Description(ctx, b.queries, models.CrawlErrCode(row.ErrorCode.CrawlErrorCode))
-			if err != nil {
-				return nil, 0, err
-			}
-
-			c.Err = &models.CrawlError{
-				Msg:         row.ErrorMsg.String,
-				Code:        models.CrawlErrCode(row.ErrorCode.CrawlErrorCode),
-				Description: d,
-			}
+		rows = make([]db.ListOutgoingPeersPaginatedRow, len(incoming))
+		for i, row := range incoming {
+			rows[i] = db.ListOutgoingPeersPaginatedRow(row)
 		}
+	default:
+		rows, err = b.queries.ListOutgoingPeersPaginated(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+	}
 
-		crawls = append(crawls, c)
+	if len(rows) > int(pageSize) {
+		rows = rows[:pageSize]
+		next = rows[len(rows)-1].Domain
 	}
 
-	return crawls, total, nil
+	peers = make([]models.Peer, 0, len(rows))
+	for _, row := range rows {
+		peers = append(peers, models.Peer{
+			ID:              row.ID.Bytes,
+			Domain:          row.Domain,
+			Status:          models.FediverseInstanceStatus(row.Status),
+			SoftwareName:    utils.ValToPtr(row.SoftwareName.String, row.SoftwareName.Valid),
+			SoftwareVersion: utils.ValToPtr(row.SoftwareVersion.String, row.SoftwareVersion.Valid),
+			TotalUsers:      utils.ValToPtr(row.TotalUsers.Int32, row.TotalUsers.Valid && row.TotalUsers.Int32 > smallServerThreshold),
+			LastCrawledAt:   utils.ValToPtr(row.LastCrawledAt.Time, row.LastCrawledAt.Valid),
+			FirstSeenAt:     utils.ValToPtr(row.FirstSeenAt.Time, row.FirstSeenAt.Valid),
+			LastSeenAt:      row.LastSeenAt.Time,
+		})
+	}
+
+	return peers, next, nil
+}
+
+// CountCrawlsForInstance returns the number of crawls of an instance.
+func (b *Business) CountCrawlsForInstance(ctx context.Context, instanceID uuid.UUID) (int64, error) {
+	return b.queries.CountCrawlsForInstance(ctx, pgtype.UUID{Bytes: instanceID, Valid: true})
 }
@@ -0,0 +1,72 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceSorts(t *testing.T) {
+	for _, key := range []models.InstanceSortKey{
+		models.InstanceSortTotalUsers,
+		models.InstanceSortActiveUsersMonth,
+		models.InstanceSortLocalPosts,
+		models.InstanceSortNumberOfPeers,
+		models.InstanceSortDomain,
+		models.InstanceSortLastCrawledAt,
+		models.InstanceSortFirstSeenAt,
+		models.InstanceSortUptime24h,
+		models.InstanceSortUptime7d,
+		models.InstanceSortUptime30d,
+		models.InstanceSortUptime90d,
+	} {
+		require.Contains(t, instanceSorts, key)
+		assert.NotNil(t, instanceSorts[key].asc, key)
+		assert.NotNil(t, instanceSorts[key].desc, key)
+	}
+	assert.Contains(t, instanceSorts, models.DefaultInstancesSort.Key)
+}
+
+func TestInstanceSorts_Value(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 123456000, time.UTC)
+	instance := db.Instance{
+		ID:             pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		Domain:         "mastodon.social",
+		CreatedAt:      pgtype.Timestamptz{Time: createdAt, Valid: true},
+		LastTotalUsers: pgtype.Int4{Int32: 42, Valid: true},
+		Uptime7d:       pgtype.Float8{Float64: 0.995, Valid: true},
+	}
+
+	tests := []struct {
+		key  models.InstanceSortKey
+		want string
+	}{
+		{models.InstanceSortTotalUsers, "42"},
+		// sorted as -1, as in the index
+		{models.InstanceSortActiveUsersMonth, "-1"},
+		{models.InstanceSortUptime7d, "0.995"},
+		{models.InstanceSortUptime30d, "-1"},
+		{models.InstanceSortFirstSeenAt, "2026-10-19T12:00:00.123456Z"},
+		{models.InstanceSortDomain, "mastodon.social"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			sort := instanceSorts[tt.key]
+			got := sort.value(instance)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, sort.parse(got))
+		})
+	}
+}
+
+func TestInstanceSorts_Parse(t *testing.T) {
+	assert.Error(t, instanceSorts[models.InstanceSortTotalUsers].parse("1e3"))
+	assert.Error(t, instanceSorts[models.InstanceSortUptime24h].parse("up"))
+	assert.Error(t, instanceSorts[models.InstanceSortLastCrawledAt].parse("yesterday"))
+}
@@ -0,0 +1,74 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	retryMaxAttempts  = 5
+	retryInitialDelay = 100 * time.Millisecond
+	retryMaxDelay     = 5 * time.Second
+)
+
+// withRetry calls fn until it succeeds, returns a permanent error or the attempts are exhausted.
+// fn must be safe to call again, eg: by running in its own transaction.
+func withRetry(ctx context.Context, fn func() error) error {
+	delay := retryInitialDelay
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || !isTransient(err) || attempt == retryMaxAttempts {
+			return err
+		}
+
+		slog.WarnContext(ctx, "transient database error, retrying", "attempt", attempt, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		delay = min(2*delay, retryMaxDelay)
+	}
+}
+
+// isTransient returns true if the operation that failed with err may succeed if retried.
+func isTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", // serialization_failure
+			"40P01", // deadlock_detected
+			"57P01", // admin_shutdown
+			"57P02", // crash_shutdown
+			"57P03": // cannot_connect_now
+			return true
+		}
+		// connection_exception class
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+
+	// eg: the database cannot be reached
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// the query was not sent, or timed out
+	var retryable interface{ SafeToRetry() bool }
+	if errors.As(err, &retryable) && retryable.SafeToRetry() {
+		return true
+	}
+	return pgconn.Timeout(err)
+}
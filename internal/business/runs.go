@@ -0,0 +1,155 @@
+package business
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// StartCrawlRun records the start of a crawl run.
+// The ID of the returned run must be attached to the crawls of the run.
+func (b *Business) StartCrawlRun(ctx context.Context, run models.CrawlRun) (models.CrawlRun, error) {
+	config := run.Config
+	if config == nil {
+		config = json.RawMessage("{}")
+	}
+
+	row, err := b.queries.CreateCrawlRun(ctx, db.CreateCrawlRunParams{
+		StartedAt:      pgtype.Timestamptz{Time: run.StartedAt, Valid: true},
+		CrawlerVersion: run.CrawlerVersion,
+		Seeds:          run.Seeds,
+		Config:         []byte(config),
+	})
+	if err != nil {
+		return models.CrawlRun{}, err
+	}
+
+	return crawlRunFromRow(row)
+}
+
+// FinishCrawlRun records the end of a crawl run.
+// Most statistics are derived from the crawls of the run:
+// only the ones known by the orchestrator alone are taken from stats.
+func (b *Business) FinishCrawlRun(ctx context.Context, id uuid.UUID, finishedAt time.Time, stats models.CrawlRunStats) (models.CrawlRun, error) {
+	blockedGroups := stats.BlockedGroups
+	if blockedGroups == nil {
+		blockedGroups = make(map[string]int)
+	}
+	groups, err := json.Marshal(blockedGroups)
+	if err != nil {
+		return models.CrawlRun{}, err
+	}
+
+	row, err := b.queries.FinishCrawlRun(ctx, db.FinishCrawlRunParams{
+		ID:            pgtype.UUID{Bytes: id, Valid: true},
+		FinishedAt:    pgtype.Timestamptz{Time: finishedAt, Valid: true},
+		Rejected:      int32(stats.Rejected),
+		Blocked:       int32(stats.Blocked),
+		BlockedGroups: groups,
+		Lost:          int32(stats.Lost),
+	})
+	if err != nil {
+		return models.CrawlRun{}, err
+	}
+
+	return crawlRunFromRow(row)
+}
+
+func (b *Business) GetCrawlRunByID(ctx context.Context, id uuid.UUID) (models.CrawlRun, error) {
+	row, err := b.queries.GetCrawlRunByID(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.CrawlRun{}, ErrCrawlRunNotFound
+		}
+		return models.CrawlRun{}, err
+	}
+
+	return crawlRunFromRow(row)
+}
+
+func (b *Business) ListCrawlRuns(ctx context.Context, page, pageSize int32) ([]models.CrawlRun, int64, error) {
+	rows, err := b.queries.ListCrawlRunsPaginated(ctx, db.ListCrawlRunsPaginatedParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	total := rows[0].TotalCount
+
+	runs := make([]models.CrawlRun, 0, len(rows))
+	for _, row := range rows {
+		run, err := crawlRunFromRow(db.CrawlRun{
+			ID:             row.ID,
+			StartedAt:      row.StartedAt,
+			FinishedAt:     row.FinishedAt,
+			CrawlerVersion: row.CrawlerVersion,
+			Seeds:          row.Seeds,
+			Config:         row.Config,
+			Attempted:      row.Attempted,
+			Completed:      row.Completed,
+			Failed:         row.Failed,
+			Discovered:     row.Discovered,
+			Rejected:       row.Rejected,
+			Blocked:        row.Blocked,
+			Lost:           row.Lost,
+			ErrorCounts:    row.ErrorCounts,
+			SoftwareCounts: row.SoftwareCounts,
+			BlockedGroups:  row.BlockedGroups,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, total, nil
+}
+
+func crawlRunFromRow(row db.CrawlRun) (models.CrawlRun, error) {
+	run := models.CrawlRun{
+		ID:        row.ID.Bytes,
+		StartedAt: row.StartedAt.Time,
+
+		CrawlerVersion: row.CrawlerVersion,
+		Seeds:          row.Seeds,
+		Config:         json.RawMessage(row.Config),
+
+		Stats: models.CrawlRunStats{
+			Attempted:  int(row.Attempted),
+			Completed:  int(row.Completed),
+			Failed:     int(row.Failed),
+			Discovered: int(row.Discovered),
+			Rejected:   int(row.Rejected),
+			Blocked:    int(row.Blocked),
+			Lost:       int(row.Lost),
+		},
+	}
+
+	if row.FinishedAt.Valid {
+		run.FinishedAt = &row.FinishedAt.Time
+	}
+
+	if err := json.Unmarshal(row.ErrorCounts, &run.Stats.Errors); err != nil {
+		return models.CrawlRun{}, err
+	}
+	if err := json.Unmarshal(row.SoftwareCounts, &run.Stats.Software); err != nil {
+		return models.CrawlRun{}, err
+	}
+	if err := json.Unmarshal(row.BlockedGroups, &run.Stats.BlockedGroups); err != nil {
+		return models.CrawlRun{}, err
+	}
+
+	return run, nil
+}
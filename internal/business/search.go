@@ -0,0 +1,91 @@
+package business
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+const (
+	// the matches are delimited by control characters, which are removed from the descriptions by the crawler,
+	// so that the snippets can be escaped before being highlighted
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+
+	// see ts_headline in the Postgres documentation
+	headlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop +
+		`, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=" … "`
+)
+
+// SearchInstances returns a page of the instances matching the query, most relevant first, small servers excluded.
+// The query is in the web search syntax, eg: "art -nsfw", and tolerates typos in the domains and the titles.
+func (b *Business) SearchInstances(ctx context.Context, query string, page, pageSize int32) ([]models.SearchResult, int64, error) {
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := b.queries.SearchInstances(ctx, db.SearchInstancesParams{
+		Query:                query,
+		HeadlineOptions:      headlineOptions,
+		SmallServerThreshold: smallServerThreshold,
+		BlockedDomains:       blocked,
+		Limit:                pageSize,
+		Offset:               (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	results := make([]models.SearchResult, 0, len(rows))
+	for _, row := range rows {
+		total = row.TotalCount
+
+		instance, err := b.instanceFromRow(ctx, row.Instance, row.Crawl)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		results = append(results, models.SearchResult{
+			Instance: instance,
+			Rank:     row.Rank,
+			Snippet:  highlightSnippet(row.Snippet),
+		})
+	}
+
+	return results, total, nil
+}
+
+// highlightSnippet escapes a headline returned by ts_headline
+// and replaces the delimiters of the matches by <mark> elements.
+func highlightSnippet(headline string) string {
+	var b strings.Builder
+	highlighted := false
+	for headline != "" {
+		i := strings.IndexAny(headline, highlightStart+highlightStop)
+		if i < 0 {
+			b.WriteString(html.EscapeString(headline))
+			break
+		}
+		b.WriteString(html.EscapeString(headline[:i]))
+
+		// the delimiters are always balanced, but a stray one must not produce invalid HTML
+		switch start := headline[i:i+1] == highlightStart; {
+		case start && !highlighted:
+			b.WriteString("<mark>")
+			highlighted = true
+		case !start && highlighted:
+			b.WriteString("</mark>")
+			highlighted = false
+		}
+		headline = headline[i+1:]
+	}
+	if highlighted {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		want     string
+	}{
+		{"no match", "A server for artists", "A server for artists"},
+		{"matches", "A \x02server\x03 for \x02artists\x03", "A <mark>server</mark> for <mark>artists</mark>"},
+		{"escaped", "<b>Cats</b> & \x02dogs\x03", "&lt;b&gt;Cats&lt;/b&gt; &amp; <mark>dogs</mark>"},
+		{"unclosed", "\x02art", "<mark>art</mark>"},
+		{"stray delimiters", "a\x03b\x02\x02c\x03\x03", "ab<mark>c</mark>"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, highlightSnippet(tt.headline))
+		})
+	}
+}
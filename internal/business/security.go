@@ -1,7 +0,0 @@
-package business
-
-var BlockedDomains = []string{
-	"localhost",
-	"ngrok.io",
-	"activitypub-troll.cf",
-}
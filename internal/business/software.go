@@ -0,0 +1,143 @@
+package business
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/semver"
+)
+
+// ListSoftwareStats returns a page of the stats of the softwares, the most used first, small servers and blocked instances excluded.
+// Like the global stats, they are cached until the next crawl run finishes.
+func (b *Business) ListSoftwareStats(ctx context.Context, page, pageSize int32) ([]models.SoftwareStats, int64, error) {
+	stats, err := b.allSoftwareStats(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start := min(int((page-1)*pageSize), len(stats))
+	end := min(start+int(pageSize), len(stats))
+	return stats[start:end], int64(len(stats)), nil
+}
+
+// GetSoftwareStats returns the stats of a software, the name is case-insensitive.
+func (b *Business) GetSoftwareStats(ctx context.Context, name string) (models.SoftwareStats, error) {
+	stats, err := b.allSoftwareStats(ctx)
+	if err != nil {
+		return models.SoftwareStats{}, err
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, s := range stats {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return models.SoftwareStats{}, ErrSoftwareNotFound
+}
+
+func (b *Business) allSoftwareStats(ctx context.Context) ([]models.SoftwareStats, error) {
+	key, err := b.lastFinishedCrawlRunID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stats, ok := b.softwareStats.Get(key); ok {
+		return stats, nil
+	}
+
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := b.queries.ListSoftwareVersionStats(ctx, db.ListSoftwareVersionStatsParams{
+		SmallServerThreshold: smallServerThreshold,
+		BlockedDomains:       blocked,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := softwareStatsFromRows(rows)
+	b.softwareStats.Set(key, stats)
+	return stats, nil
+}
+
+// softwareStatsFromRows sums the stats per software, and per parsed version.
+// The names of the softwares are not always lowercase, although nodeinfo requires it.
+func softwareStatsFromRows(rows []db.ListSoftwareVersionStatsRow) []models.SoftwareStats {
+	type versionKey struct {
+		version semver.Version
+		valid   bool
+	}
+	type software struct {
+		stats    models.SoftwareStats
+		versions map[versionKey]int64
+	}
+
+	softwares := make(map[string]*software)
+	for _, row := range rows {
+		name := strings.ToLower(row.SoftwareName)
+		s, ok := softwares[name]
+		if !ok {
+			s = &software{
+				stats:    models.SoftwareStats{Name: name},
+				versions: make(map[versionKey]int64),
+			}
+			softwares[name] = s
+		}
+
+		s.stats.Instances += row.Instances
+		s.stats.Up += row.Up
+		s.stats.Down += row.Down
+		s.stats.Unhealthy += row.Unhealthy
+		s.stats.TotalUsers += row.TotalUsers
+		s.stats.ActiveUsersMonth += row.ActiveMonth
+
+		var key versionKey
+		if v, err := semver.Parse(row.SoftwareVersion.String); err == nil {
+			key = versionKey{version: v, valid: true}
+		}
+		s.versions[key] += row.Instances
+	}
+
+	stats := make([]models.SoftwareStats, 0, len(softwares))
+	for _, s := range softwares {
+		keys := make([]versionKey, 0, len(s.versions))
+		for key := range s.versions {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(a, b versionKey) int {
+			if a.valid != b.valid {
+				if a.valid {
+					return -1
+				}
+				return 1
+			}
+			return semver.Compare(b.version, a.version)
+		})
+
+		s.stats.Versions = make([]models.SoftwareVersionCount, 0, len(keys))
+		for _, key := range keys {
+			count := models.SoftwareVersionCount{Instances: s.versions[key]}
+			if key.valid {
+				count.Version = key.version.Core()
+				count.Fork = key.version.Fork
+			}
+			s.stats.Versions = append(s.stats.Versions, count)
+		}
+		stats = append(stats, s.stats)
+	}
+
+	slices.SortFunc(stats, func(a, b models.SoftwareStats) int {
+		if c := cmp.Compare(b.Instances, a.Instances); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return stats
+}
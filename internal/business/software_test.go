@@ -0,0 +1,61 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftwareStatsFromRows(t *testing.T) {
+	version := func(v string) pgtype.Text {
+		return pgtype.Text{String: v, Valid: true}
+	}
+	rows := []db.ListSoftwareVersionStatsRow{
+		{SoftwareName: "mastodon", SoftwareVersion: version("4.1.0"), Instances: 3, Up: 2, Down: 1, TotalUsers: 300, ActiveMonth: 30},
+		{SoftwareName: "mastodon", SoftwareVersion: version("4.2.0+glitch"), Instances: 1, Up: 1, TotalUsers: 10, ActiveMonth: 5},
+		{SoftwareName: "Mastodon", SoftwareVersion: version("v4.2.0"), Instances: 2, Up: 1, Unhealthy: 1, TotalUsers: 50},
+		{SoftwareName: "mastodon", SoftwareVersion: version("4.2.0"), Instances: 4, Up: 4, TotalUsers: 400},
+		{SoftwareName: "mastodon", SoftwareVersion: version("unknown"), Instances: 1, Up: 1, TotalUsers: 3},
+		{SoftwareName: "mastodon", Instances: 1, Down: 1},
+		{SoftwareName: "misskey", SoftwareVersion: version("2023.11.0"), Instances: 2, Up: 2, TotalUsers: 70},
+		{SoftwareName: "lemmy", SoftwareVersion: version("0.19.0"), Instances: 2, Up: 1, Down: 1, TotalUsers: 20},
+	}
+
+	stats := softwareStatsFromRows(rows)
+	assert.Equal(t, []models.SoftwareStats{
+		{
+			Name:             "mastodon",
+			Instances:        12,
+			Up:               9,
+			Down:             2,
+			Unhealthy:        1,
+			TotalUsers:       763,
+			ActiveUsersMonth: 35,
+			Versions: []models.SoftwareVersionCount{
+				{Version: "4.2.0", Fork: "glitch", Instances: 1},
+				{Version: "4.2.0", Instances: 6},
+				{Version: "4.1.0", Instances: 3},
+				{Instances: 2},
+			},
+		},
+		{
+			Name:       "lemmy",
+			Instances:  2,
+			Up:         1,
+			Down:       1,
+			TotalUsers: 20,
+			Versions:   []models.SoftwareVersionCount{{Version: "0.19.0", Instances: 2}},
+		},
+		{
+			Name:       "misskey",
+			Instances:  2,
+			Up:         2,
+			TotalUsers: 70,
+			Versions:   []models.SoftwareVersionCount{{Version: "2023.11.0", Instances: 2}},
+		},
+	}, stats)
+	assert.InDelta(t, 0.75, stats[0].UpRatio(), 1e-9)
+}
@@ -0,0 +1,71 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetGlobalStats returns the totals over the last crawl of each instance, small servers and blocked instances excluded.
+// They are cached until the next crawl run finishes, a change of the blocklist applies then.
+func (b *Business) GetGlobalStats(ctx context.Context) (models.GlobalStats, error) {
+	key, err := b.lastFinishedCrawlRunID(ctx)
+	if err != nil {
+		return models.GlobalStats{}, err
+	}
+	if stats, ok := b.globalStats.Get(key); ok {
+		return stats, nil
+	}
+
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return models.GlobalStats{}, err
+	}
+
+	row, err := b.queries.GetGlobalStats(ctx, db.GetGlobalStatsParams{
+		SmallServerThreshold: smallServerThreshold,
+		BlockedDomains:       blocked,
+	})
+	if err != nil {
+		return models.GlobalStats{}, err
+	}
+
+	stats := globalStatsFromRow(row, time.Now())
+	b.globalStats.Set(key, stats)
+	return stats, nil
+}
+
+// lastFinishedCrawlRunID returns the ID of the last finished crawl run, used as a cache key.
+// Returns the zero ID if no run has finished yet.
+func (b *Business) lastFinishedCrawlRunID(ctx context.Context) (uuid.UUID, error) {
+	runID, err := b.queries.GetLastFinishedCrawlRunID(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, err
+	}
+	return runID.Bytes, nil
+}
+
+func globalStatsFromRow(row db.GetGlobalStatsRow, computedAt time.Time) models.GlobalStats {
+	stats := models.GlobalStats{
+		Instances: row.Instances,
+		Up:        row.Up,
+		Down:      row.Down,
+		Unhealthy: row.Unhealthy,
+
+		TotalUsers:       row.TotalUsers,
+		ActiveUsersMonth: row.ActiveMonth,
+		LocalPosts:       row.LocalPosts,
+
+		ComputedAt: computedAt,
+	}
+	if row.ReportedRegistrations > 0 {
+		share := float64(row.OpenRegistrations) / float64(row.ReportedRegistrations)
+		stats.OpenRegistrationsShare = &share
+	}
+	return stats
+}
@@ -0,0 +1,35 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalStatsFromRow(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	stats := globalStatsFromRow(db.GetGlobalStatsRow{
+		Instances:             10,
+		Up:                    7,
+		Down:                  2,
+		Unhealthy:             1,
+		TotalUsers:            1200,
+		ActiveMonth:           300,
+		LocalPosts:            45000,
+		OpenRegistrations:     3,
+		ReportedRegistrations: 4,
+	}, now)
+	assert.Equal(t, int64(10), stats.Instances)
+	assert.Equal(t, int64(300), stats.ActiveUsersMonth)
+	assert.Equal(t, now, stats.ComputedAt)
+	require.NotNil(t, stats.OpenRegistrationsShare)
+	assert.InDelta(t, 0.75, *stats.OpenRegistrationsShare, 1e-9)
+
+	// no instance reported its registrations
+	stats = globalStatsFromRow(db.GetGlobalStatsRow{Instances: 2, Up: 2}, now)
+	assert.Nil(t, stats.OpenRegistrationsShare)
+}
@@ -0,0 +1,205 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RollupDays computes the daily aggregates of the instances, then the global ones,
+// from the crawls started between the days from and to, in UTC, both included.
+// Rolling up days again is safe: their aggregates are replaced.
+func (b *Business) RollupDays(ctx context.Context, from, to time.Time) error {
+	fromDay := pgtype.Date{Time: utcDay(from), Valid: true}
+	toDay := pgtype.Date{Time: utcDay(to), Valid: true}
+
+	blocked, err := b.blockedDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	qtx := b.queries.WithTx(tx)
+
+	err = qtx.RollupInstanceDays(ctx, db.RollupInstanceDaysParams{FromDay: fromDay, ToDay: toDay})
+	if err != nil {
+		return fmt.Errorf("failed to roll up the instances: %w", err)
+	}
+	err = qtx.RollupGlobalDays(ctx, db.RollupGlobalDaysParams{
+		FromDay:              fromDay,
+		ToDay:                toDay,
+		BlockedDomains:       blocked,
+		SmallServerThreshold: smallServerThreshold,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll up the global aggregates: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetInstanceTimeseries returns the time series of a metric of an instance, from its daily aggregates.
+func (b *Business) GetInstanceTimeseries(ctx context.Context, instanceID uuid.UUID, q models.TimeseriesQuery) ([]models.TimeseriesPoint, error) {
+	if _, err := b.GetInstanceByID(ctx, instanceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := b.queries.ListInstanceDailyRollups(ctx, db.ListInstanceDailyRollupsParams{
+		InstanceID: pgtype.UUID{Bytes: instanceID, Valid: true},
+		FromDay:    pgtype.Date{Time: utcDay(q.From), Valid: true},
+		ToDay:      pgtype.Date{Time: utcDay(q.To), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]dailySample, 0, len(rows))
+	for _, row := range rows {
+		s := dailySample{
+			day:             row.Day.Time,
+			crawls:          int64(row.Crawls),
+			completedCrawls: int64(row.CompletedCrawls),
+		}
+		switch q.Metric {
+		case models.TimeseriesUsers:
+			s.last, s.min, s.max = int4ToFloat(row.LastUsers), int4ToFloat(row.MinUsers), int4ToFloat(row.MaxUsers)
+		case models.TimeseriesActiveUsersMonth:
+			s.last, s.min, s.max = int4ToFloat(row.LastActiveMonth), int4ToFloat(row.MinActiveMonth), int4ToFloat(row.MaxActiveMonth)
+		case models.TimeseriesLocalPosts:
+			s.last, s.min, s.max = int4ToFloat(row.LastLocalPosts), int4ToFloat(row.MinLocalPosts), int4ToFloat(row.MaxLocalPosts)
+		}
+		samples = append(samples, s)
+	}
+
+	return timeseriesPoints(samples, q.Metric, q.Interval), nil
+}
+
+// GetGlobalTimeseries returns the time series of a metric over all the instances, small servers excluded.
+func (b *Business) GetGlobalTimeseries(ctx context.Context, q models.TimeseriesQuery) ([]models.TimeseriesPoint, error) {
+	rows, err := b.queries.ListGlobalDailyRollups(ctx, db.ListGlobalDailyRollupsParams{
+		FromDay: pgtype.Date{Time: utcDay(q.From), Valid: true},
+		ToDay:   pgtype.Date{Time: utcDay(q.To), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]dailySample, 0, len(rows))
+	for _, row := range rows {
+		s := dailySample{
+			day:             row.Day.Time,
+			crawls:          int64(row.Crawls),
+			completedCrawls: int64(row.CompletedCrawls),
+		}
+		// a single value per day
+		var v float64
+		switch q.Metric {
+		case models.TimeseriesUsers:
+			v = float64(row.TotalUsers)
+		case models.TimeseriesActiveUsersMonth:
+			v = float64(row.ActiveMonth)
+		case models.TimeseriesLocalPosts:
+			v = float64(row.LocalPosts)
+		}
+		s.last, s.min, s.max = &v, &v, &v
+		samples = append(samples, s)
+	}
+
+	return timeseriesPoints(samples, q.Metric, q.Interval), nil
+}
+
+// dailySample is the daily aggregate of a metric
+type dailySample struct {
+	day            time.Time
+	last, min, max *float64
+
+	crawls          int64
+	completedCrawls int64
+}
+
+// timeseriesPoints groups the daily samples, sorted by day, per interval.
+func timeseriesPoints(samples []dailySample, metric models.TimeseriesMetric, interval models.TimeseriesInterval) []models.TimeseriesPoint {
+	var points []models.TimeseriesPoint
+	var crawls, completedCrawls int64
+
+	for _, s := range samples {
+		start := intervalStart(s.day, interval)
+		if len(points) == 0 || !points[len(points)-1].Start.Equal(start) {
+			points = append(points, models.TimeseriesPoint{Start: start})
+			crawls, completedCrawls = 0, 0
+		}
+		p := &points[len(points)-1]
+
+		if metric == models.TimeseriesUptime {
+			if s.crawls == 0 {
+				continue
+			}
+			crawls += s.crawls
+			completedCrawls += s.completedCrawls
+			uptime := float64(completedCrawls) / float64(crawls)
+			p.Value = &uptime
+
+			daily := float64(s.completedCrawls) / float64(s.crawls)
+			p.Min, p.Max = minFloat(p.Min, &daily), maxFloat(p.Max, &daily)
+			continue
+		}
+
+		if s.last != nil {
+			p.Value = s.last
+		}
+		p.Min, p.Max = minFloat(p.Min, s.min), maxFloat(p.Max, s.max)
+	}
+
+	return points
+}
+
+// intervalStart returns the first day of the interval containing the day.
+func intervalStart(day time.Time, interval models.TimeseriesInterval) time.Time {
+	day = utcDay(day)
+	switch interval {
+	case models.TimeseriesWeek:
+		// weeks start on Monday, like in Postgres
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case models.TimeseriesMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// utcDay returns the start of the day of t, in UTC.
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func int4ToFloat(v pgtype.Int4) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	f := float64(v.Int32)
+	return &f
+}
+
+func minFloat(a, b *float64) *float64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+func maxFloat(a, b *float64) *float64 {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
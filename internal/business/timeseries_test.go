@@ -0,0 +1,70 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntervalStart(t *testing.T) {
+	// a Thursday
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, day, intervalStart(day, models.TimeseriesDay))
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), intervalStart(day, models.TimeseriesWeek))
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), intervalStart(day, models.TimeseriesMonth))
+
+	// a Sunday ends the week
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), intervalStart(sunday, models.TimeseriesWeek))
+	// a Monday starts it
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, intervalStart(monday, models.TimeseriesWeek))
+}
+
+func TestTimeseriesPoints(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	samples := []dailySample{
+		// week of the 12th
+		{day: day(12), last: f(10), min: f(8), max: f(10), crawls: 4, completedCrawls: 4},
+		{day: day(14), last: f(12), min: f(12), max: f(15), crawls: 4, completedCrawls: 2},
+		// down all day, the users are not reported
+		{day: day(15), crawls: 2},
+		// week of the 19th
+		{day: day(19), last: f(20), min: f(20), max: f(20), crawls: 1, completedCrawls: 1},
+	}
+
+	t.Run("values per day", func(t *testing.T) {
+		points := timeseriesPoints(samples, models.TimeseriesUsers, models.TimeseriesDay)
+		assert.Equal(t, []models.TimeseriesPoint{
+			{Start: day(12), Value: f(10), Min: f(8), Max: f(10)},
+			{Start: day(14), Value: f(12), Min: f(12), Max: f(15)},
+			{Start: day(15)},
+			{Start: day(19), Value: f(20), Min: f(20), Max: f(20)},
+		}, points)
+	})
+
+	t.Run("values per week", func(t *testing.T) {
+		points := timeseriesPoints(samples, models.TimeseriesUsers, models.TimeseriesWeek)
+		assert.Equal(t, []models.TimeseriesPoint{
+			{Start: day(12), Value: f(12), Min: f(8), Max: f(15)},
+			{Start: day(19), Value: f(20), Min: f(20), Max: f(20)},
+		}, points)
+	})
+
+	t.Run("uptime per week", func(t *testing.T) {
+		points := timeseriesPoints(samples, models.TimeseriesUptime, models.TimeseriesWeek)
+		assert.Equal(t, []models.TimeseriesPoint{
+			{Start: day(12), Value: f(0.6), Min: f(0), Max: f(1)},
+			{Start: day(19), Value: f(1), Min: f(1), Max: f(1)},
+		}, points)
+	})
+
+	t.Run("no samples", func(t *testing.T) {
+		assert.Empty(t, timeseriesPoints(nil, models.TimeseriesUsers, models.TimeseriesMonth))
+	})
+}
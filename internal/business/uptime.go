@@ -0,0 +1,162 @@
+package business
+
+import (
+	"context"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/db"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// uptimeBatchSize is the number of instances whose uptime is computed at once
+	uptimeBatchSize = 500
+	// uptimeSampleValidity is how long a crawl tells the status of an instance, if it is not crawled again.
+	// Healthy instances are crawled every few hours, see internal/schedule.
+	uptimeSampleValidity = 24 * time.Hour
+	// uptimeFailedSampleValidity is the same for failed crawls,
+	// which are longer apart as the failing instances are crawled with a backoff.
+	uptimeFailedSampleValidity = 7 * 24 * time.Hour
+	// minUptimeCoverage is the share of a window during which the status must be known for the uptime to be set
+	minUptimeCoverage = 0.5
+)
+
+// uptimeSample is the status of an instance from the start of a crawl
+type uptimeSample struct {
+	at     time.Time
+	status models.CrawlStatus
+}
+
+// RefreshUptime computes the uptime of every instance over the windows ending at now, from their crawls.
+func (b *Business) RefreshUptime(ctx context.Context, now time.Time) error {
+	// the crawls started before the longest window tell the status at its start
+	since := pgtype.Timestamptz{Time: now.Add(-90*24*time.Hour - uptimeFailedSampleValidity), Valid: true}
+	updatedAt := pgtype.Timestamptz{Time: now, Valid: true}
+
+	after := pgtype.UUID{Valid: true}
+	for {
+		ids, err := b.queries.ListInstanceIDsPaginated(ctx, db.ListInstanceIDsPaginatedParams{
+			AfterID: after,
+			Limit:   uptimeBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		rows, err := b.queries.ListCrawlStatusesSince(ctx, db.ListCrawlStatusesSinceParams{
+			InstanceIds: ids,
+			Since:       since,
+		})
+		if err != nil {
+			return err
+		}
+		samples := make(map[uuid.UUID][]uptimeSample, len(ids))
+		for _, row := range rows {
+			samples[row.InstanceID.Bytes] = append(samples[row.InstanceID.Bytes], uptimeSample{
+				at:     row.StartedAt.Time,
+				status: models.CrawlStatus(row.Status),
+			})
+		}
+
+		params := make([]db.UpdateInstanceUptimeParams, 0, len(ids))
+		for _, id := range ids {
+			uptime := instanceUptime(samples[id.Bytes], now)
+			params = append(params, db.UpdateInstanceUptimeParams{
+				ID:        id,
+				Uptime24h: float8FromPtr(uptime.Last24h),
+				Uptime7d:  float8FromPtr(uptime.Last7d),
+				Uptime30d: float8FromPtr(uptime.Last30d),
+				Uptime90d: float8FromPtr(uptime.Last90d),
+				UpdatedAt: updatedAt,
+			})
+		}
+
+		var batchErr error
+		b.queries.UpdateInstanceUptime(ctx, params).Exec(collectBatchError(&batchErr))
+		if batchErr != nil {
+			return batchErr
+		}
+
+		if len(ids) < uptimeBatchSize {
+			return nil
+		}
+		after = ids[len(ids)-1]
+	}
+}
+
+// instanceUptime computes the uptime of an instance over the windows ending at now,
+// from its crawls sorted by start time.
+func instanceUptime(samples []uptimeSample, now time.Time) models.Uptime {
+	const day = 24 * time.Hour
+	return models.Uptime{
+		Last24h: uptimeOver(samples, now.Add(-day), now),
+		Last7d:  uptimeOver(samples, now.Add(-7*day), now),
+		Last30d: uptimeOver(samples, now.Add(-30*day), now),
+		Last90d: uptimeOver(samples, now.Add(-90*day), now),
+	}
+}
+
+// uptimeOver returns the share of the time the instance was up between from and to,
+// weighted by the time each crawl tells the status for: until the next crawl, or until it is too old.
+// The time during which the status is unknown is excluded,
+// nil is returned if the status is known for less than minUptimeCoverage of the window.
+func uptimeOver(samples []uptimeSample, from, to time.Time) *float64 {
+	var up, down time.Duration
+	for i, s := range samples {
+		validity := uptimeSampleValidity
+		if s.status == models.CrawlStatusFailed {
+			validity = uptimeFailedSampleValidity
+		}
+
+		start, end := s.at, s.at.Add(validity)
+		if i+1 < len(samples) && samples[i+1].at.Before(end) {
+			end = samples[i+1].at
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		switch s.status {
+		case models.CrawlStatusCompleted:
+			up += end.Sub(start)
+		case models.CrawlStatusFailed:
+			down += end.Sub(start)
+		}
+	}
+
+	known := up + down
+	if known == 0 || float64(known) < minUptimeCoverage*float64(to.Sub(from)) {
+		return nil
+	}
+	uptime := float64(up) / float64(known)
+	return &uptime
+}
+
+// uptimeFromRow returns the uptime stored in the instance table
+func uptimeFromRow(last24h, last7d, last30d, last90d pgtype.Float8) models.Uptime {
+	return models.Uptime{
+		Last24h: utils.ValToPtr(last24h.Float64, last24h.Valid),
+		Last7d:  utils.ValToPtr(last7d.Float64, last7d.Valid),
+		Last30d: utils.ValToPtr(last30d.Float64, last30d.Valid),
+		Last90d: utils.ValToPtr(last90d.Float64, last90d.Valid),
+	}
+}
+
+func float8FromPtr(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
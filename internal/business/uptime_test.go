@@ -0,0 +1,118 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUptimeOver(t *testing.T) {
+	to := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+	f := func(v float64) *float64 { return &v }
+	at := func(hoursAgo int) time.Time { return to.Add(-time.Duration(hoursAgo) * time.Hour) }
+
+	tests := []struct {
+		name    string
+		samples []uptimeSample
+		want    *float64
+	}{
+		{"no crawls", nil, nil},
+		{
+			name: "always up",
+			samples: []uptimeSample{
+				{at(30), models.CrawlStatusCompleted},
+				{at(18), models.CrawlStatusCompleted},
+				{at(6), models.CrawlStatusCompleted},
+			},
+			want: f(1.0),
+		},
+		{
+			name: "weighted by the time until the next crawl",
+			samples: []uptimeSample{
+				{at(24), models.CrawlStatusCompleted},
+				{at(6), models.CrawlStatusFailed},
+			},
+			want: f(0.75),
+		},
+		{
+			name: "the crawl before the window tells the status at its start",
+			samples: []uptimeSample{
+				{at(36), models.CrawlStatusFailed},
+				{at(12), models.CrawlStatusCompleted},
+			},
+			want: f(0.5),
+		},
+		{
+			name: "unknown crawls are excluded",
+			samples: []uptimeSample{
+				{at(24), models.CrawlStatusCompleted},
+				{at(12), models.CrawlStatusUnknown},
+				{at(6), models.CrawlStatusFailed},
+			},
+			want: f(2.0 / 3),
+		},
+		{
+			name: "a completed crawl does not tell the status for more than a day",
+			samples: []uptimeSample{
+				{at(20), models.CrawlStatusFailed},
+				{at(16), models.CrawlStatusCompleted},
+			},
+			// up from 16 hours ago until now, as it was not crawled again
+			want: f(0.8),
+		},
+		{
+			name: "known for less than half of the window",
+			samples: []uptimeSample{
+				{at(60), models.CrawlStatusCompleted},
+				{at(8), models.CrawlStatusCompleted},
+			},
+			// the first crawl is too old, only the last 8 hours are known
+			want: nil,
+		},
+		{
+			name: "a failed crawl holds until the next one, as failing instances are crawled less often",
+			samples: []uptimeSample{
+				{at(72), models.CrawlStatusFailed},
+			},
+			want: f(0.0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := uptimeOver(tt.samples, from, to)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.InDelta(t, *tt.want, *got, 1e-9)
+		})
+	}
+}
+
+func TestInstanceUptime(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	// discovered 20 days ago, down for the first 4 days, up since
+	samples := []uptimeSample{
+		{now.Add(-20 * day), models.CrawlStatusFailed},
+	}
+	for d := 16 * day; d > 0; d -= 6 * time.Hour {
+		samples = append(samples, uptimeSample{now.Add(-d), models.CrawlStatusCompleted})
+	}
+
+	uptime := instanceUptime(samples, now)
+	require.NotNil(t, uptime.Last24h)
+	assert.InDelta(t, 1, *uptime.Last24h, 1e-9)
+	require.NotNil(t, uptime.Last7d)
+	assert.InDelta(t, 1, *uptime.Last7d, 1e-9)
+	require.NotNil(t, uptime.Last30d)
+	assert.InDelta(t, 0.8, *uptime.Last30d, 1e-9)
+	// known for 20 days out of 90
+	assert.Nil(t, uptime.Last90d)
+}
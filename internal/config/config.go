@@ -1,5 +1,11 @@
 package config
 
+import (
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/telemetry"
+)
+
 type Environment string
 
 const (
@@ -30,8 +36,14 @@ type Config struct {
 	// DB
 	PgConn string `help:"Postgres connection string." env:"PG_CONN"`
 
+	// Blocklist
+	BlocklistRefreshInterval time.Duration `help:"Interval between two reloads of the blocklist from the database." default:"1m" env:"BLOCKLIST_REFRESH_INTERVAL"`
+
 	// API
 	FrontendURL string `help:"URL of the frontend." env:"FRONTEND_URL"`
+
+	// Tracing
+	Tracing telemetry.Config `embed:""`
 }
 
 // SetDevelopmentDefaults sets the defaults for the development environment.
@@ -19,18 +19,37 @@ import (
 	nodeinfo "github.com/cyclimse/fediverse-blahaj/pkg/nodeinfo/unversioned"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/temoto/robotstxt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func New(userAgent string) *Crawler {
+var tracer = otel.Tracer("github.com/cyclimse/fediverse-blahaj/internal/crawler")
+
+// Config contains the configuration of a crawler.
+type Config struct {
+	UserAgent string
+	// MaxPeers is the maximum number of peers kept per crawl, unlimited if zero.
+	MaxPeers int
+}
+
+func New(config Config) *Crawler {
+	client := retryablehttp.NewClient()
+	client.HTTPClient.Transport = otelhttp.NewTransport(newInstrumentedTransport(client.HTTPClient.Transport))
+
 	return &Crawler{
-		client:    retryablehttp.NewClient(),
-		userAgent: userAgent,
+		client:    client,
+		userAgent: config.UserAgent,
+		maxPeers:  config.MaxPeers,
 	}
 }
 
 type Crawler struct {
 	client    *retryablehttp.Client
 	userAgent string
+	maxPeers  int
 }
 
 type CrawlResult struct {
@@ -45,7 +64,12 @@ type CrawlResult struct {
 	ResolvedIPs []net.IP
 	RawNodeinfo json.RawMessage
 	Nodeinfo    nodeinfo.Nodeinfo
-	Peers       []string
+	Peers       Peers
+	// MaxPeers is the limit applied to the peers, zero if unlimited
+	MaxPeers int
+
+	// SpanContext is the span of the crawl, set by the caller
+	SpanContext trace.SpanContext
 }
 
 func CrawlFromResult(r CrawlResult) models.Crawl {
@@ -67,8 +91,12 @@ func CrawlFromResult(r CrawlResult) models.Crawl {
 		FinishedAt: r.End,
 		Status:     models.CrawlStatusCompleted,
 
-		Peers:         r.Peers,
-		NumberOfPeers: new(int32),
+		Peers:          r.Peers.Domains,
+		NumberOfPeers:  new(int32),
+		PeersTruncated: r.Peers.Truncated(),
+		MaxPeers:       r.MaxPeers,
+
+		SpanContext: r.SpanContext,
 
 		SoftwareName:    new(string),
 		SoftwareVersion: new(string),
@@ -84,12 +112,16 @@ func CrawlFromResult(r CrawlResult) models.Crawl {
 		}
 	}
 
-	if r.Peers != nil {
-		*c.NumberOfPeers = int32(len(r.Peers))
+	if r.Peers.Domains != nil {
+		*c.NumberOfPeers = int32(r.Peers.Total)
 	}
 
-	if r.RawNodeinfo != nil {
+	// the body of an invalid nodeinfo is not always JSON, e.g. an HTML error page,
+	// it is dropped as the crawl could not be encoded otherwise
+	if r.RawNodeinfo != nil && json.Valid(r.RawNodeinfo) {
 		c.RawNodeinfo = r.RawNodeinfo
+		c.Languages = nodeinfoLanguages(r.RawNodeinfo)
+		c.Title, c.Description = nodeinfoDescription(r.RawNodeinfo)
 	}
 
 	n := r.Nodeinfo
@@ -123,18 +155,28 @@ func (c *Crawler) Crawl(ctx context.Context, domain string) *CrawlResult {
 
 	r := &CrawlResult{}
 
+	ctx, span := tracer.Start(ctx, "crawler.Crawl", trace.WithAttributes(attribute.String("domain", domain)))
+
 	// we capture the end time here
 	defer func() {
 		r.End = time.Now()
 		slog.InfoContext(ctx, "crawled", "domain", domain, "result", r)
+
+		if r.Err != nil {
+			span.SetAttributes(attribute.String("error_code", string(r.ErrCode)))
+		}
+		endSpan(span, r.Err)
 	}()
 
 	r.Start = time.Now()
 	r.Domain = domain
+	r.MaxPeers = c.maxPeers
 
 	// lookup the domain via DNS
 	// avoids retrying on such hosts
-	ips, err := net.LookupIP(domain)
+	lookupCtx, lookupSpan := tracer.Start(ctx, "crawler.LookupIP")
+	ips, err := net.DefaultResolver.LookupIP(lookupCtx, "ip", domain)
+	endSpan(lookupSpan, err)
 	r.ResolvedIPs = ips
 	if err != nil {
 		if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
@@ -155,7 +197,9 @@ func (c *Crawler) Crawl(ctx context.Context, domain string) *CrawlResult {
 	var url string
 	for _, prefix := range []string{"https://", "http://"} {
 		url = prefix + domain
-		canProceed, err := c.acknowledgeRobotsTxt(ctx, url)
+		robotsCtx, robotsSpan := tracer.Start(ctx, "crawler.AcknowledgeRobotsTxt", trace.WithAttributes(attribute.String("url", url)))
+		canProceed, err := c.acknowledgeRobotsTxt(robotsCtx, url)
+		endSpan(robotsSpan, err)
 		if err != nil {
 			if errors.Is(err, &tls.CertificateVerificationError{}) {
 				// will use http instead
@@ -183,7 +227,9 @@ func (c *Crawler) Crawl(ctx context.Context, domain string) *CrawlResult {
 	// retry for the rest of the requests
 	c.client.RetryMax = 3
 
-	nodeInfo, raw, code, err := c.getNodeInfo(ctx, url)
+	nodeInfoCtx, nodeInfoSpan := tracer.Start(ctx, "crawler.GetNodeInfo")
+	nodeInfo, raw, code, err := c.getNodeInfo(nodeInfoCtx, url)
+	endSpan(nodeInfoSpan, err)
 	r.RawNodeinfo = raw
 	r.Nodeinfo = nodeInfo
 	if err != nil {
@@ -195,7 +241,10 @@ func (c *Crawler) Crawl(ctx context.Context, domain string) *CrawlResult {
 		return r
 	}
 
-	peers, code, err := c.GetPeers(ctx, url, nodeInfo)
+	peersCtx, peersSpan := tracer.Start(ctx, "crawler.GetPeers")
+	peers, code, err := c.GetPeers(peersCtx, url, nodeInfo)
+	peersSpan.SetAttributes(attribute.Int("peers", peers.Total), attribute.Bool("peers.truncated", peers.Truncated()))
+	endSpan(peersSpan, err)
 	r.Peers = peers
 	if err != nil {
 		if ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
@@ -312,3 +361,12 @@ func (c *Crawler) getNodeInfo(ctx context.Context, url string) (nodeinfo.Nodeinf
 
 	return nodeInfo, b, models.CrawlErrCodeUnknown, nil
 }
+
+// endSpan ends a span, marking it as failed if err is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -2,6 +2,7 @@ package crawler
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
@@ -94,3 +95,14 @@ func TestCrawler_AcknowledgeRobotsTxt(t *testing.T) {
 		})
 	}
 }
+
+func TestCrawlFromResult_InvalidRawNodeinfo(t *testing.T) {
+	crawl := CrawlFromResult(CrawlResult{
+		Domain:      "mastodon.social",
+		RawNodeinfo: []byte("<html>Not Found</html>"),
+	})
+	assert.Nil(t, crawl.RawNodeinfo)
+
+	_, err := json.Marshal(crawl)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// keep in sync with the crawl table
+	maxTitleLength       = 255
+	maxDescriptionLength = 1024
+)
+
+// nodeinfoDescription returns the title and the description of an instance from its raw nodeinfo.
+// Like the languages, they are not standard: Mastodon, Pleroma and Misskey report them in the metadata,
+// as "nodeName" and "nodeDescription". The description can contain HTML, only its text is kept.
+// Returns nil if the instance does not report them.
+func nodeinfoDescription(raw json.RawMessage) (title, description *string) {
+	var n struct {
+		Metadata struct {
+			NodeName        json.RawMessage `json:"nodeName"`
+			NodeDescription json.RawMessage `json:"nodeDescription"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return nil, nil
+	}
+	return sanitizedText(n.Metadata.NodeName, maxTitleLength), sanitizedText(n.Metadata.NodeDescription, maxDescriptionLength)
+}
+
+// sanitizedText returns the text of a JSON string, without markup, control characters
+// and repeated spaces, truncated to maxLength runes.
+// Returns nil if the value is not a string or if there is no text.
+func sanitizedText(raw json.RawMessage, maxLength int) *string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil
+	}
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		switch tt {
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			// eg: <p>a</p><p>b</p> is "a b"
+			b.WriteByte(' ')
+		}
+	}
+
+	text := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, b.String())
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil
+	}
+
+	if runes := []rune(text); len(runes) > maxLength {
+		text = strings.TrimSpace(string(runes[:maxLength]))
+	}
+	return &text
+}
@@ -0,0 +1,53 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeinfoDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		raw             string
+		wantTitle       *string
+		wantDescription *string
+	}{
+		{
+			"plain text",
+			`{"metadata":{"nodeName":"Mastodon","nodeDescription":"The original server"}}`,
+			utils.ValToPtr("Mastodon", true),
+			utils.ValToPtr("The original server", true),
+		},
+		{
+			"html",
+			`{"metadata":{"nodeName":"<b>Blåhaj</b> zone","nodeDescription":"<p>Hello &amp; welcome</p><p>to the<br/>server</p><script>alert(1)</script>"}}`,
+			utils.ValToPtr("Blåhaj zone", true),
+			utils.ValToPtr("Hello & welcome to the server alert(1)", true),
+		},
+		{
+			"spaces and control characters",
+			`{"metadata":{"nodeName":"  a\tb\u0000c\n"}}`,
+			utils.ValToPtr("a b c", true),
+			nil,
+		},
+		{"empty", `{"metadata":{"nodeName":"","nodeDescription":"<p> </p>"}}`, nil, nil},
+		{"not a string", `{"metadata":{"nodeName":["a"],"nodeDescription":1}}`, nil, nil},
+		{"not reported", `{"metadata":{}}`, nil, nil},
+		{"invalid json", `{`, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, description := nodeinfoDescription([]byte(tt.raw))
+			assert.Equal(t, tt.wantTitle, title)
+			assert.Equal(t, tt.wantDescription, description)
+		})
+	}
+}
+
+func TestNodeinfoDescription_Truncated(t *testing.T) {
+	title, _ := nodeinfoDescription([]byte(`{"metadata":{"nodeName":"` + strings.Repeat("é", maxTitleLength+10) + `"}}`))
+	assert.Equal(t, utils.ValToPtr(strings.Repeat("é", maxTitleLength), true), title)
+}
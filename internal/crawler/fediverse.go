@@ -4,47 +4,113 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/cyclimse/fediverse-blahaj/internal/hostname"
 	"github.com/cyclimse/fediverse-blahaj/internal/models"
 	nodeinfo "github.com/cyclimse/fediverse-blahaj/pkg/nodeinfo/unversioned"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
-func (c *Crawler) GetPeers(ctx context.Context, url string, n nodeinfo.Nodeinfo) ([]string, models.CrawlErrCode, error) {
+// Peers are the peers listed by an instance.
+type Peers struct {
+	// Domains are the normalized and deduplicated peers, at most MaxPeers.
+	Domains []string
+	// Total is the number of valid peers listed by the instance.
+	// Past MaxPeers, duplicates are not detected anymore.
+	Total int
+	// Rejections counts the peers that failed normalization.
+	Rejections hostname.Rejections
+}
+
+// Truncated returns true if some peers were not kept because of MaxPeers.
+func (p Peers) Truncated() bool {
+	return p.Total > len(p.Domains)
+}
+
+func (c *Crawler) GetPeers(ctx context.Context, url string, n nodeinfo.Nodeinfo) (Peers, models.CrawlErrCode, error) {
 	if n == nil {
-		return nil, models.CrawlErrCodeInternalError, fmt.Errorf("nodeinfo is nil")
+		return Peers{}, models.CrawlErrCodeInternalError, fmt.Errorf("nodeinfo is nil")
 	}
 	switch n.SoftwareName() {
 	case "mastodon":
 		return c.GetPeersMastodon(ctx, url, n)
 	}
-	return nil, models.CrawlErrCodeSoftwareNotSupportedByCrawler, fmt.Errorf("software not supported by crawler: %s", n.SoftwareName())
+	return Peers{}, models.CrawlErrCodeSoftwareNotSupportedByCrawler, fmt.Errorf("software not supported by crawler: %s", n.SoftwareName())
 }
 
-func (c *Crawler) GetPeersMastodon(ctx context.Context, url string, n nodeinfo.Nodeinfo) ([]string, models.CrawlErrCode, error) {
+func (c *Crawler) GetPeersMastodon(ctx context.Context, url string, n nodeinfo.Nodeinfo) (Peers, models.CrawlErrCode, error) {
 	r, err := retryablehttp.NewRequest("GET", url+"/api/v1/instance/peers", nil)
 	if err != nil {
-		return nil, models.CrawlErrCodeInternalError, err
+		return Peers{}, models.CrawlErrCodeInternalError, err
 	}
 
 	r.Header.Set("User-Agent", c.userAgent)
 
 	resp, err := c.client.Do(r.WithContext(ctx))
 	if err != nil {
-		return nil, models.CrawlErrCodeUnreachable, err
+		return Peers{}, models.CrawlErrCodeUnreachable, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return nil, models.CrawlErrCodeUnreachable, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return Peers{}, models.CrawlErrCodeUnreachable, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	var peers []string
-	err = json.NewDecoder(resp.Body).Decode(&peers)
+	peers, err := decodePeers(resp.Body, c.maxPeers)
 	if err != nil {
-		return nil, models.CrawlErrCodeInvalidJSON, err
+		return peers, models.CrawlErrCodeInvalidJSON, err
 	}
 
 	return peers, models.CrawlErrCodeUnknown, nil
 }
+
+// decodePeers decodes a JSON array of domains one element at a time,
+// so that the memory used does not depend on the size of the array.
+// Only the first maxPeers distinct peers are kept, the others are only counted.
+// If maxPeers is zero, every peer is kept.
+func decodePeers(r io.Reader, maxPeers int) (Peers, error) {
+	peers := Peers{Rejections: make(hostname.Rejections)}
+
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return peers, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return peers, fmt.Errorf("expected an array of peers, got %v", tok)
+	}
+
+	seen := make(map[string]struct{})
+	for dec.More() {
+		var peer string
+		if err := dec.Decode(&peer); err != nil {
+			return peers, err
+		}
+
+		domain, err := hostname.Normalize(peer)
+		if err != nil {
+			peers.Rejections.Add(err)
+			continue
+		}
+
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+		peers.Total++
+
+		if maxPeers > 0 && len(peers.Domains) >= maxPeers {
+			continue
+		}
+		seen[domain] = struct{}{}
+		peers.Domains = append(peers.Domains, domain)
+	}
+
+	// closing bracket
+	if _, err := dec.Token(); err != nil {
+		return peers, err
+	}
+
+	return peers, nil
+}
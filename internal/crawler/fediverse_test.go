@@ -0,0 +1,86 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/hostname"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePeers(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		maxPeers      int
+		wantDomains   []string
+		wantTotal     int
+		wantTruncated bool
+		wantRejected  int
+		wantErr       bool
+	}{
+		{
+			name:        "empty",
+			body:        `[]`,
+			wantDomains: nil,
+		},
+		{
+			name:        "normalized and deduplicated",
+			body:        `["Mastodon.Social", "mastodon.social.", "lemmy.ml"]`,
+			wantDomains: []string{"mastodon.social", "lemmy.ml"},
+			wantTotal:   2,
+		},
+		{
+			name:         "invalid peers are rejected",
+			body:         `["mastodon.social", "127.0.0.1", "localhost", ""]`,
+			wantDomains:  []string{"mastodon.social"},
+			wantTotal:    1,
+			wantRejected: 3,
+		},
+		{
+			name:          "capped",
+			body:          `["a.social", "b.social", "c.social", "d.social"]`,
+			maxPeers:      2,
+			wantDomains:   []string{"a.social", "b.social"},
+			wantTotal:     4,
+			wantTruncated: true,
+		},
+		{
+			name:    "not an array",
+			body:    `{"peers": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			body:    `["mastodon.social", 42]`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated body",
+			body:    `["mastodon.social", "lemm`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePeers(strings.NewReader(tt.body), tt.maxPeers)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDomains, got.Domains)
+			assert.Equal(t, tt.wantTotal, got.Total)
+			assert.Equal(t, tt.wantTruncated, got.Truncated())
+			assert.Equal(t, tt.wantRejected, got.Rejections.Total())
+		})
+	}
+}
+
+func TestDecodePeers_CountsRejectionsByReason(t *testing.T) {
+	got, err := decodePeers(strings.NewReader(`["127.0.0.1", "::1", "localhost"]`), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Rejections[hostname.ErrIPAddress])
+	assert.Equal(t, 1, got.Rejections[hostname.ErrNotQualified])
+}
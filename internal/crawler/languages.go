@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxLanguages bounds the number of languages kept per crawl
+	maxLanguages = 32
+)
+
+// languageTag matches the tags reported by the instances, eg: en, pt-BR or zh_Hant
+var languageTag = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]+)*$`)
+
+// nodeinfoLanguages returns the languages of an instance from its raw nodeinfo.
+// Nodeinfo has no standard field for them: some softwares report them in the metadata,
+// as "languages" or "langs" (eg: Misskey). Only the primary language subtag is kept.
+// Returns nil if the instance does not report its languages.
+// Keep in sync with the backfill in db/migrations.
+func nodeinfoLanguages(raw json.RawMessage) []string {
+	var n struct {
+		Metadata struct {
+			Languages json.RawMessage `json:"languages"`
+			Langs     json.RawMessage `json:"langs"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return nil
+	}
+
+	// "languages" takes precedence if it is an array
+	var tags []string
+	if err := json.Unmarshal(n.Metadata.Languages, &tags); err != nil || tags == nil {
+		if err := json.Unmarshal(n.Metadata.Langs, &tags); err != nil || tags == nil {
+			return nil
+		}
+	}
+
+	languages := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if !languageTag.MatchString(tag) {
+			continue
+		}
+		primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+		primary = strings.ToLower(primary)
+		if _, ok := seen[primary]; ok {
+			continue
+		}
+		seen[primary] = struct{}{}
+		languages = append(languages, primary)
+		if len(languages) == maxLanguages {
+			break
+		}
+	}
+	return languages
+}
@@ -0,0 +1,33 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeinfoLanguages(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{"languages", `{"metadata":{"languages":["en","fr"]}}`, []string{"en", "fr"}},
+		{"langs", `{"metadata":{"langs":["ja"]}}`, []string{"ja"}},
+		{"languages first", `{"metadata":{"languages":["en"],"langs":["ja"]}}`, []string{"en"}},
+		{"null languages", `{"metadata":{"languages":null,"langs":["ja"]}}`, []string{"ja"}},
+		{"primary subtag", `{"metadata":{"languages":["pt-BR","zh_Hant","PT"]}}`, []string{"pt", "zh"}},
+		{"invalid tags", `{"metadata":{"languages":["en","<script>","","english!"]}}`, []string{"en"}},
+		{"empty", `{"metadata":{"languages":[]}}`, []string{}},
+		{"not reported", `{"metadata":{"nodeName":"test"}}`, nil},
+		{"not an array", `{"metadata":{"languages":"en"}}`, nil},
+		{"languages not an array", `{"metadata":{"languages":"en","langs":["ja"]}}`, []string{"ja"}},
+		{"no metadata", `{"version":"2.0"}`, nil},
+		{"invalid json", `{`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nodeinfoLanguages([]byte(tt.raw)))
+		})
+	}
+}
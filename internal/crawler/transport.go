@@ -0,0 +1,67 @@
+package crawler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/metrics"
+)
+
+// instrumentedTransport records the latency and the size of the responses of the crawler requests.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func newInstrumentedTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &instrumentedTransport{next: next}
+}
+
+func (t *instrumentedTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	e := endpoint(r)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		metrics.RequestDuration.WithLabelValues(e, "error").Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+	metrics.RequestDuration.WithLabelValues(e, strconv.Itoa(resp.StatusCode)).Observe(time.Since(start).Seconds())
+
+	resp.Body = &countingReadCloser{ReadCloser: resp.Body, counter: metrics.DownloadedBytes.WithLabelValues(e)}
+	return resp, nil
+}
+
+// endpoint returns a label for the endpoint targeted by the request.
+// Used to keep the cardinality of the metrics low.
+func endpoint(r *http.Request) string {
+	switch p := r.URL.Path; {
+	case p == "/robots.txt":
+		return "robots_txt"
+	case p == "/.well-known/nodeinfo":
+		return "well_known_nodeinfo"
+	case p == "/api/v1/instance/peers":
+		return "peers"
+	case strings.Contains(p, "nodeinfo"):
+		// the nodeinfo document can be anywhere, but it's usually under /nodeinfo/
+		return "nodeinfo"
+	default:
+		return "other"
+	}
+}
+
+type countingReadCloser struct {
+	io.ReadCloser
+	counter interface{ Add(float64) }
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.counter.Add(float64(n))
+	return n, err
+}
@@ -0,0 +1,58 @@
+package crawler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/metrics"
+)
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://mastodon.social/robots.txt", "robots_txt"},
+		{"https://mastodon.social/.well-known/nodeinfo", "well_known_nodeinfo"},
+		{"https://mastodon.social/nodeinfo/2.0", "nodeinfo"},
+		{"https://lemmy.ml/nodeinfo/2.0.json", "nodeinfo"},
+		{"https://mastodon.social/api/v1/instance/peers", "peers"},
+		{"https://mastodon.social/about", "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, endpoint(req))
+		})
+	}
+}
+
+func TestInstrumentedTransport_CountsDownloadedBytes(t *testing.T) {
+	counter := metrics.DownloadedBytes.WithLabelValues("peers")
+	before := testutil.ToFloat64(counter)
+
+	transport := newInstrumentedTransport(RoundTripFunc(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`["mastodon.social"]`)),
+		}
+	}))
+
+	req, err := http.NewRequest(http.MethodGet, "https://mastodon.social/api/v1/instance/peers", nil)
+	require.NoError(t, err)
+
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, float64(len(`["mastodon.social"]`)), testutil.ToFloat64(counter)-before)
+}
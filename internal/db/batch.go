@@ -0,0 +1,529 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.21.0
+// source: batch.go
+
+package db
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrBatchAlreadyClosed = errors.New("batch already closed")
+)
+
+const createCrawl = `-- name: CreateCrawl :batchone
+INSERT INTO crawl (
+        id,
+        instance_id,
+        status,
+        error_code,
+        error_msg,
+        started_at,
+        finished_at,
+        software_name,
+        software_version,
+        number_of_peers,
+        open_registrations,
+        total_users,
+        active_half_year,
+        active_month,
+        local_posts,
+        local_comments,
+        raw_nodeinfo,
+        addresses,
+        crawl_run_id,
+        max_peers,
+        peers_truncated,
+        languages,
+        title,
+        description
+    )
+VALUES (
+        $21,
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15,
+        $16,
+        $17,
+        $18,
+        $19,
+        $20,
+        $22,
+        $23,
+        $24
+    ) ON CONFLICT (instance_id, started_at) DO NOTHING
+RETURNING id
+`
+
+type CreateCrawlBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type CreateCrawlParams struct {
+	InstanceID        pgtype.UUID
+	Status            CrawlStatus
+	ErrorCode         NullCrawlErrorCode
+	ErrorMsg          pgtype.Text
+	StartedAt         pgtype.Timestamptz
+	FinishedAt        pgtype.Timestamptz
+	SoftwareName      pgtype.Text
+	SoftwareVersion   pgtype.Text
+	NumberOfPeers     pgtype.Int4
+	OpenRegistrations pgtype.Bool
+	TotalUsers        pgtype.Int4
+	ActiveHalfYear    pgtype.Int4
+	ActiveMonth       pgtype.Int4
+	LocalPosts        pgtype.Int4
+	LocalComments     pgtype.Int4
+	RawNodeinfo       []byte
+	Addresses         []netip.Addr
+	CrawlRunID        pgtype.UUID
+	MaxPeers          pgtype.Int4
+	PeersTruncated    bool
+	ID                pgtype.UUID
+	Languages         []string
+	Title             pgtype.Text
+	Description       pgtype.Text
+}
+
+// The ID is generated by the caller so that the crawls and the instances
+// can be written in a single batch.
+// Crawls already written, eg: replayed from the spool, are ignored: no row is returned.
+func (q *Queries) CreateCrawl(ctx context.Context, arg []CreateCrawlParams) *CreateCrawlBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.InstanceID,
+			a.Status,
+			a.ErrorCode,
+			a.ErrorMsg,
+			a.StartedAt,
+			a.FinishedAt,
+			a.SoftwareName,
+			a.SoftwareVersion,
+			a.NumberOfPeers,
+			a.OpenRegistrations,
+			a.TotalUsers,
+			a.ActiveHalfYear,
+			a.ActiveMonth,
+			a.LocalPosts,
+			a.LocalComments,
+			a.RawNodeinfo,
+			a.Addresses,
+			a.CrawlRunID,
+			a.MaxPeers,
+			a.PeersTruncated,
+			a.ID,
+			a.Languages,
+			a.Title,
+			a.Description,
+		}
+		batch.Queue(createCrawl, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &CreateCrawlBatchResults{br, len(arg), false}
+}
+
+func (b *CreateCrawlBatchResults) QueryRow(f func(int, pgtype.UUID, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		var id pgtype.UUID
+		if b.closed {
+			if f != nil {
+				f(t, id, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		row := b.br.QueryRow()
+		err := row.Scan(&id)
+		if f != nil {
+			f(t, id, err)
+		}
+	}
+}
+
+func (b *CreateCrawlBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const createIncident = `-- name: CreateIncident :batchexec
+INSERT INTO incident (
+        id,
+        instance_id,
+        first_crawl_id,
+        started_at,
+        error_code,
+        failed_crawls,
+        confirmed_at
+    )
+SELECT $1,
+    instance.id,
+    crawl.id,
+    crawl.started_at,
+    crawl.error_code,
+    $2,
+    $3
+FROM instance
+    JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.id = $4
+    AND crawl.id = $5 ON CONFLICT (instance_id)
+WHERE closed_at IS NULL DO NOTHING
+`
+
+type CreateIncidentBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type CreateIncidentParams struct {
+	ID           pgtype.UUID
+	FailedCrawls int32
+	ConfirmedAt  pgtype.Timestamptz
+	InstanceID   pgtype.UUID
+	CrawlID      pgtype.UUID
+}
+
+// Only if the crawl is the last crawl of the instance, so that replaying old crawls does not open incidents.
+func (q *Queries) CreateIncident(ctx context.Context, arg []CreateIncidentParams) *CreateIncidentBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ID,
+			a.FailedCrawls,
+			a.ConfirmedAt,
+			a.InstanceID,
+			a.CrawlID,
+		}
+		batch.Queue(createIncident, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &CreateIncidentBatchResults{br, len(arg), false}
+}
+
+func (b *CreateIncidentBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *CreateIncidentBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const deleteIncident = `-- name: DeleteIncident :batchexec
+DELETE FROM incident USING instance
+WHERE incident.id = $1
+    AND instance.id = incident.instance_id
+    AND instance.last_crawl_id = $2
+`
+
+type DeleteIncidentBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type DeleteIncidentParams struct {
+	ID      pgtype.UUID
+	CrawlID pgtype.UUID
+}
+
+// Only if the crawl is the last crawl of the instance, see CreateIncident.
+func (q *Queries) DeleteIncident(ctx context.Context, arg []DeleteIncidentParams) *DeleteIncidentBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ID,
+			a.CrawlID,
+		}
+		batch.Queue(deleteIncident, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &DeleteIncidentBatchResults{br, len(arg), false}
+}
+
+func (b *DeleteIncidentBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *DeleteIncidentBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const updateIncident = `-- name: UpdateIncident :batchexec
+UPDATE incident
+SET failed_crawls = $1,
+    confirmed_at = COALESCE(incident.confirmed_at, $2),
+    recovered_crawls = $3,
+    recovering_since = $4,
+    closed_at = $5
+FROM instance
+WHERE incident.id = $6
+    AND instance.id = incident.instance_id
+    AND instance.last_crawl_id = $7
+`
+
+type UpdateIncidentBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpdateIncidentParams struct {
+	FailedCrawls    int32
+	ConfirmedAt     pgtype.Timestamptz
+	RecoveredCrawls int32
+	RecoveringSince pgtype.Timestamptz
+	ClosedAt        pgtype.Timestamptz
+	ID              pgtype.UUID
+	CrawlID         pgtype.UUID
+}
+
+// Only if the crawl is the last crawl of the instance, see CreateIncident.
+// The confirmation time is kept once set.
+func (q *Queries) UpdateIncident(ctx context.Context, arg []UpdateIncidentParams) *UpdateIncidentBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.FailedCrawls,
+			a.ConfirmedAt,
+			a.RecoveredCrawls,
+			a.RecoveringSince,
+			a.ClosedAt,
+			a.ID,
+			a.CrawlID,
+		}
+		batch.Queue(updateIncident, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpdateIncidentBatchResults{br, len(arg), false}
+}
+
+func (b *UpdateIncidentBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpdateIncidentBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const updateInstanceFromLastCrawl = `-- name: UpdateInstanceFromLastCrawl :batchexec
+UPDATE instance
+SET last_crawl_id = $2,
+    status = $3,
+    software_name = $4,
+    next_crawl_at = $5,
+    consecutive_failures = $6,
+    failing_since = $7,
+    tombstoned_at = $8,
+    not_found_since = $9,
+    title = CASE
+        WHEN new_crawl.status = 'completed' THEN new_crawl.title
+        ELSE instance.title
+    END,
+    description = CASE
+        WHEN new_crawl.status = 'completed' THEN new_crawl.description
+        ELSE instance.description
+    END,
+    last_total_users = new_crawl.total_users,
+    last_active_month = new_crawl.active_month,
+    last_local_posts = new_crawl.local_posts,
+    last_number_of_peers = new_crawl.number_of_peers,
+    last_crawled_at = new_crawl.started_at,
+    updated_at = NOW()
+FROM crawl AS new_crawl
+WHERE instance.id = $1
+    AND new_crawl.id = $2
+    AND NOT EXISTS (
+        SELECT 1
+        FROM crawl AS last_crawl
+        WHERE last_crawl.id = instance.last_crawl_id
+            AND last_crawl.started_at >= new_crawl.started_at
+    )
+`
+
+type UpdateInstanceFromLastCrawlBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpdateInstanceFromLastCrawlParams struct {
+	ID                  pgtype.UUID
+	LastCrawlID         pgtype.UUID
+	Status              InstanceStatus
+	SoftwareName        pgtype.Text
+	NextCrawlAt         pgtype.Timestamptz
+	ConsecutiveFailures int32
+	FailingSince        pgtype.Timestamptz
+	TombstonedAt        pgtype.Timestamptz
+	NotFoundSince       pgtype.Timestamptz
+}
+
+// Only if the crawl was written and is more recent than the last crawl of the instance,
+// so that replaying old crawls does not overwrite the state of the instance.
+// The title and the description are kept from the last completed crawl.
+// The sort keys of the instances listing are copied from the crawl.
+func (q *Queries) UpdateInstanceFromLastCrawl(ctx context.Context, arg []UpdateInstanceFromLastCrawlParams) *UpdateInstanceFromLastCrawlBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.ID,
+			a.LastCrawlID,
+			a.Status,
+			a.SoftwareName,
+			a.NextCrawlAt,
+			a.ConsecutiveFailures,
+			a.FailingSince,
+			a.TombstonedAt,
+			a.NotFoundSince,
+		}
+		batch.Queue(updateInstanceFromLastCrawl, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpdateInstanceFromLastCrawlBatchResults{br, len(arg), false}
+}
+
+func (b *UpdateInstanceFromLastCrawlBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpdateInstanceFromLastCrawlBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
+
+const updateInstanceUptime = `-- name: UpdateInstanceUptime :batchexec
+UPDATE instance
+SET uptime_24h = $1,
+    uptime_7d = $2,
+    uptime_30d = $3,
+    uptime_90d = $4,
+    uptime_updated_at = $5
+WHERE id = $6
+`
+
+type UpdateInstanceUptimeBatchResults struct {
+	br     pgx.BatchResults
+	tot    int
+	closed bool
+}
+
+type UpdateInstanceUptimeParams struct {
+	Uptime24h pgtype.Float8
+	Uptime7d  pgtype.Float8
+	Uptime30d pgtype.Float8
+	Uptime90d pgtype.Float8
+	UpdatedAt pgtype.Timestamptz
+	ID        pgtype.UUID
+}
+
+func (q *Queries) UpdateInstanceUptime(ctx context.Context, arg []UpdateInstanceUptimeParams) *UpdateInstanceUptimeBatchResults {
+	batch := &pgx.Batch{}
+	for _, a := range arg {
+		vals := []interface{}{
+			a.Uptime24h,
+			a.Uptime7d,
+			a.Uptime30d,
+			a.Uptime90d,
+			a.UpdatedAt,
+			a.ID,
+		}
+		batch.Queue(updateInstanceUptime, vals...)
+	}
+	br := q.db.SendBatch(ctx, batch)
+	return &UpdateInstanceUptimeBatchResults{br, len(arg), false}
+}
+
+func (b *UpdateInstanceUptimeBatchResults) Exec(f func(int, error)) {
+	defer b.br.Close()
+	for t := 0; t < b.tot; t++ {
+		if b.closed {
+			if f != nil {
+				f(t, ErrBatchAlreadyClosed)
+			}
+			continue
+		}
+		_, err := b.br.Exec()
+		if f != nil {
+			f(t, err)
+		}
+	}
+}
+
+func (b *UpdateInstanceUptimeBatchResults) Close() error {
+	b.closed = true
+	return b.br.Close()
+}
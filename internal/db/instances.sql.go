@@ -0,0 +1,3448 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.21.0
+// source: instances.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countInstances = `-- name: CountInstances :one
+
+
+SELECT COUNT(*)
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+`
+
+type CountInstancesParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+}
+
+// Code generated from db/sql/gen/instances.sql.tmpl by go generate ./internal/business. DO NOT EDIT.
+// The number of instances matching the filters of the ListInstancesBy queries.
+func (q *Queries) CountInstances(ctx context.Context, arg CountInstancesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countInstances,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const listInstancesByActiveMonthAsc = `-- name: ListInstancesByActiveMonthAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.last_active_month, -1), instance.id) > (
+    coalesce($10::text::integer, -2147483648),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.last_active_month, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByActiveMonthAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByActiveMonthAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.last_active_month, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByActiveMonthAsc(ctx context.Context, arg ListInstancesByActiveMonthAscParams) ([]ListInstancesByActiveMonthAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByActiveMonthAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByActiveMonthAscRow
+	for rows.Next() {
+		var i ListInstancesByActiveMonthAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByActiveMonthDesc = `-- name: ListInstancesByActiveMonthDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.last_active_month, -1), instance.id) < (
+    coalesce($10::text::integer, 2147483647),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.last_active_month, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByActiveMonthDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByActiveMonthDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.last_active_month, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByActiveMonthDesc(ctx context.Context, arg ListInstancesByActiveMonthDescParams) ([]ListInstancesByActiveMonthDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByActiveMonthDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByActiveMonthDescRow
+	for rows.Next() {
+		var i ListInstancesByActiveMonthDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByDomainAsc = `-- name: ListInstancesByDomainAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.domain, instance.id) > (
+    coalesce($10::text, ''),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY instance.domain,
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByDomainAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByDomainAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.domain, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByDomainAsc(ctx context.Context, arg ListInstancesByDomainAscParams) ([]ListInstancesByDomainAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByDomainAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByDomainAscRow
+	for rows.Next() {
+		var i ListInstancesByDomainAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByDomainDesc = `-- name: ListInstancesByDomainDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.domain, instance.id) < (
+    coalesce($10::text, (SELECT max(domain) FROM instance)),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY instance.domain DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByDomainDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByDomainDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.domain DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByDomainDesc(ctx context.Context, arg ListInstancesByDomainDescParams) ([]ListInstancesByDomainDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByDomainDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByDomainDescRow
+	for rows.Next() {
+		var i ListInstancesByDomainDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByFirstSeenAtAsc = `-- name: ListInstancesByFirstSeenAtAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.created_at, instance.id) > (
+    coalesce($10::text::timestamptz, '-infinity'),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY instance.created_at,
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByFirstSeenAtAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByFirstSeenAtAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.created_at, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByFirstSeenAtAsc(ctx context.Context, arg ListInstancesByFirstSeenAtAscParams) ([]ListInstancesByFirstSeenAtAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByFirstSeenAtAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByFirstSeenAtAscRow
+	for rows.Next() {
+		var i ListInstancesByFirstSeenAtAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByFirstSeenAtDesc = `-- name: ListInstancesByFirstSeenAtDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.created_at, instance.id) < (
+    coalesce($10::text::timestamptz, 'infinity'),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY instance.created_at DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByFirstSeenAtDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByFirstSeenAtDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.created_at DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByFirstSeenAtDesc(ctx context.Context, arg ListInstancesByFirstSeenAtDescParams) ([]ListInstancesByFirstSeenAtDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByFirstSeenAtDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByFirstSeenAtDescRow
+	for rows.Next() {
+		var i ListInstancesByFirstSeenAtDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByLastCrawledAtAsc = `-- name: ListInstancesByLastCrawledAtAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.last_crawled_at, instance.id) > (
+    coalesce($10::text::timestamptz, '-infinity'),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY instance.last_crawled_at,
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByLastCrawledAtAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByLastCrawledAtAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.last_crawled_at, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByLastCrawledAtAsc(ctx context.Context, arg ListInstancesByLastCrawledAtAscParams) ([]ListInstancesByLastCrawledAtAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByLastCrawledAtAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByLastCrawledAtAscRow
+	for rows.Next() {
+		var i ListInstancesByLastCrawledAtAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByLastCrawledAtDesc = `-- name: ListInstancesByLastCrawledAtDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.last_crawled_at, instance.id) < (
+    coalesce($10::text::timestamptz, 'infinity'),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY instance.last_crawled_at DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByLastCrawledAtDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByLastCrawledAtDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.last_crawled_at DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByLastCrawledAtDesc(ctx context.Context, arg ListInstancesByLastCrawledAtDescParams) ([]ListInstancesByLastCrawledAtDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByLastCrawledAtDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByLastCrawledAtDescRow
+	for rows.Next() {
+		var i ListInstancesByLastCrawledAtDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByLocalPostsAsc = `-- name: ListInstancesByLocalPostsAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.last_local_posts, -1), instance.id) > (
+    coalesce($10::text::integer, -2147483648),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.last_local_posts, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByLocalPostsAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByLocalPostsAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.last_local_posts, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByLocalPostsAsc(ctx context.Context, arg ListInstancesByLocalPostsAscParams) ([]ListInstancesByLocalPostsAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByLocalPostsAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByLocalPostsAscRow
+	for rows.Next() {
+		var i ListInstancesByLocalPostsAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByLocalPostsDesc = `-- name: ListInstancesByLocalPostsDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.last_local_posts, -1), instance.id) < (
+    coalesce($10::text::integer, 2147483647),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.last_local_posts, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByLocalPostsDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByLocalPostsDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.last_local_posts, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByLocalPostsDesc(ctx context.Context, arg ListInstancesByLocalPostsDescParams) ([]ListInstancesByLocalPostsDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByLocalPostsDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByLocalPostsDescRow
+	for rows.Next() {
+		var i ListInstancesByLocalPostsDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByNumberOfPeersAsc = `-- name: ListInstancesByNumberOfPeersAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.last_number_of_peers, -1), instance.id) > (
+    coalesce($10::text::integer, -2147483648),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.last_number_of_peers, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByNumberOfPeersAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByNumberOfPeersAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.last_number_of_peers, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByNumberOfPeersAsc(ctx context.Context, arg ListInstancesByNumberOfPeersAscParams) ([]ListInstancesByNumberOfPeersAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByNumberOfPeersAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByNumberOfPeersAscRow
+	for rows.Next() {
+		var i ListInstancesByNumberOfPeersAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByNumberOfPeersDesc = `-- name: ListInstancesByNumberOfPeersDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.last_number_of_peers, -1), instance.id) < (
+    coalesce($10::text::integer, 2147483647),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.last_number_of_peers, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByNumberOfPeersDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByNumberOfPeersDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.last_number_of_peers, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByNumberOfPeersDesc(ctx context.Context, arg ListInstancesByNumberOfPeersDescParams) ([]ListInstancesByNumberOfPeersDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByNumberOfPeersDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByNumberOfPeersDescRow
+	for rows.Next() {
+		var i ListInstancesByNumberOfPeersDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByTotalUsersAsc = `-- name: ListInstancesByTotalUsersAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.last_total_users, instance.id) > (
+    coalesce($10::text::integer, -2147483648),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY instance.last_total_users,
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByTotalUsersAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByTotalUsersAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.last_total_users, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByTotalUsersAsc(ctx context.Context, arg ListInstancesByTotalUsersAscParams) ([]ListInstancesByTotalUsersAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByTotalUsersAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByTotalUsersAscRow
+	for rows.Next() {
+		var i ListInstancesByTotalUsersAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByTotalUsersDesc = `-- name: ListInstancesByTotalUsersDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (instance.last_total_users, instance.id) < (
+    coalesce($10::text::integer, 2147483647),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY instance.last_total_users DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByTotalUsersDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByTotalUsersDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by instance.last_total_users DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByTotalUsersDesc(ctx context.Context, arg ListInstancesByTotalUsersDescParams) ([]ListInstancesByTotalUsersDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByTotalUsersDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByTotalUsersDescRow
+	for rows.Next() {
+		var i ListInstancesByTotalUsersDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime24hAsc = `-- name: ListInstancesByUptime24hAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_24h, -1), instance.id) > (
+    coalesce($10::text::float8, '-Infinity'),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.uptime_24h, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime24hAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime24hAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_24h, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime24hAsc(ctx context.Context, arg ListInstancesByUptime24hAscParams) ([]ListInstancesByUptime24hAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime24hAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime24hAscRow
+	for rows.Next() {
+		var i ListInstancesByUptime24hAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime24hDesc = `-- name: ListInstancesByUptime24hDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_24h, -1), instance.id) < (
+    coalesce($10::text::float8, 'Infinity'),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.uptime_24h, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime24hDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime24hDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_24h, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime24hDesc(ctx context.Context, arg ListInstancesByUptime24hDescParams) ([]ListInstancesByUptime24hDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime24hDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime24hDescRow
+	for rows.Next() {
+		var i ListInstancesByUptime24hDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime30dAsc = `-- name: ListInstancesByUptime30dAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_30d, -1), instance.id) > (
+    coalesce($10::text::float8, '-Infinity'),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.uptime_30d, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime30dAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime30dAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_30d, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime30dAsc(ctx context.Context, arg ListInstancesByUptime30dAscParams) ([]ListInstancesByUptime30dAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime30dAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime30dAscRow
+	for rows.Next() {
+		var i ListInstancesByUptime30dAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime30dDesc = `-- name: ListInstancesByUptime30dDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_30d, -1), instance.id) < (
+    coalesce($10::text::float8, 'Infinity'),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.uptime_30d, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime30dDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime30dDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_30d, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime30dDesc(ctx context.Context, arg ListInstancesByUptime30dDescParams) ([]ListInstancesByUptime30dDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime30dDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime30dDescRow
+	for rows.Next() {
+		var i ListInstancesByUptime30dDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime7dAsc = `-- name: ListInstancesByUptime7dAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_7d, -1), instance.id) > (
+    coalesce($10::text::float8, '-Infinity'),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.uptime_7d, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime7dAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime7dAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_7d, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime7dAsc(ctx context.Context, arg ListInstancesByUptime7dAscParams) ([]ListInstancesByUptime7dAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime7dAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime7dAscRow
+	for rows.Next() {
+		var i ListInstancesByUptime7dAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime7dDesc = `-- name: ListInstancesByUptime7dDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_7d, -1), instance.id) < (
+    coalesce($10::text::float8, 'Infinity'),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.uptime_7d, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime7dDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime7dDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_7d, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime7dDesc(ctx context.Context, arg ListInstancesByUptime7dDescParams) ([]ListInstancesByUptime7dDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime7dDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime7dDescRow
+	for rows.Next() {
+		var i ListInstancesByUptime7dDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime90dAsc = `-- name: ListInstancesByUptime90dAsc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_90d, -1), instance.id) > (
+    coalesce($10::text::float8, '-Infinity'),
+    coalesce($11::uuid, '00000000-0000-0000-0000-000000000000')
+  )
+ORDER BY coalesce(instance.uptime_90d, -1),
+  instance.id
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime90dAscParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime90dAscRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_90d, -1), then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime90dAsc(ctx context.Context, arg ListInstancesByUptime90dAscParams) ([]ListInstancesByUptime90dAscRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime90dAsc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime90dAscRow
+	for rows.Next() {
+		var i ListInstancesByUptime90dAscRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstancesByUptime90dDesc = `-- name: ListInstancesByUptime90dDesc :many
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE instance.deleted_at IS NULL
+  AND instance.last_total_users > $1::integer
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::text [] IS NULL
+    OR instance.software_name = ANY($3::text [])
+  )
+  AND (
+    $4::instance_status IS NULL
+    OR instance.status = $4::instance_status
+  )
+  AND (
+    $5::boolean IS NULL
+    OR crawl.open_registrations = $5::boolean
+  )
+  AND (
+    $6::integer IS NULL
+    OR instance.last_total_users >= $6::integer
+  )
+  AND (
+    $7::integer IS NULL
+    OR instance.last_total_users <= $7::integer
+  )
+  AND (
+    $8::text IS NULL
+    OR starts_with(crawl.software_version, $8::text)
+  )
+  AND (
+    $9::text IS NULL
+    OR $9::text = ANY(crawl.languages)
+  )
+  AND (coalesce(instance.uptime_90d, -1), instance.id) < (
+    coalesce($10::text::float8, 'Infinity'),
+    coalesce($11::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')
+  )
+ORDER BY coalesce(instance.uptime_90d, -1) DESC,
+  instance.id DESC
+LIMIT $13 OFFSET $12
+`
+
+type ListInstancesByUptime90dDescParams struct {
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Software             []string
+	Status               NullInstanceStatus
+	OpenRegistrations    pgtype.Bool
+	MinUsers             pgtype.Int4
+	MaxUsers             pgtype.Int4
+	VersionPrefix        pgtype.Text
+	Language             pgtype.Text
+	AfterValue           pgtype.Text
+	AfterID              pgtype.UUID
+	Offset               int32
+	Limit                int32
+}
+
+type ListInstancesByUptime90dDescRow struct {
+	Instance Instance
+	Crawl    Crawl
+}
+
+// The instances sorted by coalesce(instance.uptime_90d, -1) DESC, then by id, in the order of the index of the key.
+// The page starts after the instance whose sort key and id are after_value and after_id, if set,
+// after_value is the sort key as text. The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListInstancesByUptime90dDesc(ctx context.Context, arg ListInstancesByUptime90dDescParams) ([]ListInstancesByUptime90dDescRow, error) {
+	rows, err := q.db.Query(ctx, listInstancesByUptime90dDesc,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Software,
+		arg.Status,
+		arg.OpenRegistrations,
+		arg.MinUsers,
+		arg.MaxUsers,
+		arg.VersionPrefix,
+		arg.Language,
+		arg.AfterValue,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInstancesByUptime90dDescRow
+	for rows.Next() {
+		var i ListInstancesByUptime90dDescRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
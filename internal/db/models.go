@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.19.1
+//   sqlc v1.21.0
 
 package db
 
@@ -150,9 +150,20 @@ func (ns NullInstanceStatus) Value() (driver.Value, error) {
 	return string(ns.InstanceStatus), nil
 }
 
+type BlockedDomain struct {
+	ID        pgtype.UUID
+	Pattern   string
+	Reason    string
+	Source    string
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	ExpiresAt pgtype.Timestamptz
+}
+
 type Crawl struct {
 	ID                pgtype.UUID
 	InstanceID        pgtype.UUID
+	CrawlRunID        pgtype.UUID
 	Status            CrawlStatus
 	ErrorCode         NullCrawlErrorCode
 	ErrorMsg          pgtype.Text
@@ -169,6 +180,11 @@ type Crawl struct {
 	LocalComments     pgtype.Int4
 	RawNodeinfo       []byte
 	Addresses         []netip.Addr
+	MaxPeers          pgtype.Int4
+	PeersTruncated    bool
+	Languages         []string
+	Title             pgtype.Text
+	Description       pgtype.Text
 }
 
 type CrawlError struct {
@@ -176,18 +192,100 @@ type CrawlError struct {
 	Description string
 }
 
+type CrawlRun struct {
+	ID             pgtype.UUID
+	StartedAt      pgtype.Timestamptz
+	FinishedAt     pgtype.Timestamptz
+	CrawlerVersion string
+	Seeds          []string
+	Config         []byte
+	Attempted      int32
+	Completed      int32
+	Failed         int32
+	Discovered     int32
+	Rejected       int32
+	Blocked        int32
+	Lost           int32
+	ErrorCounts    []byte
+	SoftwareCounts []byte
+	BlockedGroups  []byte
+}
+
+type GlobalDailyRollup struct {
+	Day             pgtype.Date
+	Instances       int32
+	InstancesUp     int32
+	Crawls          int32
+	CompletedCrawls int32
+	TotalUsers      int64
+	ActiveMonth     int64
+	LocalPosts      int64
+	RolledUpAt      pgtype.Timestamptz
+}
+
+type Incident struct {
+	ID              pgtype.UUID
+	InstanceID      pgtype.UUID
+	FirstCrawlID    pgtype.UUID
+	StartedAt       pgtype.Timestamptz
+	ErrorCode       NullCrawlErrorCode
+	FailedCrawls    int32
+	ConfirmedAt     pgtype.Timestamptz
+	RecoveredCrawls int32
+	RecoveringSince pgtype.Timestamptz
+	ClosedAt        pgtype.Timestamptz
+}
+
 type Instance struct {
-	ID           pgtype.UUID
-	Domain       string
-	Status       InstanceStatus
-	CreatedAt    pgtype.Timestamptz
-	DeletedAt    pgtype.Timestamptz
-	UpdatedAt    pgtype.Timestamptz
-	SoftwareName pgtype.Text
-	LastCrawlID  pgtype.UUID
+	ID                  pgtype.UUID
+	Domain              string
+	Status              InstanceStatus
+	CreatedAt           pgtype.Timestamptz
+	DeletedAt           pgtype.Timestamptz
+	UpdatedAt           pgtype.Timestamptz
+	SoftwareName        pgtype.Text
+	LastCrawlID         pgtype.UUID
+	NextCrawlAt         pgtype.Timestamptz
+	ConsecutiveFailures int32
+	FailingSince        pgtype.Timestamptz
+	TombstonedAt        pgtype.Timestamptz
+	Title               pgtype.Text
+	Description         pgtype.Text
+	Uptime24h           pgtype.Float8
+	Uptime7d            pgtype.Float8
+	Uptime30d           pgtype.Float8
+	Uptime90d           pgtype.Float8
+	UptimeUpdatedAt     pgtype.Timestamptz
+	NotFoundSince       pgtype.Timestamptz
+	LastTotalUsers      pgtype.Int4
+	LastActiveMonth     pgtype.Int4
+	LastLocalPosts      pgtype.Int4
+	LastNumberOfPeers   pgtype.Int4
+	LastCrawledAt       pgtype.Timestamptz
+}
+
+type InstanceDailyRollup struct {
+	InstanceID      pgtype.UUID
+	Day             pgtype.Date
+	Crawls          int32
+	CompletedCrawls int32
+	LastUsers       pgtype.Int4
+	MinUsers        pgtype.Int4
+	MaxUsers        pgtype.Int4
+	LastActiveMonth pgtype.Int4
+	MinActiveMonth  pgtype.Int4
+	MaxActiveMonth  pgtype.Int4
+	LastLocalPosts  pgtype.Int4
+	MinLocalPosts   pgtype.Int4
+	MaxLocalPosts   pgtype.Int4
+	RolledUpAt      pgtype.Timestamptz
 }
 
 type PeeringRelationship struct {
-	InstanceID pgtype.UUID
-	PeerID     pgtype.UUID
+	InstanceID  pgtype.UUID
+	PeerID      pgtype.UUID
+	FirstSeenAt pgtype.Timestamptz
+	LastSeenAt  pgtype.Timestamptz
+	LastCrawlID pgtype.UUID
+	RemovedAt   pgtype.Timestamptz
 }
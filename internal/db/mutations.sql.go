@@ -1,119 +1,54 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.19.1
+//   sqlc v1.21.0
 // source: mutations.sql
 
 package db
 
 import (
 	"context"
-	"net/netip"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createCrawl = `-- name: CreateCrawl :one
-INSERT INTO crawl (
-        instance_id,
-        status,
-        error_code,
-        error_msg,
-        started_at,
-        finished_at,
-        software_name,
-        software_version,
-        number_of_peers,
-        open_registrations,
-        total_users,
-        active_half_year,
-        active_month,
-        local_posts,
-        local_comments,
-        raw_nodeinfo,
-        addresses
-    )
-VALUES (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8,
-        $9,
-        $10,
-        $11,
-        $12,
-        $13,
-        $14,
-        $15,
-        $16,
-        $17
-    )
-RETURNING id, instance_id, status, error_code, error_msg, started_at, finished_at, software_name, software_version, number_of_peers, open_registrations, total_users, active_half_year, active_month, local_posts, local_comments, raw_nodeinfo, addresses
+const createCrawlRun = `-- name: CreateCrawlRun :one
+INSERT INTO crawl_run (started_at, crawler_version, seeds, config)
+VALUES ($1, $2, $3, $4)
+RETURNING id, started_at, finished_at, crawler_version, seeds, config, attempted, completed, failed, discovered, rejected, blocked, lost, error_counts, software_counts, blocked_groups
 `
 
-type CreateCrawlParams struct {
-	InstanceID        pgtype.UUID
-	Status            CrawlStatus
-	ErrorCode         NullCrawlErrorCode
-	ErrorMsg          pgtype.Text
-	StartedAt         pgtype.Timestamptz
-	FinishedAt        pgtype.Timestamptz
-	SoftwareName      pgtype.Text
-	SoftwareVersion   pgtype.Text
-	NumberOfPeers     pgtype.Int4
-	OpenRegistrations pgtype.Bool
-	TotalUsers        pgtype.Int4
-	ActiveHalfYear    pgtype.Int4
-	ActiveMonth       pgtype.Int4
-	LocalPosts        pgtype.Int4
-	LocalComments     pgtype.Int4
-	RawNodeinfo       []byte
-	Addresses         []netip.Addr
-}
-
-func (q *Queries) CreateCrawl(ctx context.Context, arg CreateCrawlParams) (Crawl, error) {
-	row := q.db.QueryRow(ctx, createCrawl,
-		arg.InstanceID,
-		arg.Status,
-		arg.ErrorCode,
-		arg.ErrorMsg,
+type CreateCrawlRunParams struct {
+	StartedAt      pgtype.Timestamptz
+	CrawlerVersion string
+	Seeds          []string
+	Config         []byte
+}
+
+func (q *Queries) CreateCrawlRun(ctx context.Context, arg CreateCrawlRunParams) (CrawlRun, error) {
+	row := q.db.QueryRow(ctx, createCrawlRun,
 		arg.StartedAt,
-		arg.FinishedAt,
-		arg.SoftwareName,
-		arg.SoftwareVersion,
-		arg.NumberOfPeers,
-		arg.OpenRegistrations,
-		arg.TotalUsers,
-		arg.ActiveHalfYear,
-		arg.ActiveMonth,
-		arg.LocalPosts,
-		arg.LocalComments,
-		arg.RawNodeinfo,
-		arg.Addresses,
+		arg.CrawlerVersion,
+		arg.Seeds,
+		arg.Config,
 	)
-	var i Crawl
+	var i CrawlRun
 	err := row.Scan(
 		&i.ID,
-		&i.InstanceID,
-		&i.Status,
-		&i.ErrorCode,
-		&i.ErrorMsg,
 		&i.StartedAt,
 		&i.FinishedAt,
-		&i.SoftwareName,
-		&i.SoftwareVersion,
-		&i.NumberOfPeers,
-		&i.OpenRegistrations,
-		&i.TotalUsers,
-		&i.ActiveHalfYear,
-		&i.ActiveMonth,
-		&i.LocalPosts,
-		&i.LocalComments,
-		&i.RawNodeinfo,
-		&i.Addresses,
+		&i.CrawlerVersion,
+		&i.Seeds,
+		&i.Config,
+		&i.Attempted,
+		&i.Completed,
+		&i.Failed,
+		&i.Discovered,
+		&i.Rejected,
+		&i.Blocked,
+		&i.Lost,
+		&i.ErrorCounts,
+		&i.SoftwareCounts,
+		&i.BlockedGroups,
 	)
 	return i, err
 }
@@ -121,7 +56,7 @@ func (q *Queries) CreateCrawl(ctx context.Context, arg CreateCrawlParams) (Crawl
 const createInstance = `-- name: CreateInstance :one
 INSERT INTO instance (domain, software_name)
 VALUES ($1, $2)
-RETURNING id, domain, status, created_at, deleted_at, updated_at, software_name, last_crawl_id
+RETURNING id, domain, status, created_at, deleted_at, updated_at, software_name, last_crawl_id, next_crawl_at, consecutive_failures, failing_since, tombstoned_at, title, description, uptime_24h, uptime_7d, uptime_30d, uptime_90d, uptime_updated_at, not_found_since, last_total_users, last_active_month, last_local_posts, last_number_of_peers, last_crawled_at
 `
 
 type CreateInstanceParams struct {
@@ -141,6 +76,23 @@ func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams)
 		&i.UpdatedAt,
 		&i.SoftwareName,
 		&i.LastCrawlID,
+		&i.NextCrawlAt,
+		&i.ConsecutiveFailures,
+		&i.FailingSince,
+		&i.TombstonedAt,
+		&i.Title,
+		&i.Description,
+		&i.Uptime24h,
+		&i.Uptime7d,
+		&i.Uptime30d,
+		&i.Uptime90d,
+		&i.UptimeUpdatedAt,
+		&i.NotFoundSince,
+		&i.LastTotalUsers,
+		&i.LastActiveMonth,
+		&i.LastLocalPosts,
+		&i.LastNumberOfPeers,
+		&i.LastCrawledAt,
 	)
 	return i, err
 }
@@ -166,46 +118,378 @@ func (q *Queries) DeleteInstanceByID(ctx context.Context, id pgtype.UUID) error
 	return err
 }
 
-const updateInstanceFromLastCrawl = `-- name: UpdateInstanceFromLastCrawl :exec
-UPDATE instance
-SET last_crawl_id = $2,
-    status = $3,
-    software_name = $4,
-    updated_at = NOW()
-WHERE id = $1
+const finishCrawlRun = `-- name: FinishCrawlRun :one
+UPDATE crawl_run
+SET finished_at = $1,
+    rejected = $2,
+    blocked = $3,
+    blocked_groups = $4,
+    lost = $5,
+    attempted = (
+        SELECT COUNT(*)
+        FROM crawl
+        WHERE crawl.crawl_run_id = $6
+    ),
+    completed = (
+        SELECT COUNT(*)
+        FROM crawl
+        WHERE crawl.crawl_run_id = $6
+            AND crawl.status = 'completed'
+    ),
+    failed = (
+        SELECT COUNT(*)
+        FROM crawl
+        WHERE crawl.crawl_run_id = $6
+            AND crawl.status = 'failed'
+    ),
+    discovered = (
+        SELECT COUNT(*)
+        FROM instance
+        WHERE instance.created_at >= crawl_run.started_at
+            AND instance.created_at <= $1
+    ),
+    error_counts = (
+        SELECT COALESCE(jsonb_object_agg(error_code, n), '{}')
+        FROM (
+                SELECT error_code,
+                    COUNT(*) AS n
+                FROM crawl
+                WHERE crawl.crawl_run_id = $6
+                    AND crawl.error_code IS NOT NULL
+                GROUP BY error_code
+            ) AS errors
+    ),
+    software_counts = (
+        SELECT COALESCE(jsonb_object_agg(software_name, n), '{}')
+        FROM (
+                SELECT software_name,
+                    COUNT(*) AS n
+                FROM crawl
+                WHERE crawl.crawl_run_id = $6
+                    AND crawl.software_name IS NOT NULL
+                    AND crawl.software_name != ''
+                GROUP BY software_name
+            ) AS software
+    )
+WHERE id = $6
+RETURNING id, started_at, finished_at, crawler_version, seeds, config, attempted, completed, failed, discovered, rejected, blocked, lost, error_counts, software_counts, blocked_groups
 `
 
-type UpdateInstanceFromLastCrawlParams struct {
-	ID           pgtype.UUID
-	LastCrawlID  pgtype.UUID
-	Status       InstanceStatus
-	SoftwareName pgtype.Text
+type FinishCrawlRunParams struct {
+	FinishedAt    pgtype.Timestamptz
+	Rejected      int32
+	Blocked       int32
+	BlockedGroups []byte
+	Lost          int32
+	ID            pgtype.UUID
 }
 
-func (q *Queries) UpdateInstanceFromLastCrawl(ctx context.Context, arg UpdateInstanceFromLastCrawlParams) error {
-	_, err := q.db.Exec(ctx, updateInstanceFromLastCrawl,
+// Counts are derived from the crawls of the run, except for the ones
+// only known by the orchestrator (rejected and blocked peers, lost crawls).
+func (q *Queries) FinishCrawlRun(ctx context.Context, arg FinishCrawlRunParams) (CrawlRun, error) {
+	row := q.db.QueryRow(ctx, finishCrawlRun,
+		arg.FinishedAt,
+		arg.Rejected,
+		arg.Blocked,
+		arg.BlockedGroups,
+		arg.Lost,
 		arg.ID,
-		arg.LastCrawlID,
-		arg.Status,
-		arg.SoftwareName,
 	)
+	var i CrawlRun
+	err := row.Scan(
+		&i.ID,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.CrawlerVersion,
+		&i.Seeds,
+		&i.Config,
+		&i.Attempted,
+		&i.Completed,
+		&i.Failed,
+		&i.Discovered,
+		&i.Rejected,
+		&i.Blocked,
+		&i.Lost,
+		&i.ErrorCounts,
+		&i.SoftwareCounts,
+		&i.BlockedGroups,
+	)
+	return i, err
+}
+
+const removeUnseenPeeringRelationships = `-- name: RemoveUnseenPeeringRelationships :exec
+UPDATE peering_relationship
+SET removed_at = $1
+WHERE instance_id = $2
+    AND removed_at IS NULL
+    AND last_seen_at < $1
+`
+
+type RemoveUnseenPeeringRelationshipsParams struct {
+	SeenAt     pgtype.Timestamptz
+	InstanceID pgtype.UUID
+}
+
+// Marks as removed the edges not listed by a crawl with a complete list of peers.
+func (q *Queries) RemoveUnseenPeeringRelationships(ctx context.Context, arg RemoveUnseenPeeringRelationshipsParams) error {
+	_, err := q.db.Exec(ctx, removeUnseenPeeringRelationships, arg.SeenAt, arg.InstanceID)
+	return err
+}
+
+const rollupGlobalDays = `-- name: RollupGlobalDays :exec
+INSERT INTO global_daily_rollup (
+        day,
+        instances,
+        instances_up,
+        crawls,
+        completed_crawls,
+        total_users,
+        active_month,
+        local_posts,
+        rolled_up_at
+    )
+SELECT instance_daily_rollup.day,
+    COUNT(*),
+    COUNT(*) FILTER (
+        WHERE instance_daily_rollup.completed_crawls > 0
+    ),
+    SUM(instance_daily_rollup.crawls),
+    SUM(instance_daily_rollup.completed_crawls),
+    COALESCE(
+        SUM(
+            COALESCE(instance_daily_rollup.last_users, known.last_users)
+        ),
+        0
+    ),
+    COALESCE(
+        SUM(
+            COALESCE(
+                instance_daily_rollup.last_active_month,
+                known.last_active_month
+            )
+        ),
+        0
+    ),
+    COALESCE(
+        SUM(
+            COALESCE(
+                instance_daily_rollup.last_local_posts,
+                known.last_local_posts
+            )
+        ),
+        0
+    ),
+    NOW()
+FROM instance_daily_rollup
+    JOIN instance ON instance.id = instance_daily_rollup.instance_id
+    AND instance.deleted_at IS NULL
+    -- the last values reported before a day without any
+    LEFT JOIN LATERAL (
+        SELECT previous.last_users,
+            previous.last_active_month,
+            previous.last_local_posts
+        FROM instance_daily_rollup AS previous
+        WHERE previous.instance_id = instance_daily_rollup.instance_id
+            AND previous.day < instance_daily_rollup.day
+            AND previous.last_users IS NOT NULL
+        ORDER BY previous.day DESC
+        LIMIT 1
+    ) AS known ON instance_daily_rollup.last_users IS NULL
+WHERE instance_daily_rollup.day BETWEEN $1::date AND $2::date
+    AND NOT instance.domain = ANY($3::text [])
+    AND COALESCE(instance_daily_rollup.max_users, known.last_users) > $4::integer
+GROUP BY instance_daily_rollup.day ON CONFLICT (day) DO
+UPDATE
+SET instances = EXCLUDED.instances,
+    instances_up = EXCLUDED.instances_up,
+    crawls = EXCLUDED.crawls,
+    completed_crawls = EXCLUDED.completed_crawls,
+    total_users = EXCLUDED.total_users,
+    active_month = EXCLUDED.active_month,
+    local_posts = EXCLUDED.local_posts,
+    rolled_up_at = EXCLUDED.rolled_up_at
+`
+
+type RollupGlobalDaysParams struct {
+	FromDay              pgtype.Date
+	ToDay                pgtype.Date
+	BlockedDomains       []string
+	SmallServerThreshold int32
+}
+
+// Sums the daily aggregates of the instances, from from_day to to_day included.
+// Must run after RollupInstanceDays for the same days.
+// The instances which were down all day count with their last known values, as in GetGlobalStats,
+// so that an outage does not show up as a drop of the users.
+// Small servers and blocked domains are excluded.
+func (q *Queries) RollupGlobalDays(ctx context.Context, arg RollupGlobalDaysParams) error {
+	_, err := q.db.Exec(ctx, rollupGlobalDays,
+		arg.FromDay,
+		arg.ToDay,
+		arg.BlockedDomains,
+		arg.SmallServerThreshold,
+	)
+	return err
+}
+
+const rollupInstanceDays = `-- name: RollupInstanceDays :exec
+INSERT INTO instance_daily_rollup (
+        instance_id,
+        day,
+        crawls,
+        completed_crawls,
+        last_users,
+        min_users,
+        max_users,
+        last_active_month,
+        min_active_month,
+        max_active_month,
+        last_local_posts,
+        min_local_posts,
+        max_local_posts,
+        rolled_up_at
+    )
+SELECT crawl.instance_id,
+    (crawl.started_at AT TIME ZONE 'UTC')::date AS day,
+    COUNT(*),
+    COUNT(*) FILTER (
+        WHERE crawl.status = 'completed'
+    ),
+    (
+        array_agg(
+            crawl.total_users
+            ORDER BY crawl.started_at DESC
+        ) FILTER (
+            WHERE crawl.total_users IS NOT NULL
+        )
+    ) [1],
+    MIN(crawl.total_users),
+    MAX(crawl.total_users),
+    (
+        array_agg(
+            crawl.active_month
+            ORDER BY crawl.started_at DESC
+        ) FILTER (
+            WHERE crawl.active_month IS NOT NULL
+        )
+    ) [1],
+    MIN(crawl.active_month),
+    MAX(crawl.active_month),
+    (
+        array_agg(
+            crawl.local_posts
+            ORDER BY crawl.started_at DESC
+        ) FILTER (
+            WHERE crawl.local_posts IS NOT NULL
+        )
+    ) [1],
+    MIN(crawl.local_posts),
+    MAX(crawl.local_posts),
+    NOW()
+FROM crawl
+WHERE crawl.started_at >= ($1::date)::timestamp AT TIME ZONE 'UTC'
+    AND crawl.started_at < ($2::date + 1)::timestamp AT TIME ZONE 'UTC'
+GROUP BY crawl.instance_id,
+    day ON CONFLICT (instance_id, day) DO
+UPDATE
+SET crawls = EXCLUDED.crawls,
+    completed_crawls = EXCLUDED.completed_crawls,
+    last_users = EXCLUDED.last_users,
+    min_users = EXCLUDED.min_users,
+    max_users = EXCLUDED.max_users,
+    last_active_month = EXCLUDED.last_active_month,
+    min_active_month = EXCLUDED.min_active_month,
+    max_active_month = EXCLUDED.max_active_month,
+    last_local_posts = EXCLUDED.last_local_posts,
+    min_local_posts = EXCLUDED.min_local_posts,
+    max_local_posts = EXCLUDED.max_local_posts,
+    rolled_up_at = EXCLUDED.rolled_up_at
+`
+
+type RollupInstanceDaysParams struct {
+	FromDay pgtype.Date
+	ToDay   pgtype.Date
+}
+
+// Aggregates the crawls of each instance per day, from from_day to to_day included.
+// Rolling up a day again replaces its aggregates, eg: once the day is over.
+func (q *Queries) RollupInstanceDays(ctx context.Context, arg RollupInstanceDaysParams) error {
+	_, err := q.db.Exec(ctx, rollupInstanceDays, arg.FromDay, arg.ToDay)
 	return err
 }
 
 const updatePeeringRelationships = `-- name: UpdatePeeringRelationships :exec
-INSERT INTO peering_relationship (instance_id, peer_id)
+INSERT INTO peering_relationship (
+        instance_id,
+        peer_id,
+        first_seen_at,
+        last_seen_at,
+        last_crawl_id
+    )
 SELECT $1,
-    id
+    instance.id,
+    $2,
+    $2,
+    (
+        SELECT crawl.id
+        FROM crawl
+        WHERE crawl.instance_id = $1
+            AND crawl.started_at = $2
+    )
 FROM instance
-WHERE domain = ANY($2::varchar(255) []) ON CONFLICT DO NOTHING
+WHERE domain = ANY($3::varchar(255) []) ON CONFLICT (instance_id, peer_id) DO
+UPDATE
+SET first_seen_at = CASE
+        WHEN peering_relationship.removed_at IS NULL THEN peering_relationship.first_seen_at
+        ELSE EXCLUDED.first_seen_at
+    END,
+    last_seen_at = EXCLUDED.last_seen_at,
+    last_crawl_id = EXCLUDED.last_crawl_id,
+    removed_at = NULL
+WHERE peering_relationship.last_seen_at <= EXCLUDED.last_seen_at
+    AND (
+        peering_relationship.removed_at IS NULL
+        OR peering_relationship.removed_at <= EXCLUDED.last_seen_at
+    )
 `
 
 type UpdatePeeringRelationshipsParams struct {
 	InstanceID pgtype.UUID
+	SeenAt     pgtype.Timestamptz
 	Domains    []string
 }
 
+// The edges are identified by the start time of the crawl that listed them,
+// an older crawl being replayed does not overwrite what a newer one has seen,
+// nor restores an edge that a newer crawl has removed.
 func (q *Queries) UpdatePeeringRelationships(ctx context.Context, arg UpdatePeeringRelationshipsParams) error {
-	_, err := q.db.Exec(ctx, updatePeeringRelationships, arg.InstanceID, arg.Domains)
+	_, err := q.db.Exec(ctx, updatePeeringRelationships, arg.InstanceID, arg.SeenAt, arg.Domains)
+	return err
+}
+
+const upsertBlockedDomain = `-- name: UpsertBlockedDomain :exec
+INSERT INTO blocked_domain (pattern, reason, source, expires_at)
+VALUES ($1, $2, $3, $4) ON CONFLICT (pattern) DO
+UPDATE
+SET reason = EXCLUDED.reason,
+    source = EXCLUDED.source,
+    expires_at = EXCLUDED.expires_at,
+    updated_at = NOW()
+`
+
+type UpsertBlockedDomainParams struct {
+	Pattern   string
+	Reason    string
+	Source    string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertBlockedDomain(ctx context.Context, arg UpsertBlockedDomainParams) error {
+	_, err := q.db.Exec(ctx, upsertBlockedDomain,
+		arg.Pattern,
+		arg.Reason,
+		arg.Source,
+		arg.ExpiresAt,
+	)
 	return err
 }
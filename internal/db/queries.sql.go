@@ -1,30 +1,71 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.19.1
+//   sqlc v1.21.0
 // source: queries.sql
 
 package db
 
 import (
 	"context"
-	"net/netip"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countCrawlsForInstance = `-- name: CountCrawlsForInstance :one
+SELECT COUNT(*)
+FROM crawl
+WHERE instance_id = $1
+`
+
+func (q *Queries) CountCrawlsForInstance(ctx context.Context, instanceID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countCrawlsForInstance, instanceID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getCrawlRunByID = `-- name: GetCrawlRunByID :one
+SELECT id, started_at, finished_at, crawler_version, seeds, config, attempted, completed, failed, discovered, rejected, blocked, lost, error_counts, software_counts, blocked_groups
+FROM crawl_run
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCrawlRunByID(ctx context.Context, id pgtype.UUID) (CrawlRun, error) {
+	row := q.db.QueryRow(ctx, getCrawlRunByID, id)
+	var i CrawlRun
+	err := row.Scan(
+		&i.ID,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.CrawlerVersion,
+		&i.Seeds,
+		&i.Config,
+		&i.Attempted,
+		&i.Completed,
+		&i.Failed,
+		&i.Discovered,
+		&i.Rejected,
+		&i.Blocked,
+		&i.Lost,
+		&i.ErrorCounts,
+		&i.SoftwareCounts,
+		&i.BlockedGroups,
+	)
+	return i, err
+}
+
 const getCrawlerSeedDomains = `-- name: GetCrawlerSeedDomains :many
 SELECT domain
 FROM instance
-WHERE last_crawl_id IS NULL
-  AND deleted_at IS NULL
-UNION
-(
-  SELECT domain
-  FROM instance
-    JOIN crawl ON crawl.id = instance.last_crawl_id
-  WHERE deleted_at IS NULL
-  ORDER BY started_at ASC
-)
+WHERE deleted_at IS NULL
+  AND tombstoned_at IS NULL
+  AND (
+    next_crawl_at IS NULL
+    OR next_crawl_at <= NOW()
+  )
+ORDER BY last_crawl_id IS NOT NULL,
+  next_crawl_at ASC NULLS FIRST
 LIMIT $1 OFFSET $2
 `
 
@@ -33,7 +74,8 @@ type GetCrawlerSeedDomainsParams struct {
 	Offset int32
 }
 
-// Get all domains that have not been crawled yet. If there are none, return the domains with the oldest crawl.
+// Get the domains that are due for a crawl: the ones that have never been crawled first,
+// then by their scheduled crawl time. Tombstoned instances are never crawled again.
 func (q *Queries) GetCrawlerSeedDomains(ctx context.Context, arg GetCrawlerSeedDomainsParams) ([]string, error) {
 	rows, err := q.db.Query(ctx, getCrawlerSeedDomains, arg.Limit, arg.Offset)
 	if err != nil {
@@ -54,8 +96,79 @@ func (q *Queries) GetCrawlerSeedDomains(ctx context.Context, arg GetCrawlerSeedD
 	return items, nil
 }
 
+const getGlobalStats = `-- name: GetGlobalStats :one
+SELECT COUNT(*) AS instances,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'up'
+  ) AS up,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'down'
+  ) AS down,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'unhealthy'
+  ) AS unhealthy,
+  COALESCE(SUM(last_crawl.total_users), 0)::bigint AS total_users,
+  COALESCE(SUM(last_crawl.active_month), 0)::bigint AS active_month,
+  COALESCE(SUM(last_crawl.local_posts), 0)::bigint AS local_posts,
+  COUNT(*) FILTER (
+    WHERE last_crawl.open_registrations
+  ) AS open_registrations,
+  -- number of instances whose last crawl reported if registrations are open
+  COUNT(last_crawl.open_registrations) AS reported_registrations
+FROM instance
+  JOIN crawl AS last_crawl ON last_crawl.id = instance.last_crawl_id
+  CROSS JOIN LATERAL (
+    SELECT sized_crawl.total_users
+    FROM crawl AS sized_crawl
+    WHERE sized_crawl.instance_id = instance.id
+      AND sized_crawl.total_users IS NOT NULL
+    ORDER BY sized_crawl.started_at DESC
+    LIMIT 1
+  ) AS size
+WHERE instance.deleted_at IS NULL
+  AND NOT instance.domain = ANY($1::text [])
+  AND size.total_users > $2::integer
+`
+
+type GetGlobalStatsParams struct {
+	BlockedDomains       []string
+	SmallServerThreshold int32
+}
+
+type GetGlobalStatsRow struct {
+	Instances             int64
+	Up                    int64
+	Down                  int64
+	Unhealthy             int64
+	TotalUsers            int64
+	ActiveMonth           int64
+	LocalPosts            int64
+	OpenRegistrations     int64
+	ReportedRegistrations int64
+}
+
+// Totals over the last crawl of each instance, small servers and blocked domains excluded.
+// The size of an instance is the number of users reported by its last crawl that reported it,
+// so that the instances which are down are excluded if they were small.
+func (q *Queries) GetGlobalStats(ctx context.Context, arg GetGlobalStatsParams) (GetGlobalStatsRow, error) {
+	row := q.db.QueryRow(ctx, getGlobalStats, arg.BlockedDomains, arg.SmallServerThreshold)
+	var i GetGlobalStatsRow
+	err := row.Scan(
+		&i.Instances,
+		&i.Up,
+		&i.Down,
+		&i.Unhealthy,
+		&i.TotalUsers,
+		&i.ActiveMonth,
+		&i.LocalPosts,
+		&i.OpenRegistrations,
+		&i.ReportedRegistrations,
+	)
+	return i, err
+}
+
 const getInstanceByDomain = `-- name: GetInstanceByDomain :one
-SELECT id, domain, status, created_at, deleted_at, updated_at, software_name, last_crawl_id
+SELECT id, domain, status, created_at, deleted_at, updated_at, software_name, last_crawl_id, next_crawl_at, consecutive_failures, failing_since, tombstoned_at, title, description, uptime_24h, uptime_7d, uptime_30d, uptime_90d, uptime_updated_at, not_found_since, last_total_users, last_active_month, last_local_posts, last_number_of_peers, last_crawled_at
 FROM instance
 WHERE domain = $1
 LIMIT 1
@@ -73,12 +186,30 @@ func (q *Queries) GetInstanceByDomain(ctx context.Context, domain string) (Insta
 		&i.UpdatedAt,
 		&i.SoftwareName,
 		&i.LastCrawlID,
+		&i.NextCrawlAt,
+		&i.ConsecutiveFailures,
+		&i.FailingSince,
+		&i.TombstonedAt,
+		&i.Title,
+		&i.Description,
+		&i.Uptime24h,
+		&i.Uptime7d,
+		&i.Uptime30d,
+		&i.Uptime90d,
+		&i.UptimeUpdatedAt,
+		&i.NotFoundSince,
+		&i.LastTotalUsers,
+		&i.LastActiveMonth,
+		&i.LastLocalPosts,
+		&i.LastNumberOfPeers,
+		&i.LastCrawledAt,
 	)
 	return i, err
 }
 
 const getInstanceWithLastCrawlByID = `-- name: GetInstanceWithLastCrawlByID :one
-SELECT instance.id, domain, instance.status, created_at, deleted_at, updated_at, instance.software_name, last_crawl_id, crawl.id, instance_id, crawl.status, error_code, error_msg, started_at, finished_at, crawl.software_name, software_version, number_of_peers, open_registrations, total_users, active_half_year, active_month, local_posts, local_comments, raw_nodeinfo, addresses
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description
 FROM instance
   JOIN crawl ON crawl.id = instance.last_crawl_id
 WHERE instance.id = $1
@@ -87,89 +218,112 @@ LIMIT 1
 `
 
 type GetInstanceWithLastCrawlByIDRow struct {
-	ID                pgtype.UUID
-	Domain            string
-	Status            InstanceStatus
-	CreatedAt         pgtype.Timestamptz
-	DeletedAt         pgtype.Timestamptz
-	UpdatedAt         pgtype.Timestamptz
-	SoftwareName      pgtype.Text
-	LastCrawlID       pgtype.UUID
-	ID_2              pgtype.UUID
-	InstanceID        pgtype.UUID
-	Status_2          CrawlStatus
-	ErrorCode         NullCrawlErrorCode
-	ErrorMsg          pgtype.Text
-	StartedAt         pgtype.Timestamptz
-	FinishedAt        pgtype.Timestamptz
-	SoftwareName_2    pgtype.Text
-	SoftwareVersion   pgtype.Text
-	NumberOfPeers     pgtype.Int4
-	OpenRegistrations pgtype.Bool
-	TotalUsers        pgtype.Int4
-	ActiveHalfYear    pgtype.Int4
-	ActiveMonth       pgtype.Int4
-	LocalPosts        pgtype.Int4
-	LocalComments     pgtype.Int4
-	RawNodeinfo       []byte
-	Addresses         []netip.Addr
+	Instance Instance
+	Crawl    Crawl
 }
 
 func (q *Queries) GetInstanceWithLastCrawlByID(ctx context.Context, id pgtype.UUID) (GetInstanceWithLastCrawlByIDRow, error) {
 	row := q.db.QueryRow(ctx, getInstanceWithLastCrawlByID, id)
 	var i GetInstanceWithLastCrawlByIDRow
 	err := row.Scan(
-		&i.ID,
-		&i.Domain,
-		&i.Status,
-		&i.CreatedAt,
-		&i.DeletedAt,
-		&i.UpdatedAt,
-		&i.SoftwareName,
-		&i.LastCrawlID,
-		&i.ID_2,
-		&i.InstanceID,
-		&i.Status_2,
-		&i.ErrorCode,
-		&i.ErrorMsg,
-		&i.StartedAt,
-		&i.FinishedAt,
-		&i.SoftwareName_2,
-		&i.SoftwareVersion,
-		&i.NumberOfPeers,
-		&i.OpenRegistrations,
-		&i.TotalUsers,
-		&i.ActiveHalfYear,
-		&i.ActiveMonth,
-		&i.LocalPosts,
-		&i.LocalComments,
-		&i.RawNodeinfo,
-		&i.Addresses,
+		&i.Instance.ID,
+		&i.Instance.Domain,
+		&i.Instance.Status,
+		&i.Instance.CreatedAt,
+		&i.Instance.DeletedAt,
+		&i.Instance.UpdatedAt,
+		&i.Instance.SoftwareName,
+		&i.Instance.LastCrawlID,
+		&i.Instance.NextCrawlAt,
+		&i.Instance.ConsecutiveFailures,
+		&i.Instance.FailingSince,
+		&i.Instance.TombstonedAt,
+		&i.Instance.Title,
+		&i.Instance.Description,
+		&i.Instance.Uptime24h,
+		&i.Instance.Uptime7d,
+		&i.Instance.Uptime30d,
+		&i.Instance.Uptime90d,
+		&i.Instance.UptimeUpdatedAt,
+		&i.Instance.NotFoundSince,
+		&i.Instance.LastTotalUsers,
+		&i.Instance.LastActiveMonth,
+		&i.Instance.LastLocalPosts,
+		&i.Instance.LastNumberOfPeers,
+		&i.Instance.LastCrawledAt,
+		&i.Crawl.ID,
+		&i.Crawl.InstanceID,
+		&i.Crawl.CrawlRunID,
+		&i.Crawl.Status,
+		&i.Crawl.ErrorCode,
+		&i.Crawl.ErrorMsg,
+		&i.Crawl.StartedAt,
+		&i.Crawl.FinishedAt,
+		&i.Crawl.SoftwareName,
+		&i.Crawl.SoftwareVersion,
+		&i.Crawl.NumberOfPeers,
+		&i.Crawl.OpenRegistrations,
+		&i.Crawl.TotalUsers,
+		&i.Crawl.ActiveHalfYear,
+		&i.Crawl.ActiveMonth,
+		&i.Crawl.LocalPosts,
+		&i.Crawl.LocalComments,
+		&i.Crawl.RawNodeinfo,
+		&i.Crawl.Addresses,
+		&i.Crawl.MaxPeers,
+		&i.Crawl.PeersTruncated,
+		&i.Crawl.Languages,
+		&i.Crawl.Title,
+		&i.Crawl.Description,
 	)
 	return i, err
 }
 
-const getPeersIDsByInstanceID = `-- name: GetPeersIDsByInstanceID :many
-SELECT peer_id
-FROM peering_relationship
-  JOIN instance ON instance.id = peer_id
-  AND instance.deleted_at IS NULL
-WHERE instance_id = $1
+const getInstancesByDomains = `-- name: GetInstancesByDomains :many
+SELECT id, domain, status, created_at, deleted_at, updated_at, software_name, last_crawl_id, next_crawl_at, consecutive_failures, failing_since, tombstoned_at, title, description, uptime_24h, uptime_7d, uptime_30d, uptime_90d, uptime_updated_at, not_found_since, last_total_users, last_active_month, last_local_posts, last_number_of_peers, last_crawled_at
+FROM instance
+WHERE domain = ANY($1::varchar(255) [])
 `
 
-func (q *Queries) GetPeersIDsByInstanceID(ctx context.Context, instanceID pgtype.UUID) ([]pgtype.UUID, error) {
-	rows, err := q.db.Query(ctx, getPeersIDsByInstanceID, instanceID)
+func (q *Queries) GetInstancesByDomains(ctx context.Context, domains []string) ([]Instance, error) {
+	rows, err := q.db.Query(ctx, getInstancesByDomains, domains)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []pgtype.UUID
+	var items []Instance
 	for rows.Next() {
-		var peer_id pgtype.UUID
-		if err := rows.Scan(&peer_id); err != nil {
+		var i Instance
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Status,
+			&i.CreatedAt,
+			&i.DeletedAt,
+			&i.UpdatedAt,
+			&i.SoftwareName,
+			&i.LastCrawlID,
+			&i.NextCrawlAt,
+			&i.ConsecutiveFailures,
+			&i.FailingSince,
+			&i.TombstonedAt,
+			&i.Title,
+			&i.Description,
+			&i.Uptime24h,
+			&i.Uptime7d,
+			&i.Uptime30d,
+			&i.Uptime90d,
+			&i.UptimeUpdatedAt,
+			&i.NotFoundSince,
+			&i.LastTotalUsers,
+			&i.LastActiveMonth,
+			&i.LastLocalPosts,
+			&i.LastNumberOfPeers,
+			&i.LastCrawledAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, peer_id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -177,55 +331,216 @@ func (q *Queries) GetPeersIDsByInstanceID(ctx context.Context, instanceID pgtype
 	return items, nil
 }
 
-const listCrawlsPaginated = `-- name: ListCrawlsPaginated :many
-SELECT id, instance_id, status, error_code, error_msg, started_at, finished_at, software_name, software_version, number_of_peers, open_registrations, total_users, active_half_year, active_month, local_posts, local_comments, raw_nodeinfo, addresses,
+const getLastFinishedCrawlRunID = `-- name: GetLastFinishedCrawlRunID :one
+SELECT id
+FROM crawl_run
+WHERE finished_at IS NOT NULL
+ORDER BY started_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastFinishedCrawlRunID(ctx context.Context) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getLastFinishedCrawlRunID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listActiveBlockedDomains = `-- name: ListActiveBlockedDomains :many
+SELECT id, pattern, reason, source, created_at, updated_at, expires_at
+FROM blocked_domain
+WHERE expires_at IS NULL
+  OR expires_at > NOW()
+ORDER BY pattern
+`
+
+func (q *Queries) ListActiveBlockedDomains(ctx context.Context) ([]BlockedDomain, error) {
+	rows, err := q.db.Query(ctx, listActiveBlockedDomains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BlockedDomain
+	for rows.Next() {
+		var i BlockedDomain
+		if err := rows.Scan(
+			&i.ID,
+			&i.Pattern,
+			&i.Reason,
+			&i.Source,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCrawlRunsPaginated = `-- name: ListCrawlRunsPaginated :many
+SELECT id, started_at, finished_at, crawler_version, seeds, config, attempted, completed, failed, discovered, rejected, blocked, lost, error_counts, software_counts, blocked_groups,
   COUNT(*) OVER() AS total_count
+FROM crawl_run
+ORDER BY started_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListCrawlRunsPaginatedParams struct {
+	Limit  int32
+	Offset int32
+}
+
+type ListCrawlRunsPaginatedRow struct {
+	ID             pgtype.UUID
+	StartedAt      pgtype.Timestamptz
+	FinishedAt     pgtype.Timestamptz
+	CrawlerVersion string
+	Seeds          []string
+	Config         []byte
+	Attempted      int32
+	Completed      int32
+	Failed         int32
+	Discovered     int32
+	Rejected       int32
+	Blocked        int32
+	Lost           int32
+	ErrorCounts    []byte
+	SoftwareCounts []byte
+	BlockedGroups  []byte
+	TotalCount     int64
+}
+
+func (q *Queries) ListCrawlRunsPaginated(ctx context.Context, arg ListCrawlRunsPaginatedParams) ([]ListCrawlRunsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listCrawlRunsPaginated, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCrawlRunsPaginatedRow
+	for rows.Next() {
+		var i ListCrawlRunsPaginatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.CrawlerVersion,
+			&i.Seeds,
+			&i.Config,
+			&i.Attempted,
+			&i.Completed,
+			&i.Failed,
+			&i.Discovered,
+			&i.Rejected,
+			&i.Blocked,
+			&i.Lost,
+			&i.ErrorCounts,
+			&i.SoftwareCounts,
+			&i.BlockedGroups,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCrawlStatusesSince = `-- name: ListCrawlStatusesSince :many
+SELECT instance_id,
+  started_at,
+  status
+FROM crawl
+WHERE instance_id = ANY($1::uuid [])
+  AND started_at >= $2
+ORDER BY instance_id,
+  started_at
+`
+
+type ListCrawlStatusesSinceParams struct {
+	InstanceIds []pgtype.UUID
+	Since       pgtype.Timestamptz
+}
+
+type ListCrawlStatusesSinceRow struct {
+	InstanceID pgtype.UUID
+	StartedAt  pgtype.Timestamptz
+	Status     CrawlStatus
+}
+
+// The crawls of the instances started since a date, sorted by instance then by start time.
+func (q *Queries) ListCrawlStatusesSince(ctx context.Context, arg ListCrawlStatusesSinceParams) ([]ListCrawlStatusesSinceRow, error) {
+	rows, err := q.db.Query(ctx, listCrawlStatusesSince, arg.InstanceIds, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCrawlStatusesSinceRow
+	for rows.Next() {
+		var i ListCrawlStatusesSinceRow
+		if err := rows.Scan(&i.InstanceID, &i.StartedAt, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCrawlsPaginated = `-- name: ListCrawlsPaginated :many
+SELECT id, instance_id, crawl_run_id, status, error_code, error_msg, started_at, finished_at, software_name, software_version, number_of_peers, open_registrations, total_users, active_half_year, active_month, local_posts, local_comments, raw_nodeinfo, addresses, max_peers, peers_truncated, languages, title, description
 FROM crawl
 WHERE instance_id = $1
-ORDER BY started_at DESC
-LIMIT $2 OFFSET $3
+  AND (
+    $2::timestamptz IS NULL
+    OR (started_at, id) < (
+      $2::timestamptz,
+      $3::uuid
+    )
+  )
+ORDER BY started_at DESC,
+  id DESC
+LIMIT $5 OFFSET $4
 `
 
 type ListCrawlsPaginatedParams struct {
-	InstanceID pgtype.UUID
-	Limit      int32
-	Offset     int32
+	InstanceID     pgtype.UUID
+	AfterStartedAt pgtype.Timestamptz
+	AfterID        pgtype.UUID
+	Offset         int32
+	Limit          int32
 }
 
-type ListCrawlsPaginatedRow struct {
-	ID                pgtype.UUID
-	InstanceID        pgtype.UUID
-	Status            CrawlStatus
-	ErrorCode         NullCrawlErrorCode
-	ErrorMsg          pgtype.Text
-	StartedAt         pgtype.Timestamptz
-	FinishedAt        pgtype.Timestamptz
-	SoftwareName      pgtype.Text
-	SoftwareVersion   pgtype.Text
-	NumberOfPeers     pgtype.Int4
-	OpenRegistrations pgtype.Bool
-	TotalUsers        pgtype.Int4
-	ActiveHalfYear    pgtype.Int4
-	ActiveMonth       pgtype.Int4
-	LocalPosts        pgtype.Int4
-	LocalComments     pgtype.Int4
-	RawNodeinfo       []byte
-	Addresses         []netip.Addr
-	TotalCount        int64
-}
-
-func (q *Queries) ListCrawlsPaginated(ctx context.Context, arg ListCrawlsPaginatedParams) ([]ListCrawlsPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listCrawlsPaginated, arg.InstanceID, arg.Limit, arg.Offset)
+// Most recent first, the page starts after the crawl whose start time and id are after_*, if set.
+// The page number is deprecated, offset must be zero with a cursor.
+func (q *Queries) ListCrawlsPaginated(ctx context.Context, arg ListCrawlsPaginatedParams) ([]Crawl, error) {
+	rows, err := q.db.Query(ctx, listCrawlsPaginated,
+		arg.InstanceID,
+		arg.AfterStartedAt,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListCrawlsPaginatedRow
+	var items []Crawl
 	for rows.Next() {
-		var i ListCrawlsPaginatedRow
+		var i Crawl
 		if err := rows.Scan(
 			&i.ID,
 			&i.InstanceID,
+			&i.CrawlRunID,
 			&i.Status,
 			&i.ErrorCode,
 			&i.ErrorMsg,
@@ -242,7 +557,11 @@ func (q *Queries) ListCrawlsPaginated(ctx context.Context, arg ListCrawlsPaginat
 			&i.LocalComments,
 			&i.RawNodeinfo,
 			&i.Addresses,
-			&i.TotalCount,
+			&i.MaxPeers,
+			&i.PeersTruncated,
+			&i.Languages,
+			&i.Title,
+			&i.Description,
 		); err != nil {
 			return nil, err
 		}
@@ -279,92 +598,869 @@ func (q *Queries) ListErrorCodeDescriptions(ctx context.Context) ([]CrawlError,
 	return items, nil
 }
 
-const listInstancesPaginated = `-- name: ListInstancesPaginated :many
-SELECT instance.id, domain, instance.status, created_at, deleted_at, updated_at, instance.software_name, last_crawl_id, crawl.id, instance_id, crawl.status, error_code, error_msg, started_at, finished_at, crawl.software_name, software_version, number_of_peers, open_registrations, total_users, active_half_year, active_month, local_posts, local_comments, raw_nodeinfo, addresses,
+const listGlobalDailyRollups = `-- name: ListGlobalDailyRollups :many
+SELECT day, instances, instances_up, crawls, completed_crawls, total_users, active_month, local_posts, rolled_up_at
+FROM global_daily_rollup
+WHERE day BETWEEN $1::date AND $2::date
+ORDER BY day
+`
+
+type ListGlobalDailyRollupsParams struct {
+	FromDay pgtype.Date
+	ToDay   pgtype.Date
+}
+
+func (q *Queries) ListGlobalDailyRollups(ctx context.Context, arg ListGlobalDailyRollupsParams) ([]GlobalDailyRollup, error) {
+	rows, err := q.db.Query(ctx, listGlobalDailyRollups, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GlobalDailyRollup
+	for rows.Next() {
+		var i GlobalDailyRollup
+		if err := rows.Scan(
+			&i.Day,
+			&i.Instances,
+			&i.InstancesUp,
+			&i.Crawls,
+			&i.CompletedCrawls,
+			&i.TotalUsers,
+			&i.ActiveMonth,
+			&i.LocalPosts,
+			&i.RolledUpAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncidentsForInstancePaginated = `-- name: ListIncidentsForInstancePaginated :many
+SELECT id, instance_id, first_crawl_id, started_at, error_code, failed_crawls, confirmed_at, recovered_crawls, recovering_since, closed_at,
   COUNT(*) OVER() AS total_count
-FROM instance
-  JOIN crawl ON crawl.id = instance.last_crawl_id
-WHERE deleted_at IS NULL
-  AND total_users > $3
-ORDER BY total_users DESC
-LIMIT $1 OFFSET $2
+FROM incident
+WHERE instance_id = $1
+  AND confirmed_at IS NOT NULL
+ORDER BY started_at DESC
+LIMIT $3 OFFSET $2
 `
 
-type ListInstancesPaginatedParams struct {
-	Limit      int32
+type ListIncidentsForInstancePaginatedParams struct {
+	InstanceID pgtype.UUID
 	Offset     int32
-	TotalUsers pgtype.Int4
-}
-
-type ListInstancesPaginatedRow struct {
-	ID                pgtype.UUID
-	Domain            string
-	Status            InstanceStatus
-	CreatedAt         pgtype.Timestamptz
-	DeletedAt         pgtype.Timestamptz
-	UpdatedAt         pgtype.Timestamptz
-	SoftwareName      pgtype.Text
-	LastCrawlID       pgtype.UUID
-	ID_2              pgtype.UUID
-	InstanceID        pgtype.UUID
-	Status_2          CrawlStatus
-	ErrorCode         NullCrawlErrorCode
-	ErrorMsg          pgtype.Text
-	StartedAt         pgtype.Timestamptz
-	FinishedAt        pgtype.Timestamptz
-	SoftwareName_2    pgtype.Text
-	SoftwareVersion   pgtype.Text
-	NumberOfPeers     pgtype.Int4
-	OpenRegistrations pgtype.Bool
-	TotalUsers        pgtype.Int4
-	ActiveHalfYear    pgtype.Int4
-	ActiveMonth       pgtype.Int4
-	LocalPosts        pgtype.Int4
-	LocalComments     pgtype.Int4
-	RawNodeinfo       []byte
-	Addresses         []netip.Addr
-	TotalCount        int64
-}
-
-// TODO: these types of paginated queries are not efficient
-//
-//	we should use a cursor instead or a CTE
-func (q *Queries) ListInstancesPaginated(ctx context.Context, arg ListInstancesPaginatedParams) ([]ListInstancesPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listInstancesPaginated, arg.Limit, arg.Offset, arg.TotalUsers)
+	Limit      int32
+}
+
+type ListIncidentsForInstancePaginatedRow struct {
+	ID              pgtype.UUID
+	InstanceID      pgtype.UUID
+	FirstCrawlID    pgtype.UUID
+	StartedAt       pgtype.Timestamptz
+	ErrorCode       NullCrawlErrorCode
+	FailedCrawls    int32
+	ConfirmedAt     pgtype.Timestamptz
+	RecoveredCrawls int32
+	RecoveringSince pgtype.Timestamptz
+	ClosedAt        pgtype.Timestamptz
+	TotalCount      int64
+}
+
+// The confirmed incidents of an instance, most recent first.
+func (q *Queries) ListIncidentsForInstancePaginated(ctx context.Context, arg ListIncidentsForInstancePaginatedParams) ([]ListIncidentsForInstancePaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listIncidentsForInstancePaginated, arg.InstanceID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIncidentsForInstancePaginatedRow
+	for rows.Next() {
+		var i ListIncidentsForInstancePaginatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.FirstCrawlID,
+			&i.StartedAt,
+			&i.ErrorCode,
+			&i.FailedCrawls,
+			&i.ConfirmedAt,
+			&i.RecoveredCrawls,
+			&i.RecoveringSince,
+			&i.ClosedAt,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncidentsPaginated = `-- name: ListIncidentsPaginated :many
+SELECT incident.id, incident.instance_id, incident.first_crawl_id, incident.started_at, incident.error_code, incident.failed_crawls, incident.confirmed_at, incident.recovered_crawls, incident.recovering_since, incident.closed_at,
+  instance.domain,
+  COUNT(*) OVER() AS total_count
+FROM incident
+  JOIN instance ON instance.id = incident.instance_id
+  CROSS JOIN LATERAL (
+    SELECT sized_crawl.total_users
+    FROM crawl AS sized_crawl
+    WHERE sized_crawl.instance_id = instance.id
+      AND sized_crawl.total_users IS NOT NULL
+    ORDER BY sized_crawl.started_at DESC
+    LIMIT 1
+  ) AS size
+WHERE incident.confirmed_at IS NOT NULL
+  AND instance.deleted_at IS NULL
+  AND NOT instance.domain = ANY($1::text [])
+  AND size.total_users > $2::integer
+  AND (
+    $3::boolean IS NULL
+    OR (incident.closed_at IS NULL) = $3::boolean
+  )
+ORDER BY incident.started_at DESC,
+  incident.id
+LIMIT $5 OFFSET $4
+`
+
+type ListIncidentsPaginatedParams struct {
+	BlockedDomains       []string
+	SmallServerThreshold int32
+	Active               pgtype.Bool
+	Offset               int32
+	Limit                int32
+}
+
+type ListIncidentsPaginatedRow struct {
+	ID              pgtype.UUID
+	InstanceID      pgtype.UUID
+	FirstCrawlID    pgtype.UUID
+	StartedAt       pgtype.Timestamptz
+	ErrorCode       NullCrawlErrorCode
+	FailedCrawls    int32
+	ConfirmedAt     pgtype.Timestamptz
+	RecoveredCrawls int32
+	RecoveringSince pgtype.Timestamptz
+	ClosedAt        pgtype.Timestamptz
+	Domain          string
+	TotalCount      int64
+}
+
+// The confirmed incidents, most recent first, small servers excluded.
+// All of them if active is null, otherwise only the open or the closed ones.
+// The size of an instance is the number of users reported by its last crawl that reported it, as in GetGlobalStats.
+// The blocked domains are excluded before paginating, so that the pages are full and total_count is right.
+func (q *Queries) ListIncidentsPaginated(ctx context.Context, arg ListIncidentsPaginatedParams) ([]ListIncidentsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listIncidentsPaginated,
+		arg.BlockedDomains,
+		arg.SmallServerThreshold,
+		arg.Active,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListInstancesPaginatedRow
+	var items []ListIncidentsPaginatedRow
 	for rows.Next() {
-		var i ListInstancesPaginatedRow
+		var i ListIncidentsPaginatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.FirstCrawlID,
+			&i.StartedAt,
+			&i.ErrorCode,
+			&i.FailedCrawls,
+			&i.ConfirmedAt,
+			&i.RecoveredCrawls,
+			&i.RecoveringSince,
+			&i.ClosedAt,
+			&i.Domain,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncomingPeersPaginated = `-- name: ListIncomingPeersPaginated :many
+SELECT instance.id,
+  instance.domain,
+  instance.status,
+  instance.software_name,
+  crawl.software_version,
+  crawl.total_users,
+  crawl.started_at AS last_crawled_at,
+  peering_relationship.first_seen_at,
+  peering_relationship.last_seen_at
+FROM peering_relationship
+  JOIN instance ON instance.id = peering_relationship.instance_id
+  AND instance.deleted_at IS NULL
+  LEFT JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE peering_relationship.peer_id = $1
+  AND peering_relationship.removed_at IS NULL
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::instance_status IS NULL
+    OR instance.status = $3::instance_status
+  )
+  AND (
+    $4::text IS NULL
+    OR instance.software_name = $4::text
+  )
+  AND (
+    $5::text IS NULL
+    OR instance.domain > $5::text
+  )
+ORDER BY instance.domain
+LIMIT $6
+`
+
+type ListIncomingPeersPaginatedParams struct {
+	InstanceID     pgtype.UUID
+	BlockedDomains []string
+	Status         NullInstanceStatus
+	Software       pgtype.Text
+	After          pgtype.Text
+	Limit          int32
+}
+
+type ListIncomingPeersPaginatedRow struct {
+	ID              pgtype.UUID
+	Domain          string
+	Status          InstanceStatus
+	SoftwareName    pgtype.Text
+	SoftwareVersion pgtype.Text
+	TotalUsers      pgtype.Int4
+	LastCrawledAt   pgtype.Timestamptz
+	FirstSeenAt     pgtype.Timestamptz
+	LastSeenAt      pgtype.Timestamptz
+}
+
+// The instances listing an instance as peer, by domain.
+func (q *Queries) ListIncomingPeersPaginated(ctx context.Context, arg ListIncomingPeersPaginatedParams) ([]ListIncomingPeersPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listIncomingPeersPaginated,
+		arg.InstanceID,
+		arg.BlockedDomains,
+		arg.Status,
+		arg.Software,
+		arg.After,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIncomingPeersPaginatedRow
+	for rows.Next() {
+		var i ListIncomingPeersPaginatedRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Domain,
 			&i.Status,
-			&i.CreatedAt,
-			&i.DeletedAt,
-			&i.UpdatedAt,
 			&i.SoftwareName,
-			&i.LastCrawlID,
-			&i.ID_2,
+			&i.SoftwareVersion,
+			&i.TotalUsers,
+			&i.LastCrawledAt,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstanceDailyRollups = `-- name: ListInstanceDailyRollups :many
+SELECT instance_id, day, crawls, completed_crawls, last_users, min_users, max_users, last_active_month, min_active_month, max_active_month, last_local_posts, min_local_posts, max_local_posts, rolled_up_at
+FROM instance_daily_rollup
+WHERE instance_id = $1
+  AND day BETWEEN $2::date AND $3::date
+ORDER BY day
+`
+
+type ListInstanceDailyRollupsParams struct {
+	InstanceID pgtype.UUID
+	FromDay    pgtype.Date
+	ToDay      pgtype.Date
+}
+
+func (q *Queries) ListInstanceDailyRollups(ctx context.Context, arg ListInstanceDailyRollupsParams) ([]InstanceDailyRollup, error) {
+	rows, err := q.db.Query(ctx, listInstanceDailyRollups, arg.InstanceID, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InstanceDailyRollup
+	for rows.Next() {
+		var i InstanceDailyRollup
+		if err := rows.Scan(
 			&i.InstanceID,
-			&i.Status_2,
-			&i.ErrorCode,
-			&i.ErrorMsg,
+			&i.Day,
+			&i.Crawls,
+			&i.CompletedCrawls,
+			&i.LastUsers,
+			&i.MinUsers,
+			&i.MaxUsers,
+			&i.LastActiveMonth,
+			&i.MinActiveMonth,
+			&i.MaxActiveMonth,
+			&i.LastLocalPosts,
+			&i.MinLocalPosts,
+			&i.MaxLocalPosts,
+			&i.RolledUpAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstanceDomains = `-- name: ListInstanceDomains :many
+SELECT domain
+FROM instance
+WHERE deleted_at IS NULL
+`
+
+func (q *Queries) ListInstanceDomains(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listInstanceDomains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, err
+		}
+		items = append(items, domain)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInstanceIDsPaginated = `-- name: ListInstanceIDsPaginated :many
+SELECT id
+FROM instance
+WHERE deleted_at IS NULL
+  AND id > $1::uuid
+ORDER BY id
+LIMIT $2
+`
+
+type ListInstanceIDsPaginatedParams struct {
+	AfterID pgtype.UUID
+	Limit   int32
+}
+
+// The instances are sorted by id, the page starts after after_id.
+func (q *Queries) ListInstanceIDsPaginated(ctx context.Context, arg ListInstanceIDsPaginatedParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listInstanceIDsPaginated, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotDueDomains = `-- name: ListNotDueDomains :many
+SELECT domain
+FROM instance
+WHERE deleted_at IS NULL
+  AND (
+    tombstoned_at IS NOT NULL
+    OR next_crawl_at > NOW()
+  )
+`
+
+// Get the domains that are not due for a crawl, the opposite of GetCrawlerSeedDomains.
+// A crawl does not queue them when they are discovered as peers.
+func (q *Queries) ListNotDueDomains(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listNotDueDomains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err != nil {
+			return nil, err
+		}
+		items = append(items, domain)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenIncidentsByInstanceIDs = `-- name: ListOpenIncidentsByInstanceIDs :many
+SELECT id, instance_id, first_crawl_id, started_at, error_code, failed_crawls, confirmed_at, recovered_crawls, recovering_since, closed_at
+FROM incident
+WHERE instance_id = ANY($1::uuid [])
+  AND closed_at IS NULL
+`
+
+func (q *Queries) ListOpenIncidentsByInstanceIDs(ctx context.Context, instanceIds []pgtype.UUID) ([]Incident, error) {
+	rows, err := q.db.Query(ctx, listOpenIncidentsByInstanceIDs, instanceIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Incident
+	for rows.Next() {
+		var i Incident
+		if err := rows.Scan(
+			&i.ID,
+			&i.InstanceID,
+			&i.FirstCrawlID,
 			&i.StartedAt,
-			&i.FinishedAt,
-			&i.SoftwareName_2,
+			&i.ErrorCode,
+			&i.FailedCrawls,
+			&i.ConfirmedAt,
+			&i.RecoveredCrawls,
+			&i.RecoveringSince,
+			&i.ClosedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutgoingPeersPaginated = `-- name: ListOutgoingPeersPaginated :many
+SELECT instance.id,
+  instance.domain,
+  instance.status,
+  instance.software_name,
+  crawl.software_version,
+  crawl.total_users,
+  crawl.started_at AS last_crawled_at,
+  peering_relationship.first_seen_at,
+  peering_relationship.last_seen_at
+FROM peering_relationship
+  JOIN instance ON instance.id = peering_relationship.peer_id
+  AND instance.deleted_at IS NULL
+  LEFT JOIN crawl ON crawl.id = instance.last_crawl_id
+WHERE peering_relationship.instance_id = $1
+  AND peering_relationship.removed_at IS NULL
+  AND NOT instance.domain = ANY($2::text [])
+  AND (
+    $3::instance_status IS NULL
+    OR instance.status = $3::instance_status
+  )
+  AND (
+    $4::text IS NULL
+    OR instance.software_name = $4::text
+  )
+  AND (
+    $5::text IS NULL
+    OR instance.domain > $5::text
+  )
+ORDER BY instance.domain
+LIMIT $6
+`
+
+type ListOutgoingPeersPaginatedParams struct {
+	InstanceID     pgtype.UUID
+	BlockedDomains []string
+	Status         NullInstanceStatus
+	Software       pgtype.Text
+	After          pgtype.Text
+	Limit          int32
+}
+
+type ListOutgoingPeersPaginatedRow struct {
+	ID              pgtype.UUID
+	Domain          string
+	Status          InstanceStatus
+	SoftwareName    pgtype.Text
+	SoftwareVersion pgtype.Text
+	TotalUsers      pgtype.Int4
+	LastCrawledAt   pgtype.Timestamptz
+	FirstSeenAt     pgtype.Timestamptz
+	LastSeenAt      pgtype.Timestamptz
+}
+
+// The peers listed by an instance, by domain.
+// ListIncomingPeersPaginated must return the same columns.
+func (q *Queries) ListOutgoingPeersPaginated(ctx context.Context, arg ListOutgoingPeersPaginatedParams) ([]ListOutgoingPeersPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listOutgoingPeersPaginated,
+		arg.InstanceID,
+		arg.BlockedDomains,
+		arg.Status,
+		arg.Software,
+		arg.After,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOutgoingPeersPaginatedRow
+	for rows.Next() {
+		var i ListOutgoingPeersPaginatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Domain,
+			&i.Status,
+			&i.SoftwareName,
 			&i.SoftwareVersion,
-			&i.NumberOfPeers,
-			&i.OpenRegistrations,
 			&i.TotalUsers,
-			&i.ActiveHalfYear,
+			&i.LastCrawledAt,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPeeringChangesPaginated = `-- name: ListPeeringChangesPaginated :many
+WITH changes AS (
+  SELECT peer_id,
+    'gained'::text AS change,
+    first_seen_at AS changed_at
+  FROM peering_relationship
+  WHERE peering_relationship.instance_id = $5
+    AND peering_relationship.first_seen_at IS NOT NULL
+    AND peering_relationship.first_seen_at >= $6
+  UNION ALL
+  SELECT peer_id,
+    'lost'::text AS change,
+    removed_at AS changed_at
+  FROM peering_relationship
+  WHERE peering_relationship.instance_id = $5
+    AND peering_relationship.removed_at >= $6
+)
+SELECT changes.peer_id,
+  instance.domain,
+  changes.change,
+  changes.changed_at::timestamptz AS changed_at,
+  COUNT(*) OVER() AS total_count
+FROM changes
+  JOIN instance ON instance.id = changes.peer_id
+  AND instance.deleted_at IS NULL
+WHERE NOT instance.domain = ANY($1::text [])
+  AND (
+    $2::text IS NULL
+    OR changes.change = $2::text
+  )
+ORDER BY changes.changed_at DESC,
+  instance.domain
+LIMIT $4 OFFSET $3
+`
+
+type ListPeeringChangesPaginatedParams struct {
+	BlockedDomains []string
+	Change         pgtype.Text
+	Offset         int32
+	Limit          int32
+	InstanceID     pgtype.UUID
+	Since          pgtype.Timestamptz
+}
+
+type ListPeeringChangesPaginatedRow struct {
+	PeerID     pgtype.UUID
+	Domain     string
+	Change     string
+	ChangedAt  pgtype.Timestamptz
+	TotalCount int64
+}
+
+// The peers gained and lost by an instance since a date, most recent first.
+// The edges recorded before the peering relationships were tracked have no first_seen_at,
+// they are not reported as gained.
+func (q *Queries) ListPeeringChangesPaginated(ctx context.Context, arg ListPeeringChangesPaginatedParams) ([]ListPeeringChangesPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listPeeringChangesPaginated,
+		arg.BlockedDomains,
+		arg.Change,
+		arg.Offset,
+		arg.Limit,
+		arg.InstanceID,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPeeringChangesPaginatedRow
+	for rows.Next() {
+		var i ListPeeringChangesPaginatedRow
+		if err := rows.Scan(
+			&i.PeerID,
+			&i.Domain,
+			&i.Change,
+			&i.ChangedAt,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSoftwareVersionStats = `-- name: ListSoftwareVersionStats :many
+SELECT known.software_name::text AS software_name,
+  known.software_version,
+  COUNT(*) AS instances,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'up'
+  ) AS up,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'down'
+  ) AS down,
+  COUNT(*) FILTER (
+    WHERE instance.status = 'unhealthy'
+  ) AS unhealthy,
+  COALESCE(SUM(last_crawl.total_users), 0)::bigint AS total_users,
+  COALESCE(SUM(last_crawl.active_month), 0)::bigint AS active_month
+FROM instance
+  JOIN crawl AS last_crawl ON last_crawl.id = instance.last_crawl_id
+  CROSS JOIN LATERAL (
+    SELECT known_crawl.software_name,
+      known_crawl.software_version,
+      known_crawl.total_users
+    FROM crawl AS known_crawl
+    WHERE known_crawl.instance_id = instance.id
+      AND known_crawl.total_users IS NOT NULL
+    ORDER BY known_crawl.started_at DESC
+    LIMIT 1
+  ) AS known
+WHERE instance.deleted_at IS NULL
+  AND NOT instance.domain = ANY($1::text [])
+  AND known.total_users > $2::integer
+  AND known.software_name IS NOT NULL
+  AND known.software_name != ''
+GROUP BY known.software_name,
+  known.software_version
+`
+
+type ListSoftwareVersionStatsParams struct {
+	BlockedDomains       []string
+	SmallServerThreshold int32
+}
+
+type ListSoftwareVersionStatsRow struct {
+	SoftwareName    string
+	SoftwareVersion pgtype.Text
+	Instances       int64
+	Up              int64
+	Down            int64
+	Unhealthy       int64
+	TotalUsers      int64
+	ActiveMonth     int64
+}
+
+// Totals per software and version over the last crawl of each instance, small servers and blocked domains excluded.
+// The software, the version and the size of an instance are from its last crawl that reported its users,
+// as the crawls of the instances which are down report none.
+func (q *Queries) ListSoftwareVersionStats(ctx context.Context, arg ListSoftwareVersionStatsParams) ([]ListSoftwareVersionStatsRow, error) {
+	rows, err := q.db.Query(ctx, listSoftwareVersionStats, arg.BlockedDomains, arg.SmallServerThreshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSoftwareVersionStatsRow
+	for rows.Next() {
+		var i ListSoftwareVersionStatsRow
+		if err := rows.Scan(
+			&i.SoftwareName,
+			&i.SoftwareVersion,
+			&i.Instances,
+			&i.Up,
+			&i.Down,
+			&i.Unhealthy,
+			&i.TotalUsers,
 			&i.ActiveMonth,
-			&i.LocalPosts,
-			&i.LocalComments,
-			&i.RawNodeinfo,
-			&i.Addresses,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchInstances = `-- name: SearchInstances :many
+WITH search AS (
+  SELECT websearch_to_tsquery('simple', $1::text) AS tsquery
+)
+SELECT instance.id, instance.domain, instance.status, instance.created_at, instance.deleted_at, instance.updated_at, instance.software_name, instance.last_crawl_id, instance.next_crawl_at, instance.consecutive_failures, instance.failing_since, instance.tombstoned_at, instance.title, instance.description, instance.uptime_24h, instance.uptime_7d, instance.uptime_30d, instance.uptime_90d, instance.uptime_updated_at, instance.not_found_since, instance.last_total_users, instance.last_active_month, instance.last_local_posts, instance.last_number_of_peers, instance.last_crawled_at,
+  crawl.id, crawl.instance_id, crawl.crawl_run_id, crawl.status, crawl.error_code, crawl.error_msg, crawl.started_at, crawl.finished_at, crawl.software_name, crawl.software_version, crawl.number_of_peers, crawl.open_registrations, crawl.total_users, crawl.active_half_year, crawl.active_month, crawl.local_posts, crawl.local_comments, crawl.raw_nodeinfo, crawl.addresses, crawl.max_peers, crawl.peers_truncated, crawl.languages, crawl.title, crawl.description,
+  (
+    ts_rank_cd(
+      setweight(to_tsvector('simple', replace(instance.domain, '.', ' ')), 'A') || setweight(to_tsvector('simple', coalesce(instance.title, '')), 'A') || setweight(to_tsvector('simple', coalesce(instance.software_name, '')), 'B') || setweight(to_tsvector('simple', coalesce(instance.description, '')), 'C'),
+      search.tsquery
+    ) + greatest(
+      word_similarity($1::text, instance.domain),
+      word_similarity($1::text, coalesce(instance.title, ''))
+    )
+  )::float8 AS rank,
+  ts_headline(
+    'simple',
+    coalesce(instance.description, ''),
+    search.tsquery,
+    $2::text
+  )::text AS snippet,
+  COUNT(*) OVER() AS total_count
+FROM instance
+  JOIN crawl ON crawl.id = instance.last_crawl_id
+  CROSS JOIN search
+WHERE instance.deleted_at IS NULL
+  AND crawl.total_users > $3::integer
+  AND NOT instance.domain = ANY($4::text [])
+  AND (
+    setweight(to_tsvector('simple', replace(instance.domain, '.', ' ')), 'A') || setweight(to_tsvector('simple', coalesce(instance.title, '')), 'A') || setweight(to_tsvector('simple', coalesce(instance.software_name, '')), 'B') || setweight(to_tsvector('simple', coalesce(instance.description, '')), 'C') @@ search.tsquery
+    OR $1::text <% instance.domain
+    OR $1::text <% instance.title
+  )
+ORDER BY rank DESC,
+  crawl.total_users DESC,
+  instance.id
+LIMIT $6 OFFSET $5
+`
+
+type SearchInstancesParams struct {
+	Query                string
+	HeadlineOptions      string
+	SmallServerThreshold int32
+	BlockedDomains       []string
+	Offset               int32
+	Limit                int32
+}
+
+type SearchInstancesRow struct {
+	Instance   Instance
+	Crawl      Crawl
+	Rank       float64
+	Snippet    string
+	TotalCount int64
+}
+
+// The instances matching the query, by full-text search or with typos in their domain or title.
+// The expression of the search vector must be the same as in the instance_search_idx index.
+// Only the small servers are excluded, the filters of the instances listing do not apply.
+// The blocked domains are excluded before paginating, so that the pages are full and total_count is right.
+func (q *Queries) SearchInstances(ctx context.Context, arg SearchInstancesParams) ([]SearchInstancesRow, error) {
+	rows, err := q.db.Query(ctx, searchInstances,
+		arg.Query,
+		arg.HeadlineOptions,
+		arg.SmallServerThreshold,
+		arg.BlockedDomains,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchInstancesRow
+	for rows.Next() {
+		var i SearchInstancesRow
+		if err := rows.Scan(
+			&i.Instance.ID,
+			&i.Instance.Domain,
+			&i.Instance.Status,
+			&i.Instance.CreatedAt,
+			&i.Instance.DeletedAt,
+			&i.Instance.UpdatedAt,
+			&i.Instance.SoftwareName,
+			&i.Instance.LastCrawlID,
+			&i.Instance.NextCrawlAt,
+			&i.Instance.ConsecutiveFailures,
+			&i.Instance.FailingSince,
+			&i.Instance.TombstonedAt,
+			&i.Instance.Title,
+			&i.Instance.Description,
+			&i.Instance.Uptime24h,
+			&i.Instance.Uptime7d,
+			&i.Instance.Uptime30d,
+			&i.Instance.Uptime90d,
+			&i.Instance.UptimeUpdatedAt,
+			&i.Instance.NotFoundSince,
+			&i.Instance.LastTotalUsers,
+			&i.Instance.LastActiveMonth,
+			&i.Instance.LastLocalPosts,
+			&i.Instance.LastNumberOfPeers,
+			&i.Instance.LastCrawledAt,
+			&i.Crawl.ID,
+			&i.Crawl.InstanceID,
+			&i.Crawl.CrawlRunID,
+			&i.Crawl.Status,
+			&i.Crawl.ErrorCode,
+			&i.Crawl.ErrorMsg,
+			&i.Crawl.StartedAt,
+			&i.Crawl.FinishedAt,
+			&i.Crawl.SoftwareName,
+			&i.Crawl.SoftwareVersion,
+			&i.Crawl.NumberOfPeers,
+			&i.Crawl.OpenRegistrations,
+			&i.Crawl.TotalUsers,
+			&i.Crawl.ActiveHalfYear,
+			&i.Crawl.ActiveMonth,
+			&i.Crawl.LocalPosts,
+			&i.Crawl.LocalComments,
+			&i.Crawl.RawNodeinfo,
+			&i.Crawl.Addresses,
+			&i.Crawl.MaxPeers,
+			&i.Crawl.PeersTruncated,
+			&i.Crawl.Languages,
+			&i.Crawl.Title,
+			&i.Crawl.Description,
+			&i.Rank,
+			&i.Snippet,
 			&i.TotalCount,
 		); err != nil {
 			return nil, err
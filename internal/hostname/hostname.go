@@ -0,0 +1,184 @@
+// Package hostname normalizes and validates the domains of Fediverse instances.
+// Peer lists are user-controlled, so every domain goes through Normalize before reaching the database or the crawl frontier.
+package hostname
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	// maxLabelLength is the maximum length of a single label (RFC 1035).
+	maxLabelLength = 63
+	// maxLength is the maximum length of a domain in its textual form (RFC 1035).
+	maxLength = 253
+)
+
+var (
+	ErrEmpty        = errors.New("domain is empty")
+	ErrIPAddress    = errors.New("domain is an ip address")
+	ErrInvalid      = errors.New("domain is not a valid hostname")
+	ErrNotQualified = errors.New("domain is not fully qualified")
+	ErrLabelTooLong = errors.New("domain label is too long")
+	ErrTooLong      = errors.New("domain is too long")
+)
+
+// reasons lists the sentinel errors returned by Normalize.
+// Used to classify rejections.
+var reasons = []error{
+	ErrEmpty,
+	ErrIPAddress,
+	ErrInvalid,
+	ErrNotQualified,
+	ErrLabelTooLong,
+	ErrTooLong,
+}
+
+// profile maps the domain the same way browsers do before a lookup
+// (lowercase, Unicode normalization) and converts it to punycode.
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.StrictDomainName(true),
+)
+
+// Normalize returns the canonical form of a domain:
+// lowercased, converted to punycode, without port nor trailing dot.
+// It returns an error wrapping one of the Err* values if the domain is rejected.
+func Normalize(domain string) (string, error) {
+	d := strings.TrimSpace(domain)
+	if d == "" {
+		return "", ErrEmpty
+	}
+
+	// strip the port, if any
+	// net.SplitHostPort also removes the brackets around IPv6 literals
+	if host, port, err := net.SplitHostPort(d); err == nil && port != "" && isNumeric(port) {
+		d = host
+	}
+
+	d = strings.TrimSuffix(d, ".")
+	if d == "" {
+		return "", fmt.Errorf("%w: %q", ErrEmpty, domain)
+	}
+
+	if _, err := netip.ParseAddr(strings.Trim(d, "[]")); err == nil {
+		return "", fmt.Errorf("%w: %q", ErrIPAddress, domain)
+	}
+
+	d, err := profile.ToASCII(d)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %s", ErrInvalid, domain, err)
+	}
+
+	if len(d) > maxLength {
+		return "", fmt.Errorf("%w: %q", ErrTooLong, domain)
+	}
+
+	labels := strings.Split(d, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("%w: %q", ErrNotQualified, domain)
+	}
+
+	for _, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("%w: %q", ErrInvalid, domain)
+		}
+		if len(label) > maxLabelLength {
+			return "", fmt.Errorf("%w: %q", ErrLabelTooLong, domain)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return "", fmt.Errorf("%w: %q", ErrInvalid, domain)
+		}
+	}
+
+	// an all-numeric top-level domain is most likely
+	// a truncated or otherwise malformed IPv4 address
+	if isNumeric(labels[len(labels)-1]) {
+		return "", fmt.Errorf("%w: %q", ErrIPAddress, domain)
+	}
+
+	return d, nil
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Rejections counts the domains rejected by Normalize, by reason.
+type Rejections map[error]int
+
+// Add records the rejection. err must have been returned by Normalize.
+func (r Rejections) Add(err error) {
+	for _, reason := range reasons {
+		if errors.Is(err, reason) {
+			r[reason]++
+			return
+		}
+	}
+	r[ErrInvalid]++
+}
+
+// Total returns the total number of rejected domains.
+func (r Rejections) Total() int {
+	total := 0
+	for _, n := range r {
+		total += n
+	}
+	return total
+}
+
+// Merge adds the counts of other to r.
+func (r Rejections) Merge(other Rejections) {
+	for reason, n := range other {
+		r[reason] += n
+	}
+}
+
+func (r Rejections) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(r))
+	for _, reason := range reasons {
+		if n, ok := r[reason]; ok {
+			attrs = append(attrs, slog.Int(reason.Error(), n))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// NormalizeAll normalizes a list of domains.
+// Rejected domains are counted in rejections and duplicates are removed.
+// The order of the domains is preserved.
+func NormalizeAll(domains []string, rejections Rejections) []string {
+	seen := make(map[string]struct{}, len(domains))
+	normalized := make([]string, 0, len(domains))
+
+	for _, d := range domains {
+		n, err := Normalize(d)
+		if err != nil {
+			if rejections != nil {
+				rejections.Add(err)
+			}
+			continue
+		}
+
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		normalized = append(normalized, n)
+	}
+
+	return normalized
+}
@@ -0,0 +1,134 @@
+package hostname
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr error
+	}{
+		{
+			domain: "mastodon.social",
+			want:   "mastodon.social",
+		},
+		{
+			domain: "Mastodon.Social",
+			want:   "mastodon.social",
+		},
+		{
+			domain: "  mastodon.social\n",
+			want:   "mastodon.social",
+		},
+		{
+			domain: "mastodon.social.",
+			want:   "mastodon.social",
+		},
+		{
+			domain: "mastodon.social:443",
+			want:   "mastodon.social",
+		},
+		{
+			domain: "bücher.example",
+			want:   "xn--bcher-kva.example",
+		},
+		{
+			domain: "ＭＡＳＴＯＤＯＮ.social",
+			want:   "mastodon.social",
+		},
+		{
+			domain:  "",
+			wantErr: ErrEmpty,
+		},
+		{
+			domain:  ".",
+			wantErr: ErrEmpty,
+		},
+		{
+			domain:  "127.0.0.1",
+			wantErr: ErrIPAddress,
+		},
+		{
+			domain:  "127.0.0.1:8080",
+			wantErr: ErrIPAddress,
+		},
+		{
+			domain:  "[::1]:443",
+			wantErr: ErrIPAddress,
+		},
+		{
+			domain:  "2001:db8::1",
+			wantErr: ErrIPAddress,
+		},
+		{
+			domain:  "1.2.3",
+			wantErr: ErrIPAddress,
+		},
+		{
+			domain:  "localhost",
+			wantErr: ErrNotQualified,
+		},
+		{
+			domain:  "https://mastodon.social",
+			wantErr: ErrInvalid,
+		},
+		{
+			domain:  "mastodon..social",
+			wantErr: ErrInvalid,
+		},
+		{
+			domain:  "-mastodon.social",
+			wantErr: ErrInvalid,
+		},
+		{
+			domain:  "under_score.social",
+			wantErr: ErrInvalid,
+		},
+		{
+			domain:  "<script>alert(1)</script>",
+			wantErr: ErrInvalid,
+		},
+		{
+			domain:  strings.Repeat("a", 64) + ".social",
+			wantErr: ErrLabelTooLong,
+		},
+		{
+			domain:  strings.Repeat(strings.Repeat("a", 60)+".", 5) + "social",
+			wantErr: ErrTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := Normalize(tt.domain)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeAll(t *testing.T) {
+	rejections := make(Rejections)
+
+	got := NormalizeAll([]string{
+		"mastodon.social",
+		"MASTODON.social",
+		"10.0.0.1",
+		"pixelfed.social.",
+		"",
+	}, rejections)
+
+	assert.Equal(t, []string{"mastodon.social", "pixelfed.social"}, got)
+	assert.Equal(t, 2, rejections.Total())
+	assert.Equal(t, 1, rejections[ErrIPAddress])
+	assert.Equal(t, 1, rejections[ErrEmpty])
+}
@@ -0,0 +1,115 @@
+// Package incident decides when the outages of the instances are recorded.
+// An incident is opened by the first failed crawl of an instance which was up,
+// it is confirmed after a few consecutive failed crawls and closed after a few consecutive completed ones,
+// so that an instance going up and down does not open an incident at every crawl.
+// An incident recovering before being confirmed is discarded.
+package incident
+
+import (
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+// Policy is the configuration of the incidents.
+// The zero value is not usable, see DefaultPolicy.
+type Policy struct {
+	// ConfirmAfter is the number of consecutive failed crawls before an incident is confirmed
+	ConfirmAfter int
+	// CloseAfter is the number of consecutive completed crawls before an incident is closed
+	CloseAfter int
+}
+
+// DefaultPolicy matches the defaults of the command line flags.
+var DefaultPolicy = Policy{
+	ConfirmAfter: 2,
+	CloseAfter:   2,
+}
+
+// State is the open incident of an instance.
+type State struct {
+	// Open is false if the instance has no open incident, the other fields are then zero.
+	Open      bool
+	Confirmed bool
+
+	FailedCrawls int
+	// RecoveredCrawls is the number of consecutive completed crawls since the last failed one.
+	RecoveredCrawls int
+	// RecoveringSince is the start of the first of these completed crawls.
+	// Zero if the last crawl failed.
+	RecoveringSince time.Time
+}
+
+// Action is the change of the incident of an instance following a crawl.
+type Action string
+
+const (
+	ActionNone    Action = ""
+	ActionOpen    Action = "open"
+	ActionUpdate  Action = "update"
+	ActionClose   Action = "close"
+	ActionDiscard Action = "discard"
+)
+
+// Decision is the incident of an instance after a crawl.
+type Decision struct {
+	State
+	Action Action
+
+	// ClosedAt is the end of the outage if the incident is closed,
+	// the start of the first completed crawl after it.
+	ClosedAt time.Time
+}
+
+// Next returns the incident following the crawl of an instance.
+// wasUp is true if the last crawl of the instance before this one completed.
+func (p Policy) Next(state State, wasUp bool, crawl models.Crawl) Decision {
+	if crawl.Err != nil {
+		if !state.Open {
+			if !wasUp {
+				// never seen up, or already down before the incidents were recorded
+				return Decision{}
+			}
+			return Decision{
+				Action: ActionOpen,
+				State: State{
+					Open:         true,
+					Confirmed:    p.ConfirmAfter <= 1,
+					FailedCrawls: 1,
+				},
+			}
+		}
+
+		failed := state.FailedCrawls + 1
+		return Decision{
+			Action: ActionUpdate,
+			State: State{
+				Open:         true,
+				Confirmed:    state.Confirmed || failed >= p.ConfirmAfter,
+				FailedCrawls: failed,
+			},
+		}
+	}
+
+	if !state.Open {
+		return Decision{}
+	}
+	if !state.Confirmed {
+		return Decision{Action: ActionDiscard}
+	}
+
+	d := Decision{
+		Action: ActionUpdate,
+		State:  state,
+	}
+	d.RecoveredCrawls++
+	if d.RecoveringSince.IsZero() {
+		d.RecoveringSince = crawl.StartedAt
+	}
+
+	if d.RecoveredCrawls >= p.CloseAfter {
+		d.Action = ActionClose
+		d.ClosedAt = d.RecoveringSince
+	}
+	return d
+}
@@ -0,0 +1,114 @@
+package incident
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Next(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	failed := models.Crawl{
+		StartedAt:  now,
+		FinishedAt: now,
+		Status:     models.CrawlStatusFailed,
+		Err:        &models.CrawlError{Code: models.CrawlErrCodeUnreachable},
+	}
+	completed := models.Crawl{StartedAt: now, FinishedAt: now, Status: models.CrawlStatusCompleted}
+
+	tests := []struct {
+		name  string
+		state State
+		wasUp bool
+		crawl models.Crawl
+		want  Decision
+	}{
+		{
+			name:  "up",
+			wasUp: true,
+			crawl: completed,
+			want:  Decision{},
+		},
+		{
+			name:  "goes down",
+			wasUp: true,
+			crawl: failed,
+			want: Decision{
+				Action: ActionOpen,
+				State:  State{Open: true, FailedCrawls: 1},
+			},
+		},
+		{
+			name:  "never seen up",
+			crawl: failed,
+			want:  Decision{},
+		},
+		{
+			name:  "confirmed",
+			state: State{Open: true, FailedCrawls: 1},
+			crawl: failed,
+			want: Decision{
+				Action: ActionUpdate,
+				State:  State{Open: true, Confirmed: true, FailedCrawls: 2},
+			},
+		},
+		{
+			name:  "flapping",
+			state: State{Open: true, FailedCrawls: 1},
+			wasUp: false,
+			crawl: completed,
+			want:  Decision{Action: ActionDiscard},
+		},
+		{
+			name:  "recovering",
+			state: State{Open: true, Confirmed: true, FailedCrawls: 5},
+			crawl: completed,
+			want: Decision{
+				Action: ActionUpdate,
+				State:  State{Open: true, Confirmed: true, FailedCrawls: 5, RecoveredCrawls: 1, RecoveringSince: now},
+			},
+		},
+		{
+			name:  "down again while recovering",
+			state: State{Open: true, Confirmed: true, FailedCrawls: 5, RecoveredCrawls: 1, RecoveringSince: now.Add(-time.Hour)},
+			wasUp: true,
+			crawl: failed,
+			want: Decision{
+				Action: ActionUpdate,
+				State:  State{Open: true, Confirmed: true, FailedCrawls: 6},
+			},
+		},
+		{
+			name:  "recovered",
+			state: State{Open: true, Confirmed: true, FailedCrawls: 5, RecoveredCrawls: 1, RecoveringSince: now.Add(-6 * time.Hour)},
+			wasUp: true,
+			crawl: completed,
+			want: Decision{
+				Action:   ActionClose,
+				State:    State{Open: true, Confirmed: true, FailedCrawls: 5, RecoveredCrawls: 2, RecoveringSince: now.Add(-6 * time.Hour)},
+				ClosedAt: now.Add(-6 * time.Hour),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DefaultPolicy.Next(tt.state, tt.wasUp, tt.crawl))
+		})
+	}
+}
+
+func TestPolicy_Next_WithoutConfirmation(t *testing.T) {
+	p := Policy{ConfirmAfter: 1, CloseAfter: 1}
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	d := p.Next(State{}, true, models.Crawl{StartedAt: now, Err: &models.CrawlError{Code: models.CrawlErrCodeTimeout}})
+	assert.Equal(t, ActionOpen, d.Action)
+	assert.True(t, d.Confirmed)
+
+	d = p.Next(d.State, false, models.Crawl{StartedAt: now.Add(time.Hour)})
+	assert.Equal(t, ActionClose, d.Action)
+	assert.Equal(t, now.Add(time.Hour), d.ClosedAt)
+}
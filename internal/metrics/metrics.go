@@ -0,0 +1,75 @@
+// Package metrics contains the Prometheus metrics exported by the crawler and the API.
+// Metrics are registered on the default registry and served by Handler.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "blahaj"
+
+// Crawler metrics
+var (
+	CrawlsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawler",
+		Name:      "crawls_total",
+		Help:      "Number of crawls by status and error code.",
+	}, []string{"status", "error_code"})
+
+	QueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "crawler",
+		Name:      "queue_depth",
+		Help:      "Number of domains waiting to be crawled.",
+	})
+
+	DeferredDomains = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawler",
+		Name:      "deferred_domains_total",
+		Help:      "Number of discovered domains deferred to a later run because the queue was full.",
+	})
+
+	InFlightCrawls = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "crawler",
+		Name:      "in_flight_crawls",
+		Help:      "Number of crawls in progress, by worker.",
+	}, []string{"worker"})
+
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "crawler",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the HTTP requests made by the crawler, by endpoint.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"endpoint", "code"})
+
+	DownloadedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawler",
+		Name:      "downloaded_bytes_total",
+		Help:      "Number of bytes downloaded by the crawler, by endpoint.",
+	}, []string{"endpoint"})
+)
+
+// API metrics
+var (
+	APIRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the API requests, by OpenAPI operationId.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation_id", "code"})
+)
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
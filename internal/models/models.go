@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type CrawlStatus string
@@ -37,10 +38,10 @@ const (
 )
 
 type CrawlError struct {
-	Msg  string
-	Code CrawlErrCode
+	Msg  string       `json:"msg"`
+	Code CrawlErrCode `json:"code"`
 	// The description gets provided by the database via the error code.
-	Description string
+	Description string `json:"description,omitempty"`
 }
 
 func (e CrawlError) Error() string {
@@ -58,33 +59,307 @@ func (e CrawlError) DerivedInstanceStatus() FediverseInstanceStatus {
 	}
 }
 
+// Crawl is the result of a crawl.
+// The JSON encoding is used by the spool, it must remain backward compatible.
 type Crawl struct {
+	ID         uuid.UUID `json:"id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	// RunID is the ID of the crawl run the crawl is part of.
+	// Zero if the crawl was not part of a recorded run.
+	RunID uuid.UUID `json:"run_id"`
+
+	InstanceID uuid.UUID    `json:"instance_id"`
+	Domain     string       `json:"domain"`
+	Addresses  []netip.Addr `json:"addresses"`
+
+	Status CrawlStatus `json:"status"`
+	Err    *CrawlError `json:"error,omitempty"`
+
+	// Peers are at most MaxPeers, while NumberOfPeers is the number of peers listed by the instance
+	Peers          []string `json:"peers"`
+	NumberOfPeers  *int32   `json:"number_of_peers"`
+	PeersTruncated bool     `json:"peers_truncated"`
+	// MaxPeers is the limit applied to the peers, zero if unlimited
+	MaxPeers int `json:"max_peers"`
+
+	SoftwareName    *string `json:"software_name"`
+	SoftwareVersion *string `json:"software_version"`
+
+	// depending on the nodeinfo version, these fields may be nil
+	OpenRegistrations *bool  `json:"open_registrations"`
+	TotalUsers        *int32 `json:"total_users"`
+	ActiveHalfyear    *int32 `json:"active_half_year"`
+	ActiveMonth       *int32 `json:"active_month"`
+	LocalPosts        *int32 `json:"local_posts"`
+	LocalComments     *int32 `json:"local_comments"`
+	// Languages are the primary language subtags reported by the instance, nil if not reported
+	Languages []string `json:"languages,omitempty"`
+	// Title and Description are reported by the instance, as text
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+
+	RawNodeinfo json.RawMessage `json:"raw_nodeinfo,omitempty"`
+
+	// SpanContext links the persistence of the crawl to the trace of the crawl.
+	// Invalid if the crawl was not traced.
+	SpanContext trace.SpanContext `json:"-"`
+}
+
+// CrawlRun is a single execution of the crawler.
+type CrawlRun struct {
 	ID         uuid.UUID
 	StartedAt  time.Time
-	FinishedAt time.Time
+	FinishedAt *time.Time
 
-	InstanceID uuid.UUID
-	Domain     string
-	Addresses  []netip.Addr
+	CrawlerVersion string
+	Seeds          []string
+	// Config is the configuration of the crawler for this run.
+	Config json.RawMessage
+
+	Stats CrawlRunStats
+}
+
+// CrawlRunStats are the statistics of a crawl run.
+type CrawlRunStats struct {
+	Attempted int
+	Completed int
+	Failed    int
+	// Discovered is the number of instances discovered during the run.
+	Discovered int
+	// Rejected is the number of peers rejected because their domain is invalid.
+	Rejected int
+	// Blocked is the number of peers skipped because their domain is blocked.
+	Blocked int
+	// BlockedGroups is the number of blocked peers per registrable domain, see blocklist.Group.
+	BlockedGroups map[string]int
+	// Lost is the number of crawls not persisted because the run was stopped.
+	Lost int
+
+	Errors   map[CrawlErrCode]int
+	Software map[string]int
+}
+
+// GlobalStats are totals over the last crawl of each instance, small servers excluded.
+type GlobalStats struct {
+	Instances int64
+	Up        int64
+	Down      int64
+	Unhealthy int64
+
+	TotalUsers       int64
+	ActiveUsersMonth int64
+	LocalPosts       int64
+
+	// OpenRegistrationsShare is the share of the instances with open registrations,
+	// among the ones reporting it. Nil if none does.
+	OpenRegistrationsShare *float64
+
+	// ComputedAt is when the stats were computed, they are cached between crawl runs
+	ComputedAt time.Time
+}
+
+// SoftwareStats are totals over the last crawl of the instances of a software, small servers excluded.
+type SoftwareStats struct {
+	// Name is lowercased
+	Name string
+
+	Instances int64
+	Up        int64
+	Down      int64
+	Unhealthy int64
+
+	TotalUsers       int64
+	ActiveUsersMonth int64
+
+	// Versions are the most recent first, then the invalid versions
+	Versions []SoftwareVersionCount
+}
+
+// UpRatio returns the share of the instances that are up.
+func (s SoftwareStats) UpRatio() float64 {
+	if s.Instances == 0 {
+		return 0
+	}
+	return float64(s.Up) / float64(s.Instances)
+}
+
+// SoftwareVersionCount is the number of instances running a version of a software.
+type SoftwareVersionCount struct {
+	// Version is without the fork, eg: 4.2.0, empty if the version is not reported or invalid
+	Version string
+	// Fork is the name of the fork, eg: glitch, empty for the original software
+	Fork      string
+	Instances int64
+}
+
+// TimeseriesMetric is a metric of the time series.
+type TimeseriesMetric string
+
+const (
+	TimeseriesUsers            TimeseriesMetric = "users"
+	TimeseriesActiveUsersMonth TimeseriesMetric = "active_users_month"
+	TimeseriesLocalPosts       TimeseriesMetric = "local_posts"
+	// TimeseriesUptime is the share of the crawls that completed
+	TimeseriesUptime TimeseriesMetric = "uptime"
+)
+
+// TimeseriesInterval is the duration covered by a point of the time series.
+type TimeseriesInterval string
+
+const (
+	TimeseriesDay TimeseriesInterval = "day"
+	// TimeseriesWeek starts on Monday
+	TimeseriesWeek  TimeseriesInterval = "week"
+	TimeseriesMonth TimeseriesInterval = "month"
+)
+
+// TimeseriesQuery selects the points of a time series.
+type TimeseriesQuery struct {
+	Metric   TimeseriesMetric
+	Interval TimeseriesInterval
+	// From and To are days in UTC, both included
+	From time.Time
+	To   time.Time
+}
+
+// TimeseriesPoint is the value of a metric over an interval.
+// The intervals without data have no point.
+type TimeseriesPoint struct {
+	// Start is the first day of the interval
+	Start time.Time
+	// Value is the last value of the interval, or the uptime over the interval.
+	// Nil if it was never reported.
+	Value *float64
+	// Min and Max are the range of the values during the interval,
+	// or of the daily uptime
+	Min *float64
+	Max *float64
+}
+
+// InstancesFilter filters the instances, empty fields match every instance.
+type InstancesFilter struct {
+	// Software matches any of the software names
+	Software          []string
+	Status            FediverseInstanceStatus
+	OpenRegistrations *bool
+	MinUsers          *int32
+	MaxUsers          *int32
+	// VersionPrefix matches the software versions starting with it, eg: 4.2
+	VersionPrefix string
+	// Language is a primary language subtag, eg: en
+	Language string
+}
+
+type InstanceSortKey string
+
+const (
+	InstanceSortTotalUsers       InstanceSortKey = "total_users"
+	InstanceSortActiveUsersMonth InstanceSortKey = "active_users_month"
+	InstanceSortLocalPosts       InstanceSortKey = "local_posts"
+	InstanceSortNumberOfPeers    InstanceSortKey = "number_of_peers"
+	InstanceSortDomain           InstanceSortKey = "domain"
+	InstanceSortLastCrawledAt    InstanceSortKey = "last_crawled_at"
+	InstanceSortFirstSeenAt      InstanceSortKey = "first_seen_at"
+	InstanceSortUptime24h        InstanceSortKey = "uptime_24h"
+	InstanceSortUptime7d         InstanceSortKey = "uptime_7d"
+	InstanceSortUptime30d        InstanceSortKey = "uptime_30d"
+	InstanceSortUptime90d        InstanceSortKey = "uptime_90d"
+)
 
-	Status CrawlStatus
-	Err    *CrawlError
+// InstancesSort is the order of the instances.
+// The instances with the same key are sorted by ID.
+type InstancesSort struct {
+	Key        InstanceSortKey
+	Descending bool
+}
+
+// DefaultInstancesSort ranks the biggest instances first.
+var DefaultInstancesSort = InstancesSort{Key: InstanceSortTotalUsers, Descending: true}
+
+// InstancesCursor is the position of an instance in a listing, a page starts after it.
+type InstancesCursor struct {
+	Sort InstancesSort
+	// Value is the sort key of the instance as text, as compared by the database.
+	Value string
+	ID    uuid.UUID
+}
+
+// CrawlsCursor is the position of a crawl in the crawls of an instance, a page starts after it.
+type CrawlsCursor struct {
+	StartedAt time.Time
+	ID        uuid.UUID
+}
 
-	Peers         []string
-	NumberOfPeers *int32
+type PeerDirection string
 
+const (
+	// PeerDirectionOutgoing are the peers listed by an instance.
+	PeerDirectionOutgoing PeerDirection = "outgoing"
+	// PeerDirectionIncoming are the instances listing an instance as peer.
+	PeerDirectionIncoming PeerDirection = "incoming"
+)
+
+// PeersFilter filters the peers of an instance, empty fields match every peer.
+type PeersFilter struct {
+	Direction PeerDirection
+	Status    FediverseInstanceStatus
+	Software  string
+}
+
+// Peer is a summary of an instance in a peering relationship.
+type Peer struct {
+	ID              uuid.UUID
+	Domain          string
+	Status          FediverseInstanceStatus
 	SoftwareName    *string
 	SoftwareVersion *string
+	// TotalUsers is nil for small servers, see the privacy policy of the API
+	TotalUsers *int32
+	// LastCrawledAt is nil if the peer has never been crawled
+	LastCrawledAt *time.Time
 
-	// depending on the nodeinfo version, these fields may be nil
-	OpenRegistrations *bool
-	TotalUsers        *int32
-	ActiveHalfyear    *int32
-	ActiveMonth       *int32
-	LocalPosts        *int32
-	LocalComments     *int32
+	// FirstSeenAt is nil if the peer was listed before the peering relationships were tracked
+	FirstSeenAt *time.Time
+	LastSeenAt  time.Time
+}
+
+type PeerChangeKind string
+
+const (
+	PeerChangeGained PeerChangeKind = "gained"
+	PeerChangeLost   PeerChangeKind = "lost"
+)
+
+// PeerChange is a peer that appeared in, or disappeared from, the peers of an instance.
+type PeerChange struct {
+	PeerID    uuid.UUID
+	Domain    string
+	Change    PeerChangeKind
+	ChangedAt time.Time
+}
 
-	RawNodeinfo json.RawMessage
+// SearchResult is an instance matching a search query.
+type SearchResult struct {
+	Instance FediverseInstance
+	// Rank is the relevance of the instance, higher is better
+	Rank float64
+	// Snippet is an excerpt of the description, as HTML:
+	// the text is escaped and the matches are in <mark> elements
+	Snippet string
+}
+
+// InstanceLookup is the result of the lookup of an instance by domain.
+type InstanceLookup struct {
+	// Domain is the domain as requested
+	Domain string
+	// Err is set if the domain is invalid, see the hostname package
+	Err error
+	// Found is false if the instance is unknown or not exposed by the API
+	Found  bool
+	ID     uuid.UUID
+	Status FediverseInstanceStatus
 }
 
 type FediverseInstanceStatus string
@@ -106,9 +381,55 @@ type FediverseInstance struct {
 	// searching for instances by software
 	SoftwareName *string
 
+	// from the last completed crawl, to keep them when the instance is down
+	Title       *string
+	Description *string
+
+	Uptime Uptime
+
 	LastCrawl *Crawl
 }
 
+// Uptime is the share of the time an instance was up, from 0 to 1, over windows ending at the last crawl run.
+// The time during which the status of the instance is unknown is excluded,
+// a window is nil if the status is unknown for most of it.
+type Uptime struct {
+	Last24h *float64
+	Last7d  *float64
+	Last30d *float64
+	Last90d *float64
+}
+
+// Incident is an outage of an instance, see internal/incident.
+type Incident struct {
+	ID         uuid.UUID
+	InstanceID uuid.UUID
+	Domain     string
+
+	// StartedAt is the start of the first failed crawl of the outage, FirstCrawlID
+	StartedAt    time.Time
+	FirstCrawlID uuid.UUID
+	// ErrorCode is the error of the first failed crawl
+	ErrorCode    CrawlErrCode
+	FailedCrawls int
+
+	// ClosedAt is the start of the first completed crawl after the outage, nil while it lasts
+	ClosedAt *time.Time
+}
+
+// Active returns true while the outage lasts.
+func (i Incident) Active() bool {
+	return i.ClosedAt == nil
+}
+
+// Duration returns the duration of the outage, until now while it lasts.
+func (i Incident) Duration(now time.Time) time.Duration {
+	if i.ClosedAt != nil {
+		return i.ClosedAt.Sub(i.StartedAt)
+	}
+	return now.Sub(i.StartedAt)
+}
+
 func (s FediverseInstance) String() string {
 	return s.Domain
 }
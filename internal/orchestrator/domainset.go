@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// domainSet is an approximate set of domains, a Bloom filter.
+// Its memory is fixed by its capacity, whatever the number of domains added,
+// at the cost of false positives: Contains may return true for a domain that was never added.
+// The false positive rate is the one given to newDomainSet until the capacity is reached,
+// then it grows with the number of domains.
+type domainSet struct {
+	seed maphash.Seed
+	bits []uint64
+	// hashes is the number of bits set per domain
+	hashes uint64
+}
+
+func newDomainSet(capacity int, falsePositiveRate float64) *domainSet {
+	// optimal number of bits and of hashes for the capacity and the false positive rate
+	n := float64(max(capacity, 1))
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/n*math.Ln2))
+
+	return &domainSet{
+		seed:   maphash.MakeSeed(),
+		bits:   make([]uint64, (uint64(m)+63)/64),
+		hashes: uint64(k),
+	}
+}
+
+// positions calls fn with the position of every bit of the domain,
+// derived from two halves of a single hash, see Kirsch and Mitzenmacher.
+func (s *domainSet) positions(domain string, fn func(i uint64)) {
+	h := maphash.String(s.seed, domain)
+	h1, h2 := h&math.MaxUint32, h>>32
+	m := uint64(len(s.bits)) * 64
+	for i := uint64(0); i < s.hashes; i++ {
+		fn((h1 + i*h2) % m)
+	}
+}
+
+func (s *domainSet) Add(domain string) {
+	s.positions(domain, func(i uint64) {
+		s.bits[i/64] |= 1 << (i % 64)
+	})
+}
+
+func (s *domainSet) Contains(domain string) bool {
+	found := true
+	s.positions(domain, func(i uint64) {
+		found = found && s.bits[i/64]&(1<<(i%64)) != 0
+	})
+	return found
+}
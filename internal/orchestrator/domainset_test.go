@@ -0,0 +1,31 @@
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainSet(t *testing.T) {
+	const capacity = 10_000
+	s := newDomainSet(capacity, 0.01)
+
+	for i := 0; i < capacity; i++ {
+		s.Add(fmt.Sprintf("%d.example.com", i))
+	}
+
+	// no false negatives
+	for i := 0; i < capacity; i++ {
+		assert.True(t, s.Contains(fmt.Sprintf("%d.example.com", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < capacity; i++ {
+		if s.Contains(fmt.Sprintf("%d.example.org", i)) {
+			falsePositives++
+		}
+	}
+	// about 1% at capacity, with some margin
+	assert.Less(t, falsePositives, capacity*2/100)
+}
@@ -2,155 +2,359 @@ package orchestrator
 
 import (
 	"context"
-	"strings"
+	"errors"
+	"maps"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"log/slog"
 
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
 	"github.com/cyclimse/fediverse-blahaj/internal/crawler"
+	"github.com/cyclimse/fediverse-blahaj/internal/hostname"
+	"github.com/cyclimse/fediverse-blahaj/internal/metrics"
 	"github.com/cyclimse/fediverse-blahaj/internal/models"
-	"golang.org/x/sync/errgroup"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	startingCrawlCapacity = 500
+	// DefaultQueueCapacity is the number of domains waiting to be crawled
+	// above which the discovered peers are deferred to a later run
+	DefaultQueueCapacity = 10_000
+
+	// seenDomainsCapacity is the number of domains seen during a run up to which
+	// the set of seen domains has its false positive rate, it takes about 1.2MB.
+	// Past it, more and more new domains are wrongly skipped, they are crawled in a later run.
+	seenDomainsCapacity          = 1_000_000
+	seenDomainsFalsePositiveRate = 0.01
 )
 
+var tracer = otel.Tracer("github.com/cyclimse/fediverse-blahaj/internal/orchestrator")
+
 func New(config OrchestratorConfig) *Orchestrator {
-	return &Orchestrator{
-		crawledDomains: make(map[string]struct{}),
-		config:         config,
+	o := &Orchestrator{
+		seenDomains:   newDomainSet(seenDomainsCapacity, seenDomainsFalsePositiveRate),
+		rejections:    make(hostname.Rejections),
+		blockedGroups: make(map[string]int),
+		config:        config,
 	}
+	o.crawlFunc = o.crawl
+	return o
 }
 
 type OrchestratorConfig struct {
 	NumCrawlers      int
-	BlockedDomains   []string
+	Blocklist        *blocklist.Blocklist
 	SeedDomains      []string
 	CrawlTimeout     time.Duration
 	CrawlerUserAgent string
+	// MaxPeers is the maximum number of peers kept per crawl, unlimited if zero
+	MaxPeers int
+	// QueueCapacity bounds the number of domains waiting to be crawled,
+	// DefaultQueueCapacity if zero
+	QueueCapacity int
+	// DrainTimeout is the grace period given to the crawls in progress
+	// once the context of the crawl is done
+	DrainTimeout time.Duration
+	// RunID is attached to every crawl, can be zero
+	RunID uuid.UUID
+	// SkippedDomains are not queued when discovered as peers,
+	// eg: the domains not due for a crawl according to their schedule.
+	// The seeds are crawled even if they are skipped.
+	SkippedDomains []string
 }
 
 type Orchestrator struct {
-	crawledDomains map[string]struct{}
-	config         OrchestratorConfig
+	// seenDomains contains the domains queued, deferred or blocked during the crawl, so that each is considered once.
+	// It is approximate so that its memory does not grow with the network, see seenDomainsCapacity.
+	// Only accessed by the goroutine running Crawl.
+	seenDomains *domainSet
+	// deferred counts the discovered domains not queued because the queue was full
+	deferred int
+	// rejections counts the discovered domains that failed normalization
+	rejections hostname.Rejections
+	// blocked counts the distinct discovered domains skipped because of the blocklist
+	blocked atomic.Int64
+	// blockedGroups counts the blocked domains per registrable domain,
+	// only accessed by the goroutine running Crawl
+	blockedGroups map[string]int
+	// lost counts the crawls interrupted by the end of the grace period
+	lost   atomic.Int64
+	config OrchestratorConfig
+	// crawlFunc crawls a single domain, replaced in tests
+	crawlFunc func(ctx context.Context, c *crawler.Crawler, domain string) crawler.CrawlResult
+}
+
+// Rejections returns the number of domains rejected during the crawl, by reason.
+// Should only be called once Crawl has returned.
+func (o *Orchestrator) Rejections() hostname.Rejections {
+	return o.rejections
+}
+
+// Stats returns the statistics only known by the orchestrator.
+// The others are derived from the crawls themselves.
+// Should only be called once Crawl has returned.
+func (o *Orchestrator) Stats() models.CrawlRunStats {
+	return models.CrawlRunStats{
+		Rejected:      o.rejections.Total(),
+		Blocked:       int(o.blocked.Load()),
+		BlockedGroups: maps.Clone(o.blockedGroups),
+		Lost:          int(o.lost.Load()),
+	}
 }
 
 // crawlerIdKey is the key for the crawler id in the context.
 type crawlerIdKey struct{}
 
 // Crawl crawls the fediverse and streams the results to the results channel.
-// It returns an error if the context is exceeded.
+// Discovered domains are queued until the queue is full, the others are left for a later run.
+// The crawl stops once every queued domain has been crawled, or once the context is done:
+// then no new domain is crawled and the crawls in progress are given DrainTimeout to finish.
+// The results channel must be drained by the caller.
+// It returns the error of the context, if any.
 func (o *Orchestrator) Crawl(ctx context.Context, results chan models.Crawl) error {
 	crawlers := make([]*crawler.Crawler, o.config.NumCrawlers)
 
 	for i := 0; i < o.config.NumCrawlers; i++ {
-		crawlers[i] = crawler.New(o.config.CrawlerUserAgent)
+		crawlers[i] = crawler.New(crawler.Config{
+			UserAgent: o.config.CrawlerUserAgent,
+			MaxPeers:  o.config.MaxPeers,
+		})
+	}
+
+	queueCapacity := o.config.QueueCapacity
+	if queueCapacity <= 0 {
+		queueCapacity = DefaultQueueCapacity
 	}
 
 	// channels for the crawl
-	requested := make(chan string, startingCrawlCapacity)
-	processed := make(chan crawler.CrawlResult, startingCrawlCapacity)
+	// the workers block on processed while the results are consumed,
+	// which slows down the crawl instead of buffering the results
+	requested := make(chan string, queueCapacity)
+	processed := make(chan crawler.CrawlResult, o.config.NumCrawlers)
+
+	// number of domains queued but not processed yet
+	pending := 0
 
 	// start the crawl
-	for _, domain := range o.config.SeedDomains {
-		requested <- domain
+	for _, domain := range hostname.NormalizeAll(o.config.SeedDomains, o.rejections) {
+		if o.enqueue(requested, domain) {
+			pending++
+		}
 	}
 
+	// the skipped domains are never queued once seen
+	for _, domain := range o.config.SkippedDomains {
+		o.seenDomains.Add(domain)
+	}
+	// only the seen domains are kept during the crawl
+	o.config.SkippedDomains = nil
+
+	defer func() {
+		slog.InfoContext(ctx, "rejected domains", "total", o.rejections.Total(), "reasons", o.rejections)
+		if o.deferred > 0 {
+			slog.InfoContext(ctx, "domains deferred to a later run because the queue was full", "total", o.deferred)
+		}
+	}()
+
+	// the workers stop taking new domains once ctx is done or once there is nothing left to crawl
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	// the crawls in progress outlive ctx for at most DrainTimeout
+	drainCtx, cancelDrain := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelDrain()
+	go func() {
+		select {
+		case <-drainCtx.Done():
+			return
+		case <-ctx.Done():
+		}
+		slog.InfoContext(drainCtx, "draining crawls in progress", "timeout", o.config.DrainTimeout)
+
+		timer := time.NewTimer(o.config.DrainTimeout)
+		defer timer.Stop()
+		select {
+		case <-drainCtx.Done():
+		case <-timer.C:
+			cancelDrain()
+		}
+	}()
+
+	var workers sync.WaitGroup
 	for i := range crawlers {
 		c := crawlers[i]
+		workers.Add(1)
 		// capture as argument to avoid loopclosure issues
 		go func(i int) {
+			defer workers.Done()
+			inFlight := metrics.InFlightCrawls.WithLabelValues(strconv.Itoa(i))
 			for {
 				select {
-				case <-ctx.Done():
+				case <-workersCtx.Done():
 					return
 				case url := <-requested:
-					crawlCtx := context.WithValue(ctx, crawlerIdKey{}, i)
+					// both cases can be ready at the same time
+					if workersCtx.Err() != nil {
+						return
+					}
+					metrics.QueueDepth.Set(float64(len(requested)))
+					inFlight.Inc()
+					crawlCtx := context.WithValue(drainCtx, crawlerIdKey{}, i)
 					crawlCtx, cancel := context.WithTimeout(crawlCtx, o.config.CrawlTimeout)
-					// this could hang if the processed channel is full
-					processed <- *c.Crawl(crawlCtx, url)
+					res := o.crawlFunc(crawlCtx, c, url)
 					cancel()
+					inFlight.Dec()
+
+					if drainCtx.Err() != nil && wasInterrupted(res) {
+						// interrupted by the end of the grace period,
+						// the result says nothing about the instance
+						o.lost.Add(1)
+						return
+					}
+					processed <- res
 				}
 			}
 		}(i)
 	}
 
-	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(-1)
+	go func() {
+		workers.Wait()
+		close(processed)
+	}()
+
+	if pending == 0 {
+		stopWorkers()
+	}
+
+	// runs until every worker has exited
+	for res := range processed {
+		pending--
+
+		if res.Err != nil {
+			slog.ErrorContext(ctx, "failed to crawl", "domain", res.Domain, "error", res.Err)
+		}
+
+		errCode := ""
+		if res.Err != nil {
+			errCode = string(res.ErrCode)
+		}
+		metrics.CrawlsTotal.WithLabelValues(string(crawlStatus(res)), errCode).Inc()
+
+		// continue the trace of the crawl
+		_, span := tracer.Start(trace.ContextWithSpanContext(ctx, res.SpanContext), "orchestrator.HandleResult")
 
-	g.Go(func() error {
-		for {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case res := <-processed:
-				if res.Err != nil {
-					slog.ErrorContext(ctx, "failed to crawl", "domain", res.Domain, "error", res.Err)
+		// peers are untrusted input, the crawler only keeps valid domains
+		o.rejections.Merge(res.Peers.Rejections)
+		span.SetAttributes(attribute.Int("peers", len(res.Peers.Domains)))
+		// send the peer to the results channel
+		crawl := crawler.CrawlFromResult(res)
+		crawl.RunID = o.config.RunID
+		results <- crawl
+
+		// no need to discover new domains when draining
+		if workersCtx.Err() == nil {
+			queued := 0
+			for _, peer := range res.Peers.Domains {
+				if o.wasSeen(peer) || o.isBlocked(peer) {
+					continue
 				}
-				// send the peer to the results channel
-				results <- crawler.CrawlFromResult(res)
-
-				// mark the peer as crawled
-				o.crawledDomains[res.Domain] = struct{}{}
-
-				// parallelized because otherwise
-				// it fails to keep up with the crawler
-				// on servers with many peers
-				for i := range res.Peers {
-					peer := res.Peers[i]
-					g.Go(func() error {
-						// check if the peer was already this session
-						if o.wasCrawled(peer) {
-							slog.InfoContext(ctx, "peer was already crawled", "peer", peer)
-							return nil
-						}
-
-						if o.isBlocked(peer) {
-							// slog.InfoContext(ctx, "peer is blocked", "peer", peer)
-							return nil
-						}
-
-						// if the context is exceeded, the workers have exited and we can stop
-						// to avoid a deadlock
-						select {
-						case <-ctx.Done():
-							return ctx.Err()
-						case requested <- peer:
-						}
-
-						return nil
-					})
+				if o.enqueue(requested, peer) {
+					queued++
 				}
-
 			}
+			pending += queued
+			span.SetAttributes(attribute.Int("queued", queued))
+		}
+		span.End()
+
+		if pending == 0 {
+			slog.InfoContext(ctx, "no more domains to crawl")
+			stopWorkers()
 		}
-	})
+	}
+
+	if lost := o.lost.Load(); lost > 0 {
+		slog.WarnContext(ctx, "crawls interrupted by the end of the grace period", "lost", lost)
+	}
 
-	// this will hang until the context is exceeded
-	// this is expected
-	return g.Wait()
+	return ctx.Err()
 }
 
-func (o *Orchestrator) wasCrawled(domain string) bool {
-	// parallel access to the map is safe
-	_, ok := o.crawledDomains[domain]
-	return ok
+// enqueue queues the domain without blocking.
+// Returns false if the domain was already seen during the run,
+// or if the queue is full: the domain is then deferred to a later run.
+// A deferred domain is counted once and is not queued again during the run.
+func (o *Orchestrator) enqueue(requested chan<- string, domain string) bool {
+	if o.wasSeen(domain) {
+		return false
+	}
+	o.seenDomains.Add(domain)
+
+	select {
+	case requested <- domain:
+		metrics.QueueDepth.Set(float64(len(requested)))
+		return true
+	default:
+		o.deferred++
+		metrics.DeferredDomains.Inc()
+		return false
+	}
+}
+
+// crawl crawls a single domain.
+// Each crawl is the root of its own trace.
+func (o *Orchestrator) crawl(ctx context.Context, c *crawler.Crawler, domain string) crawler.CrawlResult {
+	attrs := []attribute.KeyValue{attribute.String("domain", domain)}
+	if id, ok := ctx.Value(crawlerIdKey{}).(int); ok {
+		attrs = append(attrs, attribute.Int("crawler.id", id))
+	}
+
+	ctx, span := tracer.Start(ctx, "orchestrator.Crawl", trace.WithNewRoot(), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	res := *c.Crawl(ctx, domain)
+	res.SpanContext = span.SpanContext()
+	return res
+}
+
+// wasInterrupted returns true if the crawl was cut short by its context.
+// The crawls that finished before are kept, even when the grace period is over.
+func wasInterrupted(res crawler.CrawlResult) bool {
+	return errors.Is(res.Err, context.Canceled) || errors.Is(res.Err, context.DeadlineExceeded)
+}
+
+func crawlStatus(res crawler.CrawlResult) models.CrawlStatus {
+	if res.Err != nil {
+		return models.CrawlStatusFailed
+	}
+	return models.CrawlStatusCompleted
+}
+
+// wasSeen returns true if the domain was queued, deferred or blocked during the run.
+// It may return true for a few domains that were not, see seenDomainsCapacity.
+func (o *Orchestrator) wasSeen(domain string) bool {
+	return o.seenDomains.Contains(domain)
 }
 
 // isBlocked returns true if the domain is blocked.
+// A blocked domain is remembered as seen, so that it is matched and counted once per run.
 func (o *Orchestrator) isBlocked(domain string) bool {
-	// we need to block domains and subdomains
-	// e.g. blocking ngrok.io should also block a.ngrok.io
-	for _, blockedDomain := range o.config.BlockedDomains {
-		if domain == blockedDomain {
-			return true
-		}
-
-		if strings.HasSuffix(domain, blockedDomain) {
-			return true
-		}
+	if o.config.Blocklist == nil {
+		return false
 	}
 
-	return false
+	m, ok := o.config.Blocklist.Match(domain)
+	if ok {
+		o.seenDomains.Add(domain)
+		o.blocked.Add(1)
+		o.blockedGroups[m.Group]++
+		slog.Debug("domain is blocked", "domain", domain, "group", m.Group, "rule", m.Rule, "reason", m.Rule.Reason)
+	}
+	return ok
 }
@@ -1,16 +1,27 @@
 package orchestrator
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/cyclimse/fediverse-blahaj/internal/blocklist"
+	"github.com/cyclimse/fediverse-blahaj/internal/crawler"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOrchestrator_isBlocked(t *testing.T) {
-	var blockedDomains = []string{
-		"localhost",
-		"ngrok.io",
-	}
+	var blockedDomains = blocklist.New(
+		blocklist.MustParseRule("localhost", "test"),
+		blocklist.MustParseRule("ngrok.io", "test"),
+	)
+
+	o := New(OrchestratorConfig{
+		Blocklist: blockedDomains,
+	})
 
 	tests := []struct {
 		domain string
@@ -32,16 +43,180 @@ func TestOrchestrator_isBlocked(t *testing.T) {
 			domain: "reallylong.toto.subdomain.ngrok.io",
 			want:   true,
 		},
+		{
+			domain: "other.subdomain.ngrok.io",
+			want:   true,
+		},
+		{
+			domain: "notngrok.io",
+			want:   false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.domain, func(t *testing.T) {
-			o := Orchestrator{
-				config: OrchestratorConfig{
-					BlockedDomains: blockedDomains,
-				},
-			}
 			got := o.isBlocked(tt.domain)
 			assert.Equal(t, tt.want, got)
 		})
 	}
+
+	// the blocked domains are remembered, and counted once
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, o.wasSeen(tt.domain), tt.domain)
+	}
+
+	stats := o.Stats()
+	assert.Equal(t, 5, stats.Blocked)
+	// ngrok.io is a public suffix, its subdomains are grouped by their own registrable domain
+	assert.Equal(t, map[string]int{
+		"localhost":           1,
+		"subdomain.localhost": 1,
+		"ngrok.io":            1,
+		"subdomain.ngrok.io":  2,
+	}, stats.BlockedGroups)
+}
+
+func TestOrchestrator_CrawlReturnsWhenThereIsNothingToCrawl(t *testing.T) {
+	o := New(OrchestratorConfig{
+		NumCrawlers:  2,
+		SeedDomains:  []string{"localhost", "not a domain"},
+		CrawlTimeout: time.Second,
+		DrainTimeout: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	results := make(chan models.Crawl, 1)
+	err := o.Crawl(ctx, results)
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Equal(t, 2, o.Stats().Rejected)
+	assert.Zero(t, o.Stats().Lost)
+}
+
+func TestOrchestrator_CrawlKeepsTheCrawlsFinishedAtTheEndOfTheGracePeriod(t *testing.T) {
+	o := New(OrchestratorConfig{
+		NumCrawlers:  2,
+		SeedDomains:  []string{"fast.example.com", "slow.example.com"},
+		CrawlTimeout: time.Minute,
+		DrainTimeout: 50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{}, 2)
+	o.crawlFunc = func(crawlCtx context.Context, _ *crawler.Crawler, domain string) crawler.CrawlResult {
+		started <- struct{}{}
+		res := crawler.CrawlResult{Domain: domain, Start: time.Now()}
+		// both crawls are still in progress at the end of the grace period
+		<-crawlCtx.Done()
+		if domain == "slow.example.com" {
+			res.Err = fmt.Errorf("failed to get nodeinfo: %w", crawlCtx.Err())
+			res.ErrCode = models.CrawlErrCodeTimeout
+		}
+		// otherwise the crawl finished right before being cut short
+		res.End = time.Now()
+		return res
+	}
+
+	go func() {
+		<-started
+		<-started
+		cancel()
+	}()
+
+	results := make(chan models.Crawl, 2)
+	err := o.Crawl(ctx, results)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, o.Stats().Lost)
+	require.Len(t, results, 1)
+	crawl := <-results
+	assert.Equal(t, "fast.example.com", crawl.Domain)
+	assert.Equal(t, models.CrawlStatusCompleted, crawl.Status)
+}
+
+func TestOrchestrator_CrawlCountsTheBlockedDomainsOnce(t *testing.T) {
+	o := New(OrchestratorConfig{
+		NumCrawlers:  1,
+		Blocklist:    blocklist.New(blocklist.MustParseRule("ngrok.io", "test")),
+		SeedDomains:  []string{"mastodon.social", "lemmy.ml"},
+		CrawlTimeout: time.Second,
+		DrainTimeout: time.Second,
+	})
+	o.crawlFunc = func(_ context.Context, _ *crawler.Crawler, domain string) crawler.CrawlResult {
+		// both seeds list the same blocked peer
+		return crawler.CrawlResult{Domain: domain, Peers: crawler.Peers{Domains: []string{"a.ngrok.io"}}}
+	}
+
+	results := make(chan models.Crawl, 2)
+	require.NoError(t, o.Crawl(context.Background(), results))
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, 1, o.Stats().Blocked)
+	assert.Equal(t, map[string]int{"a.ngrok.io": 1}, o.Stats().BlockedGroups)
+}
+
+func TestOrchestrator_CrawlDoesNotQueueTheSkippedDomains(t *testing.T) {
+	o := New(OrchestratorConfig{
+		NumCrawlers:  1,
+		SeedDomains:  []string{"mastodon.social"},
+		CrawlTimeout: time.Second,
+		DrainTimeout: time.Second,
+		// not due for a crawl, the seeds are crawled anyway
+		SkippedDomains: []string{"mastodon.social", "mastodon.online"},
+	})
+	o.crawlFunc = func(_ context.Context, _ *crawler.Crawler, domain string) crawler.CrawlResult {
+		return crawler.CrawlResult{Domain: domain, Peers: crawler.Peers{Domains: []string{"mastodon.online", "lemmy.ml"}}}
+	}
+
+	results := make(chan models.Crawl, 3)
+	require.NoError(t, o.Crawl(context.Background(), results))
+	close(results)
+
+	var crawled []string
+	for crawl := range results {
+		crawled = append(crawled, crawl.Domain)
+	}
+	assert.ElementsMatch(t, []string{"mastodon.social", "lemmy.ml"}, crawled)
+}
+
+func TestOrchestrator_enqueue(t *testing.T) {
+	o := New(OrchestratorConfig{})
+	requested := make(chan string, 2)
+
+	assert.True(t, o.enqueue(requested, "mastodon.social"))
+	assert.True(t, o.enqueue(requested, "mastodon.online"))
+	// the queue is full, the domain is deferred
+	assert.False(t, o.enqueue(requested, "mastodon.xyz"))
+
+	assert.True(t, o.wasSeen("mastodon.social"))
+	assert.True(t, o.wasSeen("mastodon.xyz"))
+	assert.False(t, o.wasSeen("lemmy.ml"))
+	assert.Equal(t, 1, o.deferred)
+	assert.Len(t, requested, 2)
+
+	// a queued domain is not queued again
+	<-requested
+	assert.False(t, o.enqueue(requested, "mastodon.online"))
+	assert.Len(t, requested, 1)
+}
+
+func TestOrchestrator_enqueueCountsDeferredDomainsOnce(t *testing.T) {
+	o := New(OrchestratorConfig{})
+	requested := make(chan string, 1)
+
+	assert.True(t, o.enqueue(requested, "mastodon.social"))
+	// the domain is discovered again by another peer while the queue is still full
+	assert.False(t, o.enqueue(requested, "mastodon.xyz"))
+	assert.False(t, o.enqueue(requested, "mastodon.xyz"))
+	assert.Equal(t, 1, o.deferred)
+
+	// the deferred domain is left for a later run, even once the queue has room
+	<-requested
+	assert.False(t, o.enqueue(requested, "mastodon.xyz"))
+	assert.Equal(t, 1, o.deferred)
+	assert.Empty(t, requested)
 }
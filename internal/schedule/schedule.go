@@ -0,0 +1,103 @@
+// Package schedule decides when an instance should be crawled next.
+// Healthy instances are crawled at a regular interval, failing instances are retried soon
+// then backed off exponentially, and domains that do not resolve anymore are eventually tombstoned.
+package schedule
+
+import (
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+// Policy is the configuration of the schedule.
+// The zero value is not usable, see DefaultPolicy.
+type Policy struct {
+	// HealthyInterval is the interval between two crawls of a healthy instance
+	HealthyInterval time.Duration
+	// RetryInterval is the interval before retrying a failing instance
+	RetryInterval time.Duration
+	// FreshFailures is the number of failures retried at RetryInterval before backing off
+	FreshFailures int
+	// MaxBackoff is the maximum interval between two crawls of a failing instance
+	MaxBackoff time.Duration
+	// TombstoneAfter is the time after which a domain that cannot be resolved is not crawled anymore
+	TombstoneAfter time.Duration
+}
+
+// DefaultPolicy matches the defaults of the command line flags.
+var DefaultPolicy = Policy{
+	HealthyInterval: 6 * time.Hour,
+	RetryInterval:   time.Hour,
+	FreshFailures:   3,
+	MaxBackoff:      7 * 24 * time.Hour,
+	TombstoneAfter:  30 * 24 * time.Hour,
+}
+
+// State is the scheduling state of an instance before a crawl.
+type State struct {
+	ConsecutiveFailures int
+	// FailingSince is the start of the first failed crawl of the current streak.
+	// Zero if the last crawl succeeded.
+	FailingSince time.Time
+	// NotFoundSince is the start of the first crawl of the current streak of unresolved domains.
+	// Zero if the last crawl did not fail with domain_not_found.
+	NotFoundSince time.Time
+}
+
+// Decision is the scheduling state of an instance after a crawl.
+type Decision struct {
+	State
+
+	// NextCrawlAt is zero if the instance is tombstoned.
+	NextCrawlAt time.Time
+	Tombstoned  bool
+}
+
+// Next returns the scheduling decision following the crawl.
+func (p Policy) Next(state State, crawl models.Crawl) Decision {
+	if crawl.Err == nil {
+		return Decision{
+			NextCrawlAt: crawl.FinishedAt.Add(p.HealthyInterval),
+		}
+	}
+
+	d := Decision{
+		State: State{
+			ConsecutiveFailures: state.ConsecutiveFailures + 1,
+			FailingSince:        state.FailingSince,
+		},
+	}
+	if d.FailingSince.IsZero() {
+		d.FailingSince = crawl.StartedAt
+	}
+
+	if crawl.Err.Code == models.CrawlErrCodeDomainNotFound {
+		d.NotFoundSince = state.NotFoundSince
+		if d.NotFoundSince.IsZero() {
+			d.NotFoundSince = crawl.StartedAt
+		}
+		if crawl.StartedAt.Sub(d.NotFoundSince) >= p.TombstoneAfter {
+			d.Tombstoned = true
+			return d
+		}
+	}
+
+	d.NextCrawlAt = crawl.FinishedAt.Add(p.backoff(d.ConsecutiveFailures))
+	return d
+}
+
+// backoff returns the interval before the next crawl after the given number of consecutive failures.
+func (p Policy) backoff(failures int) time.Duration {
+	if failures <= p.FreshFailures {
+		return p.RetryInterval
+	}
+
+	backoff := p.RetryInterval
+	for i := p.FreshFailures; i < failures; i++ {
+		backoff *= 2
+		if backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Next(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	failed := func(code models.CrawlErrCode) models.Crawl {
+		return models.Crawl{
+			StartedAt:  now,
+			FinishedAt: now,
+			Status:     models.CrawlStatusFailed,
+			Err:        &models.CrawlError{Code: code},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		state State
+		crawl models.Crawl
+		want  Decision
+	}{
+		{
+			name: "healthy",
+			state: State{
+				ConsecutiveFailures: 5,
+				FailingSince:        now.Add(-time.Hour),
+			},
+			crawl: models.Crawl{StartedAt: now, FinishedAt: now, Status: models.CrawlStatusCompleted},
+			want: Decision{
+				NextCrawlAt: now.Add(DefaultPolicy.HealthyInterval),
+			},
+		},
+		{
+			name:  "first failure",
+			crawl: failed(models.CrawlErrCodeUnreachable),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 1, FailingSince: now},
+				NextCrawlAt: now.Add(DefaultPolicy.RetryInterval),
+			},
+		},
+		{
+			name:  "fresh failures",
+			state: State{ConsecutiveFailures: 2, FailingSince: now.Add(-2 * time.Hour)},
+			crawl: failed(models.CrawlErrCodeTimeout),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 3, FailingSince: now.Add(-2 * time.Hour)},
+				NextCrawlAt: now.Add(DefaultPolicy.RetryInterval),
+			},
+		},
+		{
+			name:  "backoff",
+			state: State{ConsecutiveFailures: 4, FailingSince: now.Add(-24 * time.Hour)},
+			crawl: failed(models.CrawlErrCodeUnreachable),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 5, FailingSince: now.Add(-24 * time.Hour)},
+				NextCrawlAt: now.Add(4 * DefaultPolicy.RetryInterval),
+			},
+		},
+		{
+			name:  "maximum backoff",
+			state: State{ConsecutiveFailures: 100, FailingSince: now.Add(-24 * time.Hour)},
+			crawl: failed(models.CrawlErrCodeUnreachable),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 101, FailingSince: now.Add(-24 * time.Hour)},
+				NextCrawlAt: now.Add(DefaultPolicy.MaxBackoff),
+			},
+		},
+		{
+			name:  "domain not found recently",
+			state: State{ConsecutiveFailures: 10, FailingSince: now.Add(-24 * time.Hour), NotFoundSince: now.Add(-24 * time.Hour)},
+			crawl: failed(models.CrawlErrCodeDomainNotFound),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 11, FailingSince: now.Add(-24 * time.Hour), NotFoundSince: now.Add(-24 * time.Hour)},
+				NextCrawlAt: now.Add(DefaultPolicy.MaxBackoff),
+			},
+		},
+		{
+			name:  "domain not found for too long",
+			state: State{ConsecutiveFailures: 10, FailingSince: now.Add(-DefaultPolicy.TombstoneAfter), NotFoundSince: now.Add(-DefaultPolicy.TombstoneAfter)},
+			crawl: failed(models.CrawlErrCodeDomainNotFound),
+			want: Decision{
+				State:      State{ConsecutiveFailures: 11, FailingSince: now.Add(-DefaultPolicy.TombstoneAfter), NotFoundSince: now.Add(-DefaultPolicy.TombstoneAfter)},
+				Tombstoned: true,
+			},
+		},
+		{
+			name:  "timeouts for too long, then domain not found",
+			state: State{ConsecutiveFailures: 10, FailingSince: now.Add(-DefaultPolicy.TombstoneAfter)},
+			crawl: failed(models.CrawlErrCodeDomainNotFound),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 11, FailingSince: now.Add(-DefaultPolicy.TombstoneAfter), NotFoundSince: now},
+				NextCrawlAt: now.Add(DefaultPolicy.MaxBackoff),
+			},
+		},
+		{
+			name:  "domain not found, then unreachable",
+			state: State{ConsecutiveFailures: 10, FailingSince: now.Add(-DefaultPolicy.TombstoneAfter), NotFoundSince: now.Add(-DefaultPolicy.TombstoneAfter)},
+			crawl: failed(models.CrawlErrCodeUnreachable),
+			want: Decision{
+				State:       State{ConsecutiveFailures: 11, FailingSince: now.Add(-DefaultPolicy.TombstoneAfter)},
+				NextCrawlAt: now.Add(DefaultPolicy.MaxBackoff),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultPolicy.Next(tt.state, tt.crawl)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
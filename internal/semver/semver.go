@@ -0,0 +1,191 @@
+// Package semver parses the software versions reported by the Fediverse instances.
+// They are mostly semantic versions, with the names of forks appended (eg: 4.2.0+glitch, 3.5.3-hometown)
+// and sometimes fewer components or trailing comments (eg: 2023.11, 2.7.2 (compatible; Pleroma 2.5.2)).
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned when a version cannot be parsed.
+var ErrInvalid = errors.New("invalid version")
+
+// preReleases are the first identifiers of the pre-releases,
+// the other alphabetic ones are the names of forks
+var preReleases = map[string]struct{}{
+	"alpha":    {},
+	"beta":     {},
+	"rc":       {},
+	"pre":      {},
+	"dev":      {},
+	"develop":  {},
+	"nightly":  {},
+	"snapshot": {},
+	"canary":   {},
+}
+
+// Version is a parsed software version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// PreRelease is the pre-release without the fork, eg: rc.1
+	PreRelease string
+	// Fork is the name of the fork, lowercased, eg: glitch
+	Fork string
+}
+
+// Parse parses a version.
+// The minor and patch numbers default to zero, a leading v is ignored,
+// as well as anything after the first space.
+func Parse(s string) (Version, error) {
+	raw := s
+	s, _, _ = strings.Cut(strings.TrimSpace(s), " ")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+
+	s, build, _ := strings.Cut(s, "+")
+	core, preRelease, _ := strings.Cut(s, "-")
+
+	var v Version
+	numbers := strings.Split(core, ".")
+	if len(numbers) > 3 {
+		return Version{}, fmt.Errorf("%w: %q has more than 3 numbers", ErrInvalid, raw)
+	}
+	for i, n := range numbers {
+		x, err := parseNumber(n)
+		if err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalid, raw)
+		}
+		switch i {
+		case 0:
+			v.Major = x
+		case 1:
+			v.Minor = x
+		case 2:
+			v.Patch = x
+		}
+	}
+
+	// the fork is in the build metadata (eg: +glitch), or in the pre-release (eg: -hometown)
+	if name := forkName(build); name != "" {
+		v.Fork = name
+		v.PreRelease = preRelease
+	} else if name := forkName(preRelease); name != "" {
+		v.Fork = name
+	} else {
+		v.PreRelease = preRelease
+	}
+
+	return v, nil
+}
+
+// parseNumber parses a version number, without sign nor leading zeros.
+func parseNumber(s string) (int, error) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, ErrInvalid
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalid
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+// forkName returns the name of the fork from a pre-release or build metadata,
+// eg: hometown for hometown-1.1.1. Returns an empty string if there is none.
+func forkName(s string) string {
+	first, _, _ := strings.Cut(s, ".")
+	name, _, _ := strings.Cut(first, "-")
+	name = strings.ToLower(name)
+	if name == "" {
+		return ""
+	}
+	for _, r := range name {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+	if _, ok := preReleases[name]; ok {
+		return ""
+	}
+	return name
+}
+
+// Core returns the version without the fork, eg: 4.3.0-rc.1
+func (v Version) Core() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	return s
+}
+
+// String returns the version with the fork as build metadata, eg: 4.2.0+glitch
+func (v Version) String() string {
+	if v.Fork == "" {
+		return v.Core()
+	}
+	return v.Core() + "+" + v.Fork
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than w.
+// The pre-releases are ordered as in semantic versioning, the forks by name after the original software.
+func Compare(v, w Version) int {
+	for _, d := range [...]int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	if c := comparePreReleases(v.PreRelease, w.PreRelease); c != 0 {
+		return c
+	}
+	return strings.Compare(v.Fork, w.Fork)
+}
+
+func comparePreReleases(a, b string) int {
+	// a release is greater than its pre-releases
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		// numeric identifiers are lower than alphanumeric ones
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(d int) int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
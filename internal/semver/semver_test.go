@@ -0,0 +1,88 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    Version
+		wantErr bool
+	}{
+		{"release", "4.2.1", Version{Major: 4, Minor: 2, Patch: 1}, false},
+		{"leading v", "v1.0.0", Version{Major: 1}, false},
+		{"missing patch", "2023.11", Version{Major: 2023, Minor: 11}, false},
+		{"pre-release", "4.3.0-alpha.1", Version{Major: 4, Minor: 3, PreRelease: "alpha.1"}, false},
+		{"build fork", "4.2.0+glitch", Version{Major: 4, Minor: 2, Fork: "glitch"}, false},
+		{"build fork with version", "4.0.2+hometown-1.1.1", Version{Major: 4, Patch: 2, Fork: "hometown"}, false},
+		{"pre-release fork", "3.5.3-hometown", Version{Major: 3, Minor: 5, Patch: 3, Fork: "hometown"}, false},
+		{"pre-release and fork", "4.3.0-rc.1+glitch", Version{Major: 4, Minor: 3, PreRelease: "rc.1", Fork: "glitch"}, false},
+		{"uppercase fork", "4.1.0+Chuckya", Version{Major: 4, Minor: 1, Fork: "chuckya"}, false},
+		{"git describe", "3.10.3-0-gd34adc0", Version{Major: 3, Minor: 10, Patch: 3, PreRelease: "0-gd34adc0"}, false},
+		{"numeric build", "1.2.3+20231019", Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{"comment", "2.7.2 (compatible; Pleroma 2.5.2)", Version{Major: 2, Minor: 7, Patch: 2}, false},
+		{"empty", "", Version{}, true},
+		{"not a version", "latest", Version{}, true},
+		{"leading zero", "04.2.1", Version{}, true},
+		{"too many numbers", "1.2.3.4", Version{}, true},
+		{"negative", "-1.0.0", Version{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.version)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalid)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersion_String(t *testing.T) {
+	v := Version{Major: 4, Minor: 3, PreRelease: "rc.1", Fork: "glitch"}
+	assert.Equal(t, "4.3.0-rc.1", v.Core())
+	assert.Equal(t, "4.3.0-rc.1+glitch", v.String())
+	assert.Equal(t, "4.2.1", Version{Major: 4, Minor: 2, Patch: 1}.String())
+}
+
+func TestCompare(t *testing.T) {
+	// in increasing order
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.0+glitch",
+		"1.0.0+hometown",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i := range versions {
+		for j := range versions {
+			v, err := Parse(versions[i])
+			require.NoError(t, err)
+			w, err := Parse(versions[j])
+			require.NoError(t, err)
+
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			assert.Equal(t, want, Compare(v, w), "%s and %s", versions[i], versions[j])
+		}
+	}
+}
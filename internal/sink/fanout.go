@@ -0,0 +1,67 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+// fanOutBuffer is the number of crawls buffered per sink,
+// so that a slow sink does not immediately slow down the others
+const fanOutBuffer = 100
+
+// FanOut sends every crawl to all of its sinks.
+type FanOut struct {
+	sinks []ResultSink
+	// lost counts the crawls discarded by the sinks that failed
+	lost atomic.Int64
+}
+
+func NewFanOut(sinks ...ResultSink) *FanOut {
+	return &FanOut{sinks: sinks}
+}
+
+// Run returns once every sink has returned, the crawls are consumed until the channel is closed.
+// A sink that fails stops receiving crawls, the others are not affected.
+func (f *FanOut) Run(ctx context.Context, crawls <-chan models.Crawl) error {
+	channels := make([]chan models.Crawl, len(f.sinks))
+	errs := make([]error, len(f.sinks))
+
+	var wg sync.WaitGroup
+	for i, s := range f.sinks {
+		ch := make(chan models.Crawl, fanOutBuffer)
+		channels[i] = ch
+
+		wg.Add(1)
+		go func(i int, s ResultSink) {
+			defer wg.Done()
+			errs[i] = s.Run(ctx, ch)
+			// the sink has stopped, discard its crawls
+			for range ch {
+				f.lost.Add(1)
+			}
+		}(i, s)
+	}
+
+	for crawl := range crawls {
+		for _, ch := range channels {
+			ch <- crawl
+		}
+	}
+
+	for _, ch := range channels {
+		close(ch)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Lost returns the number of crawls discarded by the sinks that failed.
+// A crawl discarded by several sinks is counted once per sink.
+func (f *FanOut) Lost() int {
+	return int(f.lost.Load())
+}
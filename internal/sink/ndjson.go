@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+// NDJSON writes the crawls as JSON, one crawl per line.
+// The format is the same as the one of the spool.
+type NDJSON struct {
+	w      io.Writer
+	closer io.Closer
+}
+
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{w: w}
+}
+
+// NewStdout writes the crawls to the standard output.
+func NewStdout() *NDJSON {
+	return NewNDJSON(os.Stdout)
+}
+
+// CreateNDJSON writes the crawls to a file, truncating it.
+// The file is closed once the crawls have been written.
+func CreateNDJSON(path string) (*NDJSON, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &NDJSON{w: f, closer: f}, nil
+}
+
+func (s *NDJSON) Run(ctx context.Context, crawls <-chan models.Crawl) (err error) {
+	buf := bufio.NewWriter(s.w)
+	defer func() {
+		err = errors.Join(err, buf.Flush())
+		if s.closer != nil {
+			err = errors.Join(err, s.closer.Close())
+		}
+	}()
+
+	enc := json.NewEncoder(buf)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case crawl, ok := <-crawls:
+			if !ok {
+				return nil
+			}
+			// nothing is written if the crawl cannot be encoded
+			if err := enc.Encode(crawl); err != nil {
+				slog.ErrorContext(ctx, "failed to encode crawl, skipping", "domain", crawl.Domain, "error", err)
+			}
+		}
+	}
+}
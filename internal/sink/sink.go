@@ -0,0 +1,61 @@
+// Package sink contains the destinations of the results of a crawl.
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+// ResultSink receives the results of a crawl.
+type ResultSink interface {
+	// Run consumes the crawls until the channel is closed, or until the context is done.
+	Run(ctx context.Context, crawls <-chan models.Crawl) error
+}
+
+type Kind string
+
+const (
+	KindPostgres Kind = "postgres"
+	KindNDJSON   Kind = "ndjson"
+	KindStdout   Kind = "stdout"
+)
+
+var ErrInvalidSpec = errors.New("invalid sink")
+
+// Spec describes a sink on the command line, eg: postgres, stdout or ndjson:out.jsonl
+type Spec struct {
+	Kind Kind
+	// Path is only set for the ndjson sink
+	Path string
+}
+
+// ParseSpec parses the description of a sink.
+func ParseSpec(s string) (Spec, error) {
+	kind, path, hasPath := strings.Cut(strings.TrimSpace(s), ":")
+
+	switch Kind(kind) {
+	case KindPostgres, KindStdout:
+		if hasPath {
+			return Spec{}, fmt.Errorf("%w: %s does not take a path: %q", ErrInvalidSpec, kind, s)
+		}
+		return Spec{Kind: Kind(kind)}, nil
+	case KindNDJSON:
+		if path == "" {
+			return Spec{}, fmt.Errorf("%w: expected ndjson:<path>: %q", ErrInvalidSpec, s)
+		}
+		return Spec{Kind: KindNDJSON, Path: path}, nil
+	default:
+		return Spec{}, fmt.Errorf("%w: unknown sink %q, expected postgres, stdout or ndjson:<path>", ErrInvalidSpec, kind)
+	}
+}
+
+func (s Spec) String() string {
+	if s.Path != "" {
+		return string(s.Kind) + ":" + s.Path
+	}
+	return string(s.Kind)
+}
@@ -0,0 +1,138 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/spool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    Spec
+		wantErr bool
+	}{
+		{name: "postgres", spec: "postgres", want: Spec{Kind: KindPostgres}},
+		{name: "stdout", spec: "stdout", want: Spec{Kind: KindStdout}},
+		{name: "ndjson", spec: "ndjson:out.jsonl", want: Spec{Kind: KindNDJSON, Path: "out.jsonl"}},
+		{name: "ndjson with colons in the path", spec: "ndjson:C:/out.jsonl", want: Spec{Kind: KindNDJSON, Path: "C:/out.jsonl"}},
+		{name: "ndjson without path", spec: "ndjson", wantErr: true},
+		{name: "ndjson with empty path", spec: "ndjson:", wantErr: true},
+		{name: "postgres with path", spec: "postgres:db", wantErr: true},
+		{name: "unknown", spec: "kafka", wantErr: true},
+		{name: "empty", spec: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpec(tt.spec)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidSpec)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.spec, got.String())
+		})
+	}
+}
+
+func testCrawls() []models.Crawl {
+	startedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	return []models.Crawl{
+		{
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(time.Second),
+			Domain:     "mastodon.social",
+			Status:     models.CrawlStatusCompleted,
+			Peers:      []string{"mastodon.online"},
+		},
+		{
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(time.Minute),
+			Domain:     "lemmy.ml",
+			Status:     models.CrawlStatusFailed,
+			Err: &models.CrawlError{
+				Msg:  "context deadline exceeded",
+				Code: models.CrawlErrCodeTimeout,
+			},
+		},
+	}
+}
+
+func send(crawls []models.Crawl) <-chan models.Crawl {
+	ch := make(chan models.Crawl, len(crawls))
+	for _, crawl := range crawls {
+		ch <- crawl
+	}
+	close(ch)
+	return ch
+}
+
+func TestNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jsonl")
+	s, err := CreateNDJSON(path)
+	require.NoError(t, err)
+
+	crawls := testCrawls()
+	require.NoError(t, s.Run(context.Background(), send(crawls)))
+
+	// the output can be replayed like the spool
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var got []models.Crawl
+	require.NoError(t, spool.Read(f, func(c models.Crawl) error {
+		got = append(got, c)
+		return nil
+	}))
+	assert.Equal(t, crawls, got)
+}
+
+func TestNDJSON_SkipsTheCrawlsThatCannotBeEncoded(t *testing.T) {
+	var buf bytes.Buffer
+	crawls := testCrawls()
+	invalid := crawls[0]
+	invalid.Domain = "invalid.example.com"
+	invalid.RawNodeinfo = []byte("<html>")
+
+	err := NewNDJSON(&buf).Run(context.Background(), send([]models.Crawl{crawls[0], invalid, crawls[1]}))
+	require.NoError(t, err)
+
+	var got []models.Crawl
+	require.NoError(t, spool.Read(&buf, func(c models.Crawl) error {
+		got = append(got, c)
+		return nil
+	}))
+	assert.Equal(t, crawls, got)
+}
+
+type failingSink struct{}
+
+func (failingSink) Run(ctx context.Context, crawls <-chan models.Crawl) error {
+	return errors.New("failed")
+}
+
+func TestFanOut(t *testing.T) {
+	var a, b bytes.Buffer
+	s := NewFanOut(NewNDJSON(&a), failingSink{}, NewNDJSON(&b))
+
+	err := s.Run(context.Background(), send(testCrawls()))
+	assert.EqualError(t, err, "failed")
+
+	// the failing sink does not prevent the others from receiving the crawls
+	assert.Equal(t, 2, bytes.Count(a.Bytes(), []byte("\n")))
+	assert.Equal(t, a.String(), b.String())
+	// the crawls sent to the failing sink are lost
+	assert.Equal(t, 2, s.Lost())
+}
@@ -0,0 +1,78 @@
+// Package spool stores the crawls that could not be written to the database
+// in a local NDJSON file, one crawl per line, so that they can be replayed later.
+package spool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+)
+
+// Writer appends crawls to a spool file.
+// It is safe for concurrent use.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Open opens the spool file for appending, creating it if needed.
+func Open(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: f}, nil
+}
+
+// Write appends the crawl to the spool.
+// The crawl is on disk once Write returns.
+func (w *Writer) Write(crawl models.Crawl) error {
+	line, err := json.Marshal(crawl)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// a single write so that lines are not interleaved
+	if _, err := w.file.Write(line); err != nil {
+		return err
+	}
+	return w.file.Sync()
+}
+
+// Path returns the path of the spool file.
+func (w *Writer) Path() string {
+	return w.file.Name()
+}
+
+func (w *Writer) Close() error {
+	return w.file.Close()
+}
+
+// Read calls fn for every crawl of the spool, in order.
+// It stops at the first error returned by fn.
+func Read(r io.Reader, fn func(models.Crawl) error) error {
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var crawl models.Crawl
+		err := dec.Decode(&crawl)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("record %d: %w", line, err)
+		}
+
+		if err := fn(crawl); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package spool
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyclimse/fediverse-blahaj/internal/crawler"
+	"github.com/cyclimse/fediverse-blahaj/internal/models"
+	"github.com/cyclimse/fediverse-blahaj/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crawls.ndjson")
+
+	startedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	crawls := []models.Crawl{
+		{
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(time.Second),
+			RunID:      uuid.New(),
+			Domain:     "mastodon.social",
+			Addresses:  []netip.Addr{netip.MustParseAddr("2001:db8::1")},
+			Status:     models.CrawlStatusCompleted,
+
+			Peers:          []string{"mastodon.online", "lemmy.ml"},
+			NumberOfPeers:  utils.ValToPtr(int32(3), true),
+			PeersTruncated: true,
+			MaxPeers:       2,
+
+			SoftwareName: utils.ValToPtr("mastodon", true),
+			TotalUsers:   utils.ValToPtr(int32(42), true),
+
+			RawNodeinfo: []byte(`{"version":"2.0"}`),
+		},
+		{
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(time.Minute),
+			Domain:     "lemmy.ml",
+			Status:     models.CrawlStatusFailed,
+			Err: &models.CrawlError{
+				Msg:  "context deadline exceeded",
+				Code: models.CrawlErrCodeTimeout,
+			},
+		},
+	}
+
+	w, err := Open(path)
+	require.NoError(t, err)
+	for _, crawl := range crawls {
+		require.NoError(t, w.Write(crawl))
+	}
+	require.NoError(t, w.Close())
+
+	// appends to the existing spool
+	w, err = Open(path)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(crawls[0]))
+	require.NoError(t, w.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var got []models.Crawl
+	err = Read(f, func(c models.Crawl) error {
+		got = append(got, c)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, crawls[0], got[0])
+	assert.Equal(t, crawls[1], got[1])
+	assert.Equal(t, crawls[0], got[2])
+}
+
+func TestWrite_NodeinfoThatIsNotJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crawls.ndjson")
+
+	// an instance serving an HTML page at its nodeinfo URL
+	crawl := crawler.CrawlFromResult(crawler.CrawlResult{
+		Domain:      "mastodon.social",
+		Err:         errors.New("invalid character '<' looking for beginning of value"),
+		ErrCode:     models.CrawlErrCodeInvalidNodeinfo,
+		RawNodeinfo: []byte("<html><body>Not Found</body></html>"),
+	})
+
+	w, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(crawl))
+	require.NoError(t, w.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var got []models.Crawl
+	require.NoError(t, Read(f, func(c models.Crawl) error {
+		got = append(got, c)
+		return nil
+	}))
+	require.Len(t, got, 1)
+	assert.Equal(t, "mastodon.social", got[0].Domain)
+	assert.Equal(t, models.CrawlErrCodeInvalidNodeinfo, got[0].Err.Code)
+	assert.Nil(t, got[0].RawNodeinfo)
+}
+
+func TestRead_InvalidRecord(t *testing.T) {
+	r := strings.NewReader(`{"domain":"mastodon.social"}` + "\n" + `{"domain":` + "\n")
+
+	n := 0
+	err := Read(r, func(models.Crawl) error {
+		n++
+		return nil
+	})
+	assert.ErrorContains(t, err, "record 2")
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"sqlc query", "-- name: GetInstanceByID :one\nSELECT * FROM instance WHERE id = $1", "GetInstanceByID"},
+		{"sqlc exec", "-- name: DeleteInstanceByID :exec\nDELETE FROM instance WHERE id = $1", "DeleteInstanceByID"},
+		{"raw query", "SELECT 1", "query"},
+		{"empty name", "-- name: ", "query"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, queryName(tt.sql))
+		})
+	}
+}
+
+func TestQueryTracer_Batch(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := &QueryTracer{tracer: provider.Tracer("test")}
+
+	batch := &pgx.Batch{}
+	batch.Queue("-- name: CreateCrawl :batchone\nINSERT INTO crawl DEFAULT VALUES")
+	batch.Queue("-- name: CreateCrawl :batchone\nINSERT INTO crawl DEFAULT VALUES")
+
+	ctx := tracer.TraceBatchStart(context.Background(), nil, pgx.TraceBatchStartData{Batch: batch})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{
+		SQL:        "-- name: CreateCrawl :batchone\nINSERT INTO crawl DEFAULT VALUES",
+		CommandTag: pgconn.NewCommandTag("INSERT 0 1"),
+	})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{
+		SQL: "-- name: CreateCrawl :batchone\nINSERT INTO crawl DEFAULT VALUES",
+		Err: errors.New("duplicate key"),
+	})
+	tracer.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{Err: errors.New("duplicate key")})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "db.batch", span.Name())
+	assert.Contains(t, span.Attributes(), attribute.Int("db.batch.size", 2))
+	assert.Equal(t, codes.Error, span.Status().Code)
+
+	// one event per query, then the error of the batch
+	require.Len(t, span.Events(), 3)
+	assert.Equal(t, "db.CreateCrawl", span.Events()[0].Name)
+	assert.Equal(t, "exception", span.Events()[1].Name)
+	assert.Equal(t, "exception", span.Events()[2].Name)
+}
@@ -0,0 +1,68 @@
+// Package telemetry configures the OpenTelemetry tracing of the crawler and the API.
+// Packages create their spans with the global tracer provider,
+// which does nothing until Setup is called with an exporter.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+type Exporter string
+
+const (
+	ExporterNone Exporter = "none"
+	ExporterOTLP Exporter = "otlp"
+)
+
+// Config contains the configuration of the tracing.
+// The OTLP exporter is configured with the standard OTEL_EXPORTER_OTLP_* environment variables.
+type Config struct {
+	TracesExporter    Exporter `help:"Exporter for the traces." enum:"none,otlp" default:"none" env:"OTEL_TRACES_EXPORTER"`
+	TracesSampleRatio float64  `help:"Ratio of the traces to sample." default:"1" env:"OTEL_TRACES_SAMPLER_ARG"`
+}
+
+// Setup installs the global tracer provider and propagator.
+// The returned function flushes the pending spans and must be called before exiting.
+func Setup(ctx context.Context, cfg Config, serviceName, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	switch cfg.TracesExporter {
+	case ExporterNone, "":
+		// the global tracer provider is a no-op by default
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+	default:
+		return nil, fmt.Errorf("unknown traces exporter: %q", cfg.TracesExporter)
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracesSampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
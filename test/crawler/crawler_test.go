@@ -12,7 +12,7 @@ import (
 )
 
 func TestGetPeersMastodon(t *testing.T) {
-	c := crawler.New("test")
+	c := crawler.New(crawler.Config{UserAgent: "test"})
 	nodeInfo := nodeinfo.Nodeinfo{
 		Software: nodeinfo.NodeinfoSoftware{
 			Name: "mastodon",
@@ -22,5 +22,5 @@ func TestGetPeersMastodon(t *testing.T) {
 	peers, code, err := c.GetPeersMastodon(context.Background(), "https://mastodon.social", &nodeInfo)
 	require.NoError(t, err)
 	assert.Equal(t, code, models.CrawlErrCodeUnknown)
-	assert.Greater(t, len(peers), 100)
+	assert.Greater(t, len(peers.Domains), 100)
 }